@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"ble-radar.klederson.com/internal/bluetooth"
+	"github.com/spf13/cobra"
+)
+
+func newAdaptersCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "adapters",
+		Short: "List Bluetooth controllers with their address and power state",
+		Long: `Queries BlueZ for the available HCI controllers. The one selected with
+--adapter (or scanner.adapter in the config file) is marked with *.`,
+		Args: cobra.NoArgs,
+		RunE: runAdapters,
+	}
+}
+
+func runAdapters(cmd *cobra.Command, args []string) error {
+	adapters, err := bluetooth.ListAdapters()
+	if err != nil {
+		return err
+	}
+	if len(adapters) == 0 {
+		fmt.Fprintln(os.Stderr, "No Bluetooth controllers found.")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  ADAPTER\tADDRESS\tPOWER\tNAME")
+	for _, a := range adapters {
+		mark := " "
+		if a.ID == cfg.Scanner.Adapter {
+			mark = "*"
+		}
+		power := "off"
+		if a.Powered {
+			power = "on"
+		}
+		fmt.Fprintf(tw, "%s %s\t%s\t%s\t%s\n", mark, a.ID, a.Address, power, a.Name)
+	}
+	return tw.Flush()
+}
@@ -1,12 +1,18 @@
 package app
 
 import (
+	"context"
+	"fmt"
 	"strings"
 	"time"
 
 	"ble-radar.klederson.com/internal/bluetooth"
+	"ble-radar.klederson.com/internal/calibration"
 	"ble-radar.klederson.com/internal/config"
+	"ble-radar.klederson.com/internal/localization"
 	"ble-radar.klederson.com/internal/radar"
+	"ble-radar.klederson.com/internal/survey"
+	"ble-radar.klederson.com/internal/tracking"
 	"ble-radar.klederson.com/internal/ui"
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -15,15 +21,32 @@ import (
 // Because Bubble Tea uses value receivers, pointer fields ensure all copies
 // see the same underlying data.
 type shared struct {
-	store          *bluetooth.DeviceStore
-	sweep          *radar.Sweep
-	bleScanner     *bluetooth.BLEScanner
-	classicScanner *bluetooth.ClassicScanner
-	wifiScanner    *bluetooth.WiFiScanner
-	mockScanner    *bluetooth.MockScanner
-	resolver       *bluetooth.NameResolver
-	hiddenDevices  map[string]bool
-	rssiHistory    map[string]*RSSIRing
+	store         *bluetooth.DeviceStore
+	sweep         *radar.Sweep
+	scanners      []bluetooth.Scanner
+	cancelScan    context.CancelFunc
+	resolver      *bluetooth.NameResolver
+	hiddenDevices map[string]bool
+	rssiHistory   map[string]*RSSIRing
+	rawHistory    map[string]*RSSIRing
+	sensorHistory map[string]*SensorHistory
+	profiles      *calibration.Profiles
+	profilesPath  string
+	trackers      *tracking.Monitor
+}
+
+// Options configures a new AppModel.
+type Options struct {
+	Config          *config.Config
+	Demo            bool     // No real hardware: skips name resolution
+	Scanners        []string // Scanner names from the registry; empty selects defaults
+	ScannerOptions  bluetooth.ScannerOptions
+	Recorder        *bluetooth.Recorder         // Optional session recorder
+	Smoothing       bluetooth.SmootherFactory   // RSSI filter for each device
+	Calibration     *calibration.Profiles       // Loaded calibration profiles, may be nil
+	CalibrationPath string                      // Where the wizard saves profiles
+	Identities      *bluetooth.IdentityResolver // IRKs of known devices, may be nil
+	Vendors         bluetooth.VendorLookup      // OUI database, may be nil
 }
 
 // AppModel is the root Bubble Tea model for BLE Radar.
@@ -34,11 +57,36 @@ type AppModel struct {
 	scanning    bool
 	demoMode    bool
 	adapter     string
+	cfg         *config.Config
+	scanOpts    Options
 	cursorIndex int
 	selectedMAC string
 	detailOpen  bool
+	advOpen     bool // Advertisement section of the detail panel is expanded
 	isolateMAC  string
 
+	// Radar scale in meters; autoRange refits it to the visible devices
+	maxRange  float64
+	autoRange bool
+
+	// Bearings come from multi-adapter localization rather than MAC hashes
+	localized bool
+
+	// Calibration wizard, nil when not calibrating
+	calib        *calibration.Wizard
+	calibStatus  string
+	calibAdapter string // Adapter sampled, "" for adapter-less sources
+
+	// Fox hunt for the isolated device, nil when not hunting
+	fox        *localization.FoxHunt
+	foxStatus  string
+	foxAdapter string // Adapter sampled, "" for adapter-less sources
+
+	// Trilateration survey of one device, nil when not surveying
+	survey        *survey.Form
+	surveyStatus  string
+	surveyAdapter string // Adapter sampled, "" for adapter-less sources
+
 	// Filter state
 	filterBLE     bool
 	filterClassic bool
@@ -54,28 +102,57 @@ type AppModel struct {
 }
 
 // New creates a new AppModel.
-func New(demoMode bool, adapter string) AppModel {
+func New(opts Options) AppModel {
+	cfg := opts.Config
+	store := bluetooth.NewDeviceStore(bluetooth.PathLoss{
+		MeasuredPower: cfg.Distance.MeasuredPower,
+		Exponent:      cfg.Distance.PathLossExp,
+	}, opts.Smoothing)
+	profiles := opts.Calibration
+	if profiles == nil {
+		profiles = &calibration.Profiles{}
+	}
+	profiles.Apply(store)
+	locator, localized := localization.FromConfig(cfg.Localization)
+	if localized {
+		store.SetLocalizer(locator)
+	}
+	if opts.Identities != nil {
+		store.SetIdentities(opts.Identities)
+	}
+	if opts.Vendors != nil {
+		store.SetVendors(opts.Vendors)
+	}
 	return AppModel{
 		scanning:      true,
-		demoMode:      demoMode,
-		adapter:       adapter,
+		demoMode:      opts.Demo,
+		adapter:       strings.Join(opts.ScannerOptions.BLEAdapters(), ","),
+		cfg:           cfg,
+		scanOpts:      opts,
+		maxRange:      cfg.Radar.MaxRange,
+		localized:     localized,
 		filterBLE:     true,
 		filterClassic: true,
 		filterWiFi:    true,
 		shared: &shared{
-			store:         bluetooth.NewDeviceStore(),
-			sweep:         radar.NewSweep(),
-			resolver:      bluetooth.NewNameResolver(),
+			store:         store,
+			sweep:         radar.NewSweep(cfg.Radar.SweepSpeedRPM, cfg.Radar.SweepTrailDeg),
+			resolver:      bluetooth.NewNameResolver(opts.ScannerOptions.Adapter),
 			hiddenDevices: make(map[string]bool),
 			rssiHistory:   make(map[string]*RSSIRing),
+			rawHistory:    make(map[string]*RSSIRing),
+			sensorHistory: make(map[string]*SensorHistory),
+			profiles:      profiles,
+			profilesPath:  opts.CalibrationPath,
+			trackers:      tracking.NewMonitor(tracking.ThresholdsFromConfig(cfg.Trackers)),
 		},
 	}
 }
 
 func (m AppModel) Init() tea.Cmd {
 	return tea.Batch(
-		tickCmd(),
-		evictCmd(),
+		tickCmd(m.cfg.Radar.TargetFPS),
+		evictCmd(m.cfg.Devices.EvictInterval),
 	)
 }
 
@@ -93,22 +170,22 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.shared.sweep.Update()
 		m.devices = m.shared.store.Snapshot()
 		m.filteredView = m.filteredDevices()
+		if m.autoRange {
+			m.maxRange = radar.FitRange(m.visibleDevices())
+		}
 
-		// Record RSSI history
+		// Record filtered and raw RSSI history
 		for _, d := range m.devices {
-			ring, ok := m.shared.rssiHistory[d.MAC]
-			if !ok {
-				ring = NewRSSIRing(60)
-				m.shared.rssiHistory[d.MAC] = ring
-			}
-			ring.Push(d.RSSI)
+			pushHistory(m.shared.rssiHistory, d.MAC, d.RSSI)
+			pushHistory(m.shared.rawHistory, d.MAC, d.RawRSSI)
+			pushSensorHistory(m.shared.sensorHistory, d)
 		}
 
 		// Request name resolution for unnamed devices (real mode only)
 		if !m.demoMode {
 			for _, d := range m.devices {
-				if d.Name == "" && m.shared.resolver.ShouldResolve(d.MAC) {
-					m.shared.resolver.RequestResolve(d.MAC)
+				if d.Name == "" && m.shared.resolver.ShouldResolve(d.Address) {
+					m.shared.resolver.RequestResolve(d.Address)
 				}
 			}
 		}
@@ -135,13 +212,14 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.detailOpen = false
 		}
 
-		return m, tickCmd()
+		return m, tickCmd(m.cfg.Radar.TargetFPS)
 
 	case EvictMsg:
-		m.shared.store.Evict(config.DeviceTimeout)
+		m.shared.store.Evict(m.cfg.Devices.Timeout)
 
 		// Clean up stale entries
 		snap := m.shared.store.Snapshot()
+		m.shared.trackers.Observe(snap, m.shared.store.Now())
 		active := make(map[string]bool, len(snap))
 		for _, d := range snap {
 			active[d.MAC] = true
@@ -149,6 +227,12 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		for mac := range m.shared.rssiHistory {
 			if !active[mac] {
 				delete(m.shared.rssiHistory, mac)
+				delete(m.shared.rawHistory, mac)
+			}
+		}
+		for mac := range m.shared.sensorHistory {
+			if !active[mac] {
+				delete(m.shared.sensorHistory, mac)
 			}
 		}
 		for mac := range m.shared.hiddenDevices {
@@ -160,11 +244,24 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.isolateMAC = ""
 		}
 
-		return m, evictCmd()
+		return m, evictCmd(m.cfg.Devices.EvictInterval)
 
 	case bluetooth.DeviceDiscoveredMsg:
 		if m.scanning {
-			m.shared.store.Upsert(msg.MAC, msg.Name, float64(msg.RSSI), msg.Type, msg.Frequency, msg.Channel)
+			if !msg.At.IsZero() {
+				// Replayed: evict on the recorded clock, whatever the speed.
+				m.shared.store.Advance(msg.At, m.cfg.Devices.Timeout)
+			}
+			m.shared.store.Upsert(msg)
+			if m.calib != nil && m.sampleOf(msg, m.calib.MAC, m.calibAdapter) {
+				m.calib.Add(float64(msg.RSSI))
+			}
+			if m.fox != nil && m.sampleOf(msg, m.fox.MAC, m.foxAdapter) {
+				m.fox.Add(time.Now(), float64(msg.RSSI))
+			}
+			if m.survey != nil && m.sampleOf(msg, m.survey.Survey.MAC, m.surveyAdapter) {
+				m.survey.Add(float64(msg.RSSI))
+			}
 		}
 		return m, nil
 
@@ -175,7 +272,27 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// sampleOf reports whether msg is a reading of the device stored under key,
+// taken by adapter unless adapter is empty. Rotated and linked addresses
+// resolve to the key they were merged into.
+func (m AppModel) sampleOf(msg bluetooth.DeviceDiscoveredMsg, key, adapter string) bool {
+	if adapter != "" && msg.Adapter != adapter {
+		return false
+	}
+	d, ok := m.shared.store.Get(msg.MAC)
+	return ok && d.MAC == key
+}
+
 func (m AppModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.calib != nil {
+		return m.handleKeyCalibration(msg)
+	}
+	if m.fox != nil {
+		return m.handleKeyFoxHunt(msg)
+	}
+	if m.survey != nil {
+		return m.handleKeySurvey(msg)
+	}
 	if m.filterActive {
 		return m.handleKeyFilter(msg)
 	}
@@ -262,6 +379,62 @@ func (m AppModel) handleKeyNormal(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	case "/":
 		m.filterActive = true
+
+	case "+", "=":
+		m.autoRange = false
+		m.maxRange = radar.ZoomIn(m.maxRange)
+
+	case "-", "_":
+		m.autoRange = false
+		m.maxRange = radar.ZoomOut(m.maxRange)
+
+	case "a", "A":
+		// Toggle auto-fit to the farthest visible device
+		m.autoRange = !m.autoRange
+		if m.autoRange {
+			m.maxRange = radar.FitRange(m.visibleDevices())
+		}
+
+	case "c", "C":
+		// Start the distance calibration wizard for the selected device
+		if len(m.filteredView) > 0 && m.cursorIndex < len(m.filteredView) {
+			d := m.filteredView[m.cursorIndex]
+			m.calib = calibration.NewWizard(d.MAC, d.KnownName(), m.cfg.Calibration.Samples)
+			m.calibStatus = ""
+			m.calibAdapter = d.BestAdapter
+			m.detailOpen = false
+		}
+
+	case "f", "F":
+		// Fox hunt: find the bearing of the isolated (or selected) device
+		if len(m.filteredView) > 0 && m.cursorIndex < len(m.filteredView) {
+			d := m.filteredView[m.cursorIndex]
+			if m.isolateMAC != "" {
+				if iso, ok := m.shared.store.Get(m.isolateMAC); ok {
+					d = iso
+				}
+			}
+			m.isolateMAC = d.MAC
+			m.fox = localization.NewFoxHunt(d.MAC, d.KnownName())
+			m.foxStatus = ""
+			m.foxAdapter = d.BestAdapter
+			m.detailOpen = false
+		}
+
+	case "t", "T":
+		// Trilaterate the selected device from RSSI taken at known spots
+		if len(m.filteredView) > 0 && m.cursorIndex < len(m.filteredView) {
+			m.startSurvey(m.filteredView[m.cursorIndex])
+			m.detailOpen = false
+		}
+
+	case "n", "N":
+		// Advance a stepped replay by one entry
+		for _, s := range m.shared.scanners {
+			if st, ok := s.(bluetooth.Stepper); ok {
+				st.Step()
+			}
+		}
 	}
 
 	return m, nil
@@ -276,6 +449,9 @@ func (m AppModel) handleKeyDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "esc", "enter":
 		m.detailOpen = false
 
+	case "a", "A":
+		m.advOpen = !m.advOpen
+
 	case "up", "k":
 		if m.cursorIndex > 0 {
 			m.cursorIndex--
@@ -361,27 +537,46 @@ func (m AppModel) View() string {
 		radarW = m.width - listW
 	}
 
-	menuBar := ui.RenderMenuBar(m.width, m.adapter, m.scanning, m.detailOpen, m.filterActive)
+	menuBar := ui.RenderMenuBar(m.width, m.adapter, m.scanning, m.detailOpen, m.filterActive, m.calib != nil, m.fox != nil, m.survey != nil)
 
 	var leftPanel string
-	if m.detailOpen && m.cursorIndex >= 0 && m.cursorIndex < len(m.filteredView) {
+	if m.calib != nil {
+		leftPanel = ui.RenderCalibrationPanel(m.calib, radarW, bodyH, m.calibStatus)
+	} else if m.fox != nil {
+		leftPanel = ui.RenderFoxHuntPanel(m.fox, radarW, bodyH, time.Now(), m.foxStatus)
+	} else if m.survey != nil {
+		leftPanel = ui.RenderSurveyPanel(m.survey, radarW, bodyH, m.surveyStatus)
+	} else if m.detailOpen && m.cursorIndex >= 0 && m.cursorIndex < len(m.filteredView) {
 		d := m.filteredView[m.cursorIndex]
-		var history []float64
+		var history, raw []float64
 		if ring, ok := m.shared.rssiHistory[d.MAC]; ok {
 			history = ring.Values()
 		}
-		leftPanel = ui.RenderDetailPanel(d, radarW, bodyH, history)
+		if ring, ok := m.shared.rawHistory[d.MAC]; ok {
+			raw = ring.Values()
+		}
+		var track *tracking.Track
+		if t, ok := m.shared.trackers.Track(d.MAC); ok {
+			track = &t
+		}
+		var sensor map[bluetooth.Quantity][]float64
+		if h, ok := m.shared.sensorHistory[d.MAC]; ok {
+			sensor = h.Values()
+		}
+		leftPanel = ui.RenderDetailPanel(d, radarW, bodyH, history, raw, track, sensor, m.advOpen)
 	} else {
 		innerW := radarW - 4
-		innerH := bodyH - 4
 		if innerW < 5 {
 			innerW = 5
 		}
+		devices := m.visibleDevices()
+		legend := radar.RenderLegend(innerW, m.localized, devices)
+		// The class glyph line, if any, takes a radar row.
+		innerH := bodyH - 4 - strings.Count(legend, "\n")
 		if innerH < 3 {
 			innerH = 3
 		}
-		radarContent := radar.Render(innerW, innerH, m.visibleDevices(), m.shared.sweep)
-		legend := radar.RenderLegend(innerW)
+		radarContent := radar.Render(innerW, innerH, devices, m.shared.sweep, m.radarSettings())
 		leftPanel = ui.RenderRadarPanel(radarW, bodyH, radarContent, legend)
 	}
 
@@ -392,42 +587,45 @@ func (m AppModel) View() string {
 		Search:  m.filterSearch,
 		Active:  m.filterActive,
 	}
-	deviceList := ui.RenderDeviceList(m.filteredView, listW, bodyH, m.cursorIndex, m.shared.hiddenDevices, m.isolateMAC, filter)
+	alerts := m.shared.trackers.Alerts(m.shared.store.Now())
+	following := make(map[string]bool)
+	for _, t := range alerts {
+		for _, mac := range t.MACs {
+			following[mac] = true
+		}
+	}
+	deviceList := ui.RenderDeviceList(m.filteredView, listW, bodyH, m.cursorIndex, m.shared.hiddenDevices, m.isolateMAC, following, filter)
 
 	total := m.shared.store.Count()
 	ble, classic, wifi := m.shared.store.CountByType()
 	statusBar := ui.RenderStatusBar(m.width, m.scanning, total, ble, classic, wifi,
-		m.shared.sweep.Degrees(), config.MaxRange)
+		m.shared.sweep.Degrees(), m.maxRange, m.autoRange, trackerAlert(alerts))
 
 	return ui.ComposeLayout(menuBar, leftPanel, deviceList, statusBar, m.width)
 }
 
-// StartScanners initializes and starts scanners. Must be called before p.Run().
-func (m *AppModel) StartScanners(p *tea.Program) error {
-	m.shared.resolver.Start(p)
-
-	if m.demoMode {
-		m.shared.mockScanner = bluetooth.NewMockScanner()
-		return m.shared.mockScanner.Start(p)
+// StartScanners builds the selected scanners from the registry and starts
+// them, delivering results to sink. Must be called before p.Run().
+func (m *AppModel) StartScanners(sink bluetooth.Sink) error {
+	if m.scanOpts.Recorder != nil {
+		sink = m.scanOpts.Recorder.Wrap(sink)
 	}
+	m.shared.resolver.Start(sink)
 
-	m.shared.bleScanner = bluetooth.NewBLEScanner()
-	if err := m.shared.bleScanner.Start(p); err != nil {
+	scanners, err := bluetooth.NewScanners(m.scanOpts.Scanners, m.scanOpts.ScannerOptions)
+	if err != nil {
 		return err
 	}
 
-	if bluetooth.ClassicScannerAvailable() {
-		m.shared.classicScanner = bluetooth.NewClassicScanner(
-			time.Duration(config.ClassicScanSec) * time.Second)
-		_ = m.shared.classicScanner.Start(p)
-	}
-
-	if bluetooth.WiFiScannerAvailable() {
-		m.shared.wifiScanner = bluetooth.NewWiFiScanner("",
-			time.Duration(config.WiFiScanSec)*time.Second)
-		_ = m.shared.wifiScanner.Start(p)
+	ctx, cancel := context.WithCancel(context.Background())
+	m.shared.cancelScan = cancel
+	for _, s := range scanners {
+		if err := s.Start(ctx, sink); err != nil {
+			cancel()
+			return err
+		}
+		m.shared.scanners = append(m.shared.scanners, s)
 	}
-
 	return nil
 }
 
@@ -435,17 +633,11 @@ func (m *AppModel) stopScanners() {
 	if m.shared.resolver != nil {
 		m.shared.resolver.Stop()
 	}
-	if m.shared.mockScanner != nil {
-		m.shared.mockScanner.Stop()
-	}
-	if m.shared.bleScanner != nil {
-		m.shared.bleScanner.Stop()
+	for _, s := range m.shared.scanners {
+		s.Stop()
 	}
-	if m.shared.classicScanner != nil {
-		m.shared.classicScanner.Stop()
-	}
-	if m.shared.wifiScanner != nil {
-		m.shared.wifiScanner.Stop()
+	if m.shared.cancelScan != nil {
+		m.shared.cancelScan()
 	}
 }
 
@@ -471,8 +663,8 @@ func (m AppModel) filteredDevices() []*bluetooth.Device {
 		}
 		// Text search
 		if search != "" {
-			nameLower := strings.ToLower(d.Name)
-			macLower := strings.ToLower(d.MAC)
+			nameLower := strings.ToLower(d.Name + " " + d.Identity + " " + d.Vendor)
+			macLower := strings.ToLower(strings.Join(d.Addresses, " "))
 			if !strings.Contains(nameLower, search) && !strings.Contains(macLower, search) {
 				continue
 			}
@@ -517,14 +709,48 @@ func (m *AppModel) refreshFilter() {
 	m.clampCursor()
 }
 
-func tickCmd() tea.Cmd {
-	return tea.Tick(time.Second/time.Duration(config.TargetFPS), func(t time.Time) tea.Msg {
+// radarSettings returns the radar geometry, plus the pinned bearing of the
+// isolated device if a fox hunt fixed one.
+func (m AppModel) radarSettings() radar.Settings {
+	set := radar.Settings{
+		MaxRange:    m.maxRange,
+		AspectRatio: m.cfg.Radar.AspectRatio,
+		RingCount:   m.cfg.Radar.RingCount,
+		Localized:   m.localized,
+	}
+	if m.isolateMAC != "" {
+		if d, ok := m.shared.store.Get(m.isolateMAC); ok && d.Pinned {
+			set.ShowBearing, set.Bearing = true, d.Angle
+		}
+	}
+	return set
+}
+
+func tickCmd(fps int) tea.Cmd {
+	return tea.Tick(time.Second/time.Duration(fps), func(t time.Time) tea.Msg {
 		return TickMsg(t)
 	})
 }
 
-func evictCmd() tea.Cmd {
-	return tea.Tick(config.EvictInterval, func(t time.Time) tea.Msg {
+func evictCmd(interval time.Duration) tea.Cmd {
+	return tea.Tick(interval, func(t time.Time) tea.Msg {
 		return EvictMsg(t)
 	})
 }
+
+// trackerAlert summarizes the tracking tags following the user for the
+// status bar, longest following first.
+func trackerAlert(alerts []tracking.Track) string {
+	if len(alerts) == 0 {
+		return ""
+	}
+	t := alerts[0]
+	msg := fmt.Sprintf("TRACKER FOLLOWING: %s for %s", t.Label(), t.Duration().Truncate(time.Minute))
+	if t.Places > 1 {
+		msg += fmt.Sprintf(" at %d places", t.Places)
+	}
+	if len(alerts) > 1 {
+		msg += fmt.Sprintf(" (+%d more)", len(alerts)-1)
+	}
+	return msg
+}
@@ -0,0 +1,82 @@
+package app
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func (m AppModel) handleKeyCalibration(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	switch key {
+	case "ctrl+c":
+		m.stopScanners()
+		return m, tea.Quit
+
+	case "esc":
+		m.calib = nil
+		m.calibStatus = ""
+
+	case "enter":
+		if !m.calib.Sampling() {
+			if err := m.calib.Begin(); err != nil {
+				m.calibStatus = err.Error()
+			} else {
+				m.calibStatus = ""
+			}
+		}
+
+	case "backspace":
+		if n := len(m.calib.Input); n > 0 && !m.calib.Sampling() {
+			m.calib.Input = m.calib.Input[:n-1]
+		}
+
+	case "u", "U":
+		m.calib.Undo()
+
+	case "g", "G":
+		m.saveCalibration(true)
+
+	case "d", "D":
+		m.saveCalibration(false)
+
+	default:
+		if len(key) == 1 && (key[0] >= '0' && key[0] <= '9' || key[0] == '.') && !m.calib.Sampling() {
+			m.calib.Input += key
+		}
+	}
+	return m, nil
+}
+
+// saveCalibration stores the current fit globally or for the wizard's
+// device, writes the profile file and applies it to the running store.
+func (m *AppModel) saveCalibration(global bool) {
+	res, err := m.calib.Fit()
+	if err != nil {
+		m.calibStatus = err.Error()
+		return
+	}
+
+	p := m.shared.profiles
+	if global {
+		model := res.Model
+		p.Global = &model
+	} else {
+		p.SetDevice(m.calib.MAC, res.Model)
+	}
+	p.Apply(m.shared.store)
+
+	if m.shared.profilesPath == "" {
+		m.calibStatus = "Applied for this session (no profile file configured)"
+		return
+	}
+	if err := p.Save(m.shared.profilesPath); err != nil {
+		m.calibStatus = fmt.Sprintf("Save failed: %v", err)
+		return
+	}
+	scope := "device"
+	if global {
+		scope = "global"
+	}
+	m.calibStatus = fmt.Sprintf("Saved %s profile to %s", scope, m.shared.profilesPath)
+}
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"ble-radar.klederson.com/internal/localization"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func (m AppModel) handleKeyFoxHunt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		m.stopScanners()
+		return m, tea.Quit
+
+	case "esc":
+		m.fox = nil
+		m.foxStatus = ""
+
+	case "left", "h":
+		m.fox.Turn(-localization.HeadingStep)
+
+	case "right", "l":
+		m.fox.Turn(localization.HeadingStep)
+
+	case " ", "m", "M":
+		m.fox.Mark(time.Now())
+		m.foxStatus = ""
+
+	case "u", "U", "backspace":
+		m.fox.Undo()
+
+	case "enter":
+		b, err := m.fox.Estimate(time.Now())
+		if err != nil {
+			m.foxStatus = err.Error()
+			return m, nil
+		}
+		m.shared.store.PinBearing(m.fox.MAC, b.Angle)
+		m.fox = nil
+		m.foxStatus = ""
+
+	case "x", "X":
+		m.shared.store.UnpinBearing(m.fox.MAC)
+		m.foxStatus = fmt.Sprintf("Cleared pinned bearing of %s", m.fox.MAC)
+
+	case "n", "N":
+		// Face north again
+		m.fox.Turn(-m.fox.Heading)
+	}
+	return m, nil
+}
@@ -52,3 +52,13 @@ func (r *RSSIRing) Last() float64 {
 func (r *RSSIRing) Len() int {
 	return r.count
 }
+
+// pushHistory appends val to the ring for mac, creating it on first use.
+func pushHistory(rings map[string]*RSSIRing, mac string, val float64) {
+	ring, ok := rings[mac]
+	if !ok {
+		ring = NewRSSIRing(60)
+		rings[mac] = ring
+	}
+	ring.Push(val)
+}
@@ -0,0 +1,56 @@
+package app
+
+import (
+	"time"
+
+	"ble-radar.klederson.com/internal/bluetooth"
+)
+
+// sensorSampleInterval is the shortest time between two samples of a
+// sensor's history, so that frames heard by several adapters or repeated
+// every second do not crowd out older readings.
+const sensorSampleInterval = 5 * time.Second
+
+// sensorHistoryLen is the number of samples kept per quantity.
+const sensorHistoryLen = 60
+
+// SensorHistory keeps the recent readings of one sensor per quantity.
+type SensorHistory struct {
+	last  time.Time
+	rings map[bluetooth.Quantity]*RSSIRing
+}
+
+// Values returns the samples of every quantity, oldest first.
+func (h *SensorHistory) Values() map[bluetooth.Quantity][]float64 {
+	out := make(map[bluetooth.Quantity][]float64, len(h.rings))
+	for q, r := range h.rings {
+		out[q] = r.Values()
+	}
+	return out
+}
+
+// pushSensorHistory samples the readings of d's sensor once it sent a new
+// frame and sensorSampleInterval has passed since the previous sample.
+func pushSensorHistory(hists map[string]*SensorHistory, d *bluetooth.Device) {
+	s := d.Sensor
+	if s == nil {
+		return
+	}
+	h, ok := hists[d.MAC]
+	if !ok {
+		h = &SensorHistory{rings: make(map[bluetooth.Quantity]*RSSIRing)}
+		hists[d.MAC] = h
+	}
+	if s.Updated.Sub(h.last) < sensorSampleInterval {
+		return
+	}
+	h.last = s.Updated
+	for q, v := range s.Readings {
+		ring, ok := h.rings[q]
+		if !ok {
+			ring = NewRSSIRing(sensorHistoryLen)
+			h.rings[q] = ring
+		}
+		ring.Push(v)
+	}
+}
@@ -0,0 +1,90 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"time"
+
+	"ble-radar.klederson.com/internal/bluetooth"
+	"ble-radar.klederson.com/internal/survey"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func (m AppModel) handleKeySurvey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		m.stopScanners()
+		return m, tea.Quit
+
+	case "esc":
+		m.survey = nil
+		m.surveyStatus = ""
+
+	case "tab":
+		m.survey.NextField()
+
+	case "shift+tab":
+		m.survey.PrevField()
+
+	case "enter":
+		if !m.survey.Sampling() {
+			if err := m.survey.Begin(time.Now()); err != nil {
+				m.surveyStatus = err.Error()
+			} else {
+				m.surveyStatus = ""
+			}
+		}
+
+	case "backspace":
+		m.survey.Backspace()
+
+	case "ctrl+u":
+		m.survey.Undo()
+
+	case "ctrl+s":
+		m.saveSurvey()
+
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			m.survey.Type(string(msg.Runes))
+		}
+	}
+	return m, nil
+}
+
+// startSurvey opens the survey form for d, resuming the device's saved
+// survey if there is one.
+func (m *AppModel) startSurvey(d *bluetooth.Device) {
+	path := survey.PathFor(m.cfg.Survey.Dir, d.MAC)
+	s, err := survey.Load(path)
+	switch {
+	case err == nil:
+		m.surveyStatus = fmt.Sprintf("Resumed %d positions from %s", len(s.Observations), path)
+	case errors.Is(err, fs.ErrNotExist):
+		m.surveyStatus = ""
+	default:
+		m.surveyStatus = fmt.Sprintf("Could not load %s: %v", path, err)
+	}
+	if s == nil {
+		s = &survey.Survey{
+			MAC:   d.MAC,
+			Model: bluetooth.PathLoss{MeasuredPower: d.RefPower, Exponent: d.PathLossExp},
+		}
+	}
+	if name := d.KnownName(); name != "" {
+		s.Name = name
+	}
+	m.survey = survey.NewForm(s, m.cfg.Survey.Samples)
+	m.surveyAdapter = d.BestAdapter
+}
+
+// saveSurvey writes the survey to the device's file in the survey directory.
+func (m *AppModel) saveSurvey() {
+	path := survey.PathFor(m.cfg.Survey.Dir, m.survey.Survey.MAC)
+	if err := m.survey.Survey.Save(path); err != nil {
+		m.surveyStatus = fmt.Sprintf("Save failed: %v", err)
+		return
+	}
+	m.surveyStatus = fmt.Sprintf("Saved %d positions to %s", len(m.survey.Survey.Observations), path)
+}
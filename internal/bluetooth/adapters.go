@@ -0,0 +1,81 @@
+package bluetooth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// ErrAdapterNotFound is returned when the requested HCI adapter does not exist.
+var ErrAdapterNotFound = errors.New("bluetooth adapter not found")
+
+// sysfsBluetooth lists one entry per HCI controller known to the kernel.
+const sysfsBluetooth = "/sys/class/bluetooth"
+
+// AdapterInfo describes a Bluetooth controller as reported by BlueZ.
+type AdapterInfo struct {
+	ID      string // HCI name, e.g. "hci0"
+	Address string
+	Name    string // Alias if set, else the system name
+	Powered bool
+}
+
+// ListAdapters queries BlueZ over D-Bus for the available controllers,
+// sorted by ID.
+func ListAdapters() ([]AdapterInfo, error) {
+	conn, err := dbus.SystemBus()
+	if err != nil {
+		return nil, fmt.Errorf("connecting to the system bus: %w", err)
+	}
+
+	var objects map[dbus.ObjectPath]map[string]map[string]dbus.Variant
+	err = conn.Object("org.bluez", "/").
+		Call("org.freedesktop.DBus.ObjectManager.GetManagedObjects", 0).
+		Store(&objects)
+	if err != nil {
+		return nil, fmt.Errorf("querying BlueZ (is bluetoothd running?): %w", err)
+	}
+
+	var adapters []AdapterInfo
+	for path, ifaces := range objects {
+		props, ok := ifaces["org.bluez.Adapter1"]
+		if !ok {
+			continue
+		}
+		info := AdapterInfo{ID: filepath.Base(string(path))}
+		_ = props["Address"].Store(&info.Address)
+		_ = props["Powered"].Store(&info.Powered)
+		if err := props["Alias"].Store(&info.Name); err != nil || info.Name == "" {
+			_ = props["Name"].Store(&info.Name)
+		}
+		adapters = append(adapters, info)
+	}
+	sort.Slice(adapters, func(i, j int) bool { return adapters[i].ID < adapters[j].ID })
+	return adapters, nil
+}
+
+// CheckAdapter returns an error wrapping ErrAdapterNotFound if the kernel
+// has no controller named id. The error lists the controllers that exist.
+func CheckAdapter(id string) error {
+	if _, err := os.Stat(filepath.Join(sysfsBluetooth, id)); err == nil {
+		return nil
+	}
+
+	var present []string
+	entries, _ := os.ReadDir(sysfsBluetooth)
+	for _, e := range entries {
+		// Connection handles show up as "hci0:12"; skip them.
+		if !strings.Contains(e.Name(), ":") {
+			present = append(present, e.Name())
+		}
+	}
+	if len(present) == 0 {
+		return fmt.Errorf("%w: %s (no Bluetooth controllers present)", ErrAdapterNotFound, id)
+	}
+	return fmt.Errorf("%w: %s (available: %s)", ErrAdapterNotFound, id, strings.Join(present, ", "))
+}
@@ -0,0 +1,79 @@
+package bluetooth
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// AddressType classifies a Bluetooth device address.
+type AddressType int
+
+const (
+	AddressUnknown AddressType = iota
+	AddressPublic
+	AddressRandomStatic
+	AddressResolvable    // Resolvable private address (RPA), rotates
+	AddressNonResolvable // Non-resolvable private address, rotates
+)
+
+func (t AddressType) String() string {
+	switch t {
+	case AddressPublic:
+		return "public"
+	case AddressRandomStatic:
+		return "random static"
+	case AddressResolvable:
+		return "resolvable private"
+	case AddressNonResolvable:
+		return "non-resolvable private"
+	default:
+		return ""
+	}
+}
+
+// MarshalText encodes the type by name for JSON output.
+func (t AddressType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText parses a type name written by MarshalText.
+func (t *AddressType) UnmarshalText(text []byte) error {
+	for _, c := range []AddressType{AddressUnknown, AddressPublic, AddressRandomStatic, AddressResolvable, AddressNonResolvable} {
+		if c.String() == string(text) {
+			*t = c
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown address type %q", text)
+}
+
+// Rotating reports whether devices change this kind of address over time.
+func (t AddressType) Rotating() bool {
+	return t == AddressResolvable || t == AddressNonResolvable
+}
+
+// ClassifyAddress derives the address type from a MAC string and whether
+// the controller reported it as a random address. Random addresses are told
+// apart by their two most significant bits.
+func ClassifyAddress(mac string, random bool) AddressType {
+	if !random {
+		return AddressPublic
+	}
+	if len(mac) < 2 {
+		return AddressUnknown
+	}
+	msb, err := strconv.ParseUint(mac[:2], 16, 8)
+	if err != nil {
+		return AddressUnknown
+	}
+	switch msb >> 6 {
+	case 0b11:
+		return AddressRandomStatic
+	case 0b01:
+		return AddressResolvable
+	case 0b00:
+		return AddressNonResolvable
+	default:
+		return AddressUnknown // 0b10 is reserved
+	}
+}
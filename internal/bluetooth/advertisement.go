@@ -0,0 +1,344 @@
+package bluetooth
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"tinygo.org/x/bluetooth"
+)
+
+// GAP AD types decoded from raw advertisements.
+const (
+	adTypeFlags          = 0x01
+	adTypeIncomplete16   = 0x02
+	adTypeComplete16     = 0x03
+	adTypeIncomplete32   = 0x04
+	adTypeComplete32     = 0x05
+	adTypeIncomplete128  = 0x06
+	adTypeComplete128    = 0x07
+	adTypeShortName      = 0x08
+	adTypeCompleteName   = 0x09
+	adTypeTxPower        = 0x0A
+	adTypeServiceData16  = 0x16
+	adTypeAppearance     = 0x19
+	adTypeServiceData32  = 0x20
+	adTypeServiceData128 = 0x21
+	adTypeManufacturer   = 0xFF
+)
+
+// AdvFlags is the value of the Flags AD field.
+type AdvFlags uint8
+
+const (
+	FlagLELimited       AdvFlags = 1 << iota // LE Limited Discoverable Mode
+	FlagLEGeneral                            // LE General Discoverable Mode
+	FlagNoBREDR                              // BR/EDR Not Supported
+	FlagSimulController                      // Simultaneous LE and BR/EDR (controller)
+	FlagSimulHost                            // Simultaneous LE and BR/EDR (host)
+)
+
+var advFlagNames = []struct {
+	flag AdvFlags
+	name string
+}{
+	{FlagLELimited, "LE limited"},
+	{FlagLEGeneral, "LE general"},
+	{FlagNoBREDR, "no BR/EDR"},
+	{FlagSimulController, "LE+BR/EDR controller"},
+	{FlagSimulHost, "LE+BR/EDR host"},
+}
+
+// String lists the set flags, e.g. "LE general, no BR/EDR".
+func (f AdvFlags) String() string {
+	var names []string
+	for _, n := range advFlagNames {
+		if f&n.flag != 0 {
+			names = append(names, n.name)
+		}
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
+
+// HexBytes is a byte slice written as a hex string in JSON.
+type HexBytes []byte
+
+// String returns the bytes as upper case hex.
+func (b HexBytes) String() string {
+	return strings.ToUpper(hex.EncodeToString(b))
+}
+
+// MarshalText encodes the bytes as lower case hex.
+func (b HexBytes) MarshalText() ([]byte, error) {
+	return []byte(hex.EncodeToString(b)), nil
+}
+
+// UnmarshalText parses hex written by MarshalText.
+func (b *HexBytes) UnmarshalText(text []byte) error {
+	v, err := hex.DecodeString(string(text))
+	if err != nil {
+		return err
+	}
+	*b = v
+	return nil
+}
+
+// ServiceUUID is a service class UUID listed in an advertisement.
+type ServiceUUID struct {
+	UUID     string `json:"uuid"`               // "180F" for 16-bit, 8 digits for 32-bit, else the full UUID
+	Complete bool   `json:"complete,omitempty"` // Listed as complete; false if incomplete or the stack does not say
+}
+
+// Name returns the assigned name of a 16-bit UUID, or "".
+func (s ServiceUUID) Name() string {
+	return lookupServiceUUID(s.UUID)
+}
+
+// ManufacturerData is one Manufacturer Specific Data field.
+type ManufacturerData struct {
+	CompanyID uint16   `json:"company_id"`
+	Data      HexBytes `json:"data"`
+}
+
+// ServiceData is one Service Data field.
+type ServiceData struct {
+	UUID string   `json:"uuid"` // Same form as ServiceUUID.UUID
+	Data HexBytes `json:"data"`
+}
+
+// Name returns the assigned name of a 16-bit UUID, or "".
+func (s ServiceData) Name() string {
+	return lookupServiceUUID(s.UUID)
+}
+
+// ADField is an AD structure that Advertisement does not decode.
+type ADField struct {
+	Type byte     `json:"type"`
+	Data HexBytes `json:"data"`
+}
+
+// Name returns the assigned name of the AD type, or "".
+func (f ADField) Name() string {
+	return LookupADType(f.Type)
+}
+
+// Advertisement holds every AD field of a BLE advertisement and its scan
+// response. Stacks that hand out the raw bytes (Raw set) report every
+// field; BlueZ only passes on the name, flags, services, manufacturer
+// data, service data, appearance and TX power, so Connectable and Other
+// stay unset there. Advertisement and scan response arrive separately, so
+// an Advertisement accumulates them via Merge.
+type Advertisement struct {
+	LocalName string `json:"local_name,omitempty"`
+	ShortName bool   `json:"short_name,omitempty"` // LocalName is the shortened name
+
+	Flags    AdvFlags `json:"flags,omitempty"`
+	HasFlags bool     `json:"has_flags,omitempty"`
+
+	Services     []ServiceUUID      `json:"services,omitempty"`
+	Manufacturer []ManufacturerData `json:"manufacturer,omitempty"`
+	ServiceData  []ServiceData      `json:"service_data,omitempty"`
+
+	Appearance    uint16 `json:"appearance,omitempty"`
+	HasAppearance bool   `json:"has_appearance,omitempty"`
+
+	TxPower    int8 `json:"tx_power,omitempty"` // dBm
+	HasTxPower bool `json:"has_tx_power,omitempty"`
+
+	// Connectable is only known to sources that see the PDU type
+	Connectable    bool `json:"connectable,omitempty"`
+	HasConnectable bool `json:"has_connectable,omitempty"`
+
+	Other []ADField `json:"other,omitempty"` // Remaining AD structures, raw
+
+	Raw HexBytes `json:"raw,omitempty"` // AD structures as received, if the stack reports them
+}
+
+// ParseAdvertisement decodes the AD structures of a raw advertisement or
+// scan response. Malformed trailing structures are ignored.
+func ParseAdvertisement(raw []byte) *Advertisement {
+	a := &Advertisement{Raw: append(HexBytes(nil), raw...)}
+	for len(raw) >= 2 {
+		n := int(raw[0])
+		if n == 0 || n >= len(raw) {
+			break
+		}
+		typ, data := raw[1], raw[2:n+1]
+		raw = raw[n+1:]
+		switch typ {
+		case adTypeFlags:
+			if len(data) >= 1 {
+				a.Flags, a.HasFlags = AdvFlags(data[0]), true
+			}
+		case adTypeIncomplete16, adTypeComplete16:
+			for ; len(data) >= 2; data = data[2:] {
+				a.addService(bluetooth.New16BitUUID(binary.LittleEndian.Uint16(data)), typ == adTypeComplete16)
+			}
+		case adTypeIncomplete32, adTypeComplete32:
+			for ; len(data) >= 4; data = data[4:] {
+				a.addService(bluetooth.New32BitUUID(binary.LittleEndian.Uint32(data)), typ == adTypeComplete32)
+			}
+		case adTypeIncomplete128, adTypeComplete128:
+			for ; len(data) >= 16; data = data[16:] {
+				a.addService(uuid128(data), typ == adTypeComplete128)
+			}
+		case adTypeShortName, adTypeCompleteName:
+			if a.LocalName == "" || typ == adTypeCompleteName {
+				a.LocalName, a.ShortName = string(data), typ == adTypeShortName
+			}
+		case adTypeTxPower:
+			if len(data) == 1 {
+				a.TxPower, a.HasTxPower = int8(data[0]), true
+			}
+		case adTypeAppearance:
+			if len(data) == 2 {
+				a.Appearance, a.HasAppearance = binary.LittleEndian.Uint16(data), true
+			}
+		case adTypeServiceData16:
+			if len(data) >= 2 {
+				a.addServiceData(bluetooth.New16BitUUID(binary.LittleEndian.Uint16(data)), data[2:])
+			}
+		case adTypeServiceData32:
+			if len(data) >= 4 {
+				a.addServiceData(bluetooth.New32BitUUID(binary.LittleEndian.Uint32(data)), data[4:])
+			}
+		case adTypeServiceData128:
+			if len(data) >= 16 {
+				a.addServiceData(uuid128(data), data[16:])
+			}
+		case adTypeManufacturer:
+			if len(data) >= 2 {
+				a.Manufacturer = append(a.Manufacturer, ManufacturerData{
+					CompanyID: binary.LittleEndian.Uint16(data),
+					Data:      append(HexBytes(nil), data[2:]...),
+				})
+			}
+		default:
+			a.Other = append(a.Other, ADField{Type: typ, Data: append(HexBytes(nil), data...)})
+		}
+	}
+	return a
+}
+
+// uuid128 reads a 128-bit UUID sent least significant byte first.
+func uuid128(b []byte) bluetooth.UUID {
+	var be [16]byte
+	for i := range be {
+		be[i] = b[15-i]
+	}
+	return bluetooth.NewUUID(be)
+}
+
+// uuidLabel returns the short form of 16 and 32-bit UUIDs, else the full
+// UUID.
+func uuidLabel(u bluetooth.UUID) string {
+	switch {
+	case u.Is16Bit():
+		return fmt.Sprintf("%04X", u.Get16Bit())
+	case u.Is32Bit():
+		return fmt.Sprintf("%08X", u.Get32Bit())
+	}
+	return u.String()
+}
+
+func (a *Advertisement) addService(u bluetooth.UUID, complete bool) {
+	a.Services = append(a.Services, ServiceUUID{UUID: uuidLabel(u), Complete: complete})
+}
+
+func (a *Advertisement) addServiceData(u bluetooth.UUID, data []byte) {
+	a.ServiceData = append(a.ServiceData, ServiceData{UUID: uuidLabel(u), Data: append(HexBytes(nil), data...)})
+}
+
+// Merge copies the fields of a later advertisement or scan response into a.
+// Services accumulate; manufacturer and service data replace earlier
+// entries of the same company or UUID.
+func (a *Advertisement) Merge(other *Advertisement) {
+	if other == nil {
+		return
+	}
+	if other.LocalName != "" && (a.LocalName == "" || a.ShortName || !other.ShortName) {
+		a.LocalName, a.ShortName = other.LocalName, other.ShortName
+	}
+	if other.HasFlags {
+		a.Flags, a.HasFlags = other.Flags, true
+	}
+	if other.HasAppearance {
+		a.Appearance, a.HasAppearance = other.Appearance, true
+	}
+	if other.HasTxPower {
+		a.TxPower, a.HasTxPower = other.TxPower, true
+	}
+	if other.HasConnectable {
+		a.Connectable, a.HasConnectable = other.Connectable, true
+	}
+	for _, s := range other.Services {
+		found := false
+		for i := range a.Services {
+			if a.Services[i].UUID == s.UUID {
+				a.Services[i].Complete = a.Services[i].Complete || s.Complete
+				found = true
+				break
+			}
+		}
+		if !found {
+			a.Services = append(a.Services, s)
+		}
+	}
+	for _, m := range other.Manufacturer {
+		found := false
+		for i := range a.Manufacturer {
+			if a.Manufacturer[i].CompanyID == m.CompanyID {
+				a.Manufacturer[i].Data = m.Data
+				found = true
+				break
+			}
+		}
+		if !found {
+			a.Manufacturer = append(a.Manufacturer, m)
+		}
+	}
+	for _, sd := range other.ServiceData {
+		found := false
+		for i := range a.ServiceData {
+			if a.ServiceData[i].UUID == sd.UUID {
+				a.ServiceData[i].Data = sd.Data
+				found = true
+				break
+			}
+		}
+		if !found {
+			a.ServiceData = append(a.ServiceData, sd)
+		}
+	}
+	for _, f := range other.Other {
+		found := false
+		for i := range a.Other {
+			if a.Other[i].Type == f.Type {
+				a.Other[i].Data = f.Data
+				found = true
+				break
+			}
+		}
+		if !found {
+			a.Other = append(a.Other, f)
+		}
+	}
+	if len(other.Raw) > 0 {
+		a.Raw = other.Raw
+	}
+}
+
+// clone returns a deep copy of a. The byte slices are never modified in
+// place, so they are shared.
+func (a *Advertisement) clone() *Advertisement {
+	cp := *a
+	cp.Services = append([]ServiceUUID(nil), a.Services...)
+	cp.Manufacturer = append([]ManufacturerData(nil), a.Manufacturer...)
+	cp.ServiceData = append([]ServiceData(nil), a.ServiceData...)
+	cp.Other = append([]ADField(nil), a.Other...)
+	return &cp
+}
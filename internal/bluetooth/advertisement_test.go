@@ -0,0 +1,171 @@
+package bluetooth
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAdvertisement(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string // AD structures: length, type, data
+		want Advertisement
+	}{
+		{
+			name: "every decoded field",
+			raw:  "020106 0503 0F18 0A18 0609 5261646172 020A F4 0319 C103 05FF 4C00 1005 0516 AAFE 10EB",
+			want: Advertisement{
+				LocalName: "Radar", Flags: FlagLEGeneral | FlagNoBREDR, HasFlags: true,
+				Services:     []ServiceUUID{{UUID: "180F", Complete: true}, {UUID: "180A", Complete: true}},
+				Manufacturer: []ManufacturerData{{CompanyID: 0x004C, Data: HexBytes{0x10, 0x05}}},
+				ServiceData:  []ServiceData{{UUID: "FEAA", Data: HexBytes{0x10, 0xEB}}},
+				Appearance:   0x03C1, HasAppearance: true,
+				TxPower: -12, HasTxPower: true,
+			},
+		},
+		{
+			name: "incomplete 16-bit list",
+			raw:  "0302 D2FE",
+			want: Advertisement{Services: []ServiceUUID{{UUID: "FED2"}}},
+		},
+		{
+			name: "32-bit uuids little endian",
+			raw:  "0505 78563412 0904 EFBEADDE 01020304",
+			want: Advertisement{Services: []ServiceUUID{
+				{UUID: "12345678", Complete: true}, {UUID: "DEADBEEF"}, {UUID: "04030201"},
+			}},
+		},
+		{
+			name: "128-bit uuid little endian",
+			raw:  "1107 9ECADC240EE5A9E093F3A3B50100406E",
+			want: Advertisement{Services: []ServiceUUID{{UUID: "6e400001-b5a3-f393-e0a9-e50e24dcca9e", Complete: true}}},
+		},
+		{
+			name: "32 and 128-bit service data",
+			raw:  "0720 78563412 AB01 1221 9ECADC240EE5A9E093F3A3B50300406E 42",
+			want: Advertisement{ServiceData: []ServiceData{
+				{UUID: "12345678", Data: HexBytes{0xAB, 0x01}},
+				{UUID: "6e400003-b5a3-f393-e0a9-e50e24dcca9e", Data: HexBytes{0x42}},
+			}},
+		},
+		{
+			name: "complete name after short",
+			raw:  "0408 526164 0609 5261646172",
+			want: Advertisement{LocalName: "Radar"},
+		},
+		{
+			name: "short name after complete",
+			raw:  "0609 5261646172 0408 526164",
+			want: Advertisement{LocalName: "Radar"},
+		},
+		{
+			name: "short name only",
+			raw:  "0408 526164",
+			want: Advertisement{LocalName: "Rad", ShortName: true},
+		},
+		{
+			name: "truncated structure",
+			raw:  "020106 0509 4142",
+			want: Advertisement{Flags: FlagLEGeneral | FlagNoBREDR, HasFlags: true},
+		},
+		{
+			name: "lone length byte",
+			raw:  "020106 03",
+			want: Advertisement{Flags: FlagLEGeneral | FlagNoBREDR, HasFlags: true},
+		},
+		{
+			// A zero length ends the significant part; the rest is padding.
+			name: "zero-length structure",
+			raw:  "020106 00 0309 4142",
+			want: Advertisement{Flags: FlagLEGeneral | FlagNoBREDR, HasFlags: true},
+		},
+		{
+			name: "partial uuids dropped",
+			raw:  "0403 0F18 0A 0405 785634 1007 9ECADC240EE5A9E093F3A3B50100406E",
+			want: Advertisement{Services: []ServiceUUID{{UUID: "180F", Complete: true}}},
+		},
+		{
+			name: "malformed fixed-size fields ignored",
+			raw:  "0101 030A F400 0219 C1 02FF 4C 0216 AA 0420 785634",
+		},
+		{
+			name: "unknown types kept raw",
+			raw:  "032A 0102 0124",
+			want: Advertisement{Other: []ADField{{Type: 0x2A, Data: HexBytes{0x01, 0x02}}, {Type: 0x24}}},
+		},
+		{name: "empty", raw: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := unhex(t, tt.raw)
+			got := ParseAdvertisement(raw)
+			want := tt.want
+			if len(raw) > 0 {
+				want.Raw = raw
+			}
+			if !reflect.DeepEqual(*got, want) {
+				t.Errorf("ParseAdvertisement =\n%+v\nwant\n%+v", *got, want)
+			}
+		})
+	}
+}
+
+func TestAdvertisementMerge(t *testing.T) {
+	adv := ParseAdvertisement(unhex(t, "020106 0302 0F18 06FF 4C00 100503 05FF 0600 0109 0516 AAFE 10EB 032A 0102"))
+	rsp := ParseAdvertisement(unhex(t, "0609 5261646172 0503 0F18 0A18 05FF 4C00 1007 0516 AAFE 20EE 032A 0304 020A F4"))
+	adv.Merge(rsp)
+	want := &Advertisement{
+		LocalName: "Radar", Flags: FlagLEGeneral | FlagNoBREDR, HasFlags: true,
+		// A service listed again as complete becomes complete
+		Services: []ServiceUUID{{UUID: "180F", Complete: true}, {UUID: "180A", Complete: true}},
+		Manufacturer: []ManufacturerData{
+			{CompanyID: 0x004C, Data: HexBytes{0x10, 0x07}},
+			{CompanyID: 0x0006, Data: HexBytes{0x01, 0x09}},
+		},
+		ServiceData: []ServiceData{{UUID: "FEAA", Data: HexBytes{0x20, 0xEE}}},
+		TxPower:     -12, HasTxPower: true,
+		Other: []ADField{{Type: 0x2A, Data: HexBytes{0x03, 0x04}}},
+		Raw:   rsp.Raw,
+	}
+	if !reflect.DeepEqual(adv, want) {
+		t.Errorf("merged =\n%+v\nwant\n%+v", adv, want)
+	}
+
+	adv.Merge(nil)
+	if !reflect.DeepEqual(adv, want) {
+		t.Error("Merge(nil) changed the advertisement")
+	}
+
+	// Flags, appearance and TX power only change when the later packet
+	// carries them.
+	adv.Merge(ParseAdvertisement(unhex(t, "0319 C103 020A 00")))
+	if adv.Flags != FlagLEGeneral|FlagNoBREDR || adv.Appearance != 0x03C1 || adv.TxPower != 0 || !adv.HasTxPower {
+		t.Errorf("after appearance and TX power = %+v", adv)
+	}
+}
+
+func TestAdvertisementMergeName(t *testing.T) {
+	short := func(name string) *Advertisement { return &Advertisement{LocalName: name, ShortName: true} }
+	full := func(name string) *Advertisement { return &Advertisement{LocalName: name} }
+	tests := []struct {
+		name      string
+		a, other  *Advertisement
+		wantName  string
+		wantShort bool
+	}{
+		{"complete replaces short", short("Rad"), full("Radar"), "Radar", false},
+		{"short keeps complete", full("Radar"), short("Rad"), "Radar", false},
+		{"short replaces short", short("Rad"), short("Radr"), "Radr", true},
+		{"complete replaces complete", full("Radar"), full("Radar 2"), "Radar 2", false},
+		{"short fills empty", &Advertisement{}, short("Rad"), "Rad", true},
+		{"no name keeps name", short("Rad"), &Advertisement{}, "Rad", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.a.Merge(tt.other)
+			if tt.a.LocalName != tt.wantName || tt.a.ShortName != tt.wantShort {
+				t.Errorf("name %q short %v, want %q short %v", tt.a.LocalName, tt.a.ShortName, tt.wantName, tt.wantShort)
+			}
+		})
+	}
+}
@@ -0,0 +1,495 @@
+// Code generated by gen_assigned_numbers.go from assigned_numbers/; DO NOT EDIT.
+
+package bluetooth
+
+// companyNames maps SIG company identifiers to company names.
+var companyNames = map[uint16]string{
+	0x0000: "Ericsson",
+	0x0001: "Nokia Mobile Phones",
+	0x0002: "Intel",
+	0x0003: "IBM",
+	0x0004: "Toshiba",
+	0x0005: "3Com",
+	0x0006: "Microsoft",
+	0x0007: "Lucent",
+	0x0008: "Motorola",
+	0x0009: "Infineon Technologies",
+	0x000A: "Qualcomm Technologies International",
+	0x000B: "Silicon Wave",
+	0x000C: "Digianswer",
+	0x000D: "Texas Instruments",
+	0x000E: "Parthus Technologies",
+	0x000F: "Broadcom",
+	0x0010: "Mitel Semiconductor",
+	0x0011: "Widcomm",
+	0x0012: "Zeevo",
+	0x0013: "Atmel",
+	0x0014: "Mitsubishi Electric",
+	0x0015: "RTX",
+	0x0016: "KC Technology",
+	0x0017: "Newlogic",
+	0x0018: "Transilica",
+	0x0019: "Rohde & Schwarz",
+	0x001A: "TTPCom",
+	0x001B: "Signia Technologies",
+	0x001C: "Conexant Systems",
+	0x001D: "Qualcomm",
+	0x001E: "Inventel",
+	0x001F: "AVM Berlin",
+	0x0020: "BandSpeed",
+	0x0021: "Mansella",
+	0x0022: "NEC",
+	0x0023: "WavePlus Technology",
+	0x0024: "Alcatel",
+	0x0025: "NXP",
+	0x0026: "C Technologies",
+	0x0027: "Open Interface",
+	0x0028: "R F Micro Devices",
+	0x0029: "Hitachi",
+	0x002A: "Symbol Technologies",
+	0x002B: "Tenovis",
+	0x002C: "Macronix International",
+	0x002D: "GCT Semiconductor",
+	0x002E: "Norwood Systems",
+	0x002F: "MewTel Technology",
+	0x0030: "ST Microelectronics",
+	0x0031: "Synopsys",
+	0x0032: "Red-M",
+	0x0033: "Commil",
+	0x0034: "Computer Access Technology",
+	0x0035: "Eclipse (HQ Espana) S.L.",
+	0x0036: "Renesas Electronics",
+	0x0037: "Mobilian",
+	0x0038: "Syntronix",
+	0x0039: "Integrated System Solution",
+	0x003A: "Panasonic Holdings",
+	0x003B: "Gennum",
+	0x003C: "BlackBerry",
+	0x003D: "IPextreme",
+	0x003E: "Systems and Chips",
+	0x003F: "Bluetooth SIG",
+	0x0040: "Seiko Epson",
+	0x0041: "Integrated Silicon Solution Taiwan",
+	0x0042: "CONWISE Technology",
+	0x0043: "PARROT AUTOMOTIVE",
+	0x0044: "Socket Mobile",
+	0x0045: "Atheros Communications",
+	0x0046: "MediaTek",
+	0x0047: "Bluegiga",
+	0x0048: "Marvell Technology Group",
+	0x0049: "3DSP",
+	0x004A: "Accel Semiconductor",
+	0x004B: "Continental Automotive Systems",
+	0x004C: "Apple",
+	0x004D: "Staccato Communications",
+	0x004E: "Avago Technologies",
+	0x004F: "APT",
+	0x0050: "SiRF Technology",
+	0x0051: "Tzero Technologies",
+	0x0052: "J&M",
+	0x0053: "Free2move",
+	0x0054: "3DiJoy",
+	0x0055: "Plantronics",
+	0x0056: "Sony Ericsson Mobile Communications",
+	0x0057: "Harman International Industries",
+	0x0058: "Vizio",
+	0x0059: "Nordic Semiconductor",
+	0x005A: "EM Microelectronic-Marin",
+	0x005B: "Ralink Technology",
+	0x005C: "Belkin International",
+	0x005D: "Realtek Semiconductor",
+	0x005E: "Stonestreet One",
+	0x005F: "Wicentric",
+	0x0060: "RivieraWaves",
+	0x0061: "RDA Microelectronics",
+	0x0062: "Gibson Guitars",
+	0x0063: "MiCommand",
+	0x0064: "Band XI International",
+	0x0065: "HP",
+	0x0066: "9Solutions",
+	0x0067: "GN Audio",
+	0x0068: "General Motors",
+	0x0069: "A&D Engineering",
+	0x006A: "LTIMINDTREE",
+	0x006B: "Polar Electro",
+	0x006C: "Beautiful Enterprise",
+	0x006D: "BriarTek",
+	0x006E: "Summit Data Communications",
+	0x006F: "Sound ID",
+	0x0070: "Monster",
+	0x0071: "connectBlue",
+	0x0072: "ShangHai Super Smart Electronics",
+	0x0073: "Group Sense",
+	0x0074: "Zomm",
+	0x0075: "Samsung Electronics",
+	0x0076: "Creative Technology",
+	0x0077: "Laird Connectivity",
+	0x0078: "Nike",
+	0x0079: "lesswire",
+	0x007A: "MStar Semiconductor",
+	0x007B: "Hanlynn Technologies",
+	0x007C: "A & R Cambridge",
+	0x007D: "Seers Technology",
+	0x007E: "Sports Tracking Technologies",
+	0x007F: "Autonet Mobile",
+	0x0080: "DeLorme Publishing Company",
+	0x0081: "WuXi Vimicro",
+	0x0082: "DSEA",
+	0x0083: "TimeKeeping Systems",
+	0x0084: "Ludus Helsinki",
+	0x0085: "BlueRadios",
+	0x0086: "Equinux",
+	0x0087: "Garmin International",
+	0x0088: "Ecotest",
+	0x0089: "GN Hearing",
+	0x008A: "Jawbone",
+	0x008B: "Topcon Positioning Systems",
+	0x008C: "Gimbal",
+	0x008D: "Zscan Software",
+	0x008E: "Quintic",
+	0x008F: "Telit Wireless Solutions",
+	0x0090: "Funai Electric",
+	0x0091: "Advanced PANMOBIL systems",
+	0x0092: "ThinkOptics",
+	0x0093: "Universal Electronics",
+	0x0094: "Airoha Technology",
+	0x0095: "NEC Lighting",
+	0x0096: "ODM Technology",
+	0x0097: "ConnecteDevice",
+	0x0098: "zero1.tv",
+	0x0099: "i.Tech Dynamic Global Distribution",
+	0x009A: "Alpwise",
+	0x009B: "Jiangsu Toppower Automotive Electronics",
+	0x009C: "Colorfy",
+	0x009D: "Geoforce",
+	0x009E: "Bose",
+	0x009F: "Suunto",
+	0x00A0: "Kensington Computer Products Group",
+	0x00A1: "SR-Medizinelektronik",
+	0x00A2: "Vertu",
+	0x00A3: "Meta Watch",
+	0x00A4: "LINAK",
+	0x00A5: "OTL Dynamics",
+	0x00A6: "Panda Ocean",
+	0x00A7: "Visteon",
+	0x00A8: "ARP Devices",
+	0x00A9: "MARELLI EUROPE",
+	0x00AA: "CAEN RFID",
+	0x00AB: "Ingenieur-Systemgruppe Zahn",
+	0x00AC: "Green Throttle Games",
+	0x00AD: "Peter Systemtechnik",
+	0x00AE: "Omegawave",
+	0x00AF: "Cinetix",
+	0x00B0: "Passif Semiconductor",
+	0x00B1: "Saris Cycling Group",
+	0x00B2: "Bekey",
+	0x00B3: "Clarinox Technologies Pty.",
+	0x00B4: "BDE Technology",
+	0x00B5: "Swirl Networks",
+	0x00B6: "Meso international",
+	0x00B7: "TreLab",
+	0x00B8: "Qualcomm Innovation Center",
+	0x00B9: "Johnson Controls",
+	0x00BA: "Starkey Hearing Technologies",
+	0x00BB: "S-Power Electronics",
+	0x00BC: "Ace Sensor",
+	0x00BD: "Aplix",
+	0x00BE: "AAMP of America",
+	0x00BF: "Stalmart Technology",
+	0x00C0: "AMICCOM Electronics",
+	0x00C1: "Shenzhen Excelsecu Data Technology",
+	0x00C2: "Geneq",
+	0x00C3: "adidas",
+	0x00C4: "LG Electronics",
+	0x00C5: "Onset Computer",
+	0x00C6: "Selfly",
+	0x00C7: "Quuppa",
+	0x00C8: "GeLo",
+	0x00C9: "Evluma",
+	0x00CA: "MC10",
+	0x00CB: "Binauric",
+	0x00CC: "Beats Electronics",
+	0x00CD: "Microchip Technology",
+	0x00CE: "Eve Systems",
+	0x00CF: "ARCHOS",
+	0x00D0: "Dexcom",
+	0x00D1: "Polar Electro Europe",
+	0x00D2: "Dialog Semiconductor",
+	0x00D3: "Taixingbang Technology",
+	0x00D4: "Kawantech",
+	0x00D5: "Austco Communication Systems",
+	0x00D6: "Timex Group USA",
+	0x00D7: "Qualcomm Technologies",
+	0x00D8: "Qualcomm Connected Experiences",
+	0x00D9: "Voyetra Turtle Beach",
+	0x00DA: "txtr",
+	0x00DB: "Snuza",
+	0x00DC: "Procter & Gamble",
+	0x00DD: "Hosiden",
+	0x00DE: "Muzik",
+	0x00DF: "Misfit Wearables",
+	0x00E0: "Google",
+	0x0118: "Radius Networks",
+	0x012D: "Sony",
+	0x0131: "Cypress Semiconductor",
+	0x0154: "Pebble Technology",
+	0x0157: "Anhui Huami Information Technology",
+	0x0171: "Amazon.com Services",
+	0x01AB: "Meta Platforms",
+	0x027D: "HUAWEI Technologies",
+	0x02E5: "Espressif Systems",
+	0x038F: "Xiaomi",
+	0x0499: "Ruuvi Innovations",
+	0x067C: "Tile",
+	0x0822: "Adafruit Industries",
+	0x0969: "Woan Technology",
+}
+
+// serviceNames maps 16-bit GATT service and member UUIDs to names.
+var serviceNames = map[uint16]string{
+	0x1800: "Generic Access",
+	0x1801: "Generic Attribute",
+	0x1802: "Immediate Alert",
+	0x1803: "Link Loss",
+	0x1804: "Tx Power",
+	0x1805: "Current Time",
+	0x1806: "Reference Time Update",
+	0x1807: "Next DST Change",
+	0x1808: "Glucose",
+	0x1809: "Health Thermometer",
+	0x180A: "Device Information",
+	0x180D: "Heart Rate",
+	0x180E: "Phone Alert Status",
+	0x180F: "Battery",
+	0x1810: "Blood Pressure",
+	0x1811: "Alert Notification",
+	0x1812: "Human Interface Device",
+	0x1813: "Scan Parameters",
+	0x1814: "Running Speed and Cadence",
+	0x1815: "Automation IO",
+	0x1816: "Cycling Speed and Cadence",
+	0x1818: "Cycling Power",
+	0x1819: "Location and Navigation",
+	0x181A: "Environmental Sensing",
+	0x181B: "Body Composition",
+	0x181C: "User Data",
+	0x181D: "Weight Scale",
+	0x181E: "Bond Management",
+	0x181F: "Continuous Glucose Monitoring",
+	0x1820: "Internet Protocol Support",
+	0x1821: "Indoor Positioning",
+	0x1822: "Pulse Oximeter",
+	0x1823: "HTTP Proxy",
+	0x1824: "Transport Discovery",
+	0x1825: "Object Transfer",
+	0x1826: "Fitness Machine",
+	0x1827: "Mesh Provisioning",
+	0x1828: "Mesh Proxy",
+	0x1829: "Reconnection Configuration",
+	0x183A: "Insulin Delivery",
+	0x183B: "Binary Sensor",
+	0x183C: "Emergency Configuration",
+	0x183D: "Authorization Control",
+	0x183E: "Physical Activity Monitor",
+	0x183F: "Elapsed Time",
+	0x1840: "Generic Health Sensor",
+	0x1843: "Audio Input Control",
+	0x1844: "Volume Control",
+	0x1845: "Volume Offset Control",
+	0x1846: "Coordinated Set Identification",
+	0x1847: "Device Time",
+	0x1848: "Media Control",
+	0x1849: "Generic Media Control",
+	0x184A: "Constant Tone Extension",
+	0x184B: "Telephone Bearer",
+	0x184C: "Generic Telephone Bearer",
+	0x184D: "Microphone Control",
+	0x184E: "Audio Stream Control",
+	0x184F: "Broadcast Audio Scan",
+	0x1850: "Published Audio Capabilities",
+	0x1851: "Basic Audio Announcement",
+	0x1852: "Broadcast Audio Announcement",
+	0x1853: "Common Audio",
+	0x1854: "Hearing Access",
+	0x1855: "Telephony and Media Audio",
+	0x1856: "Public Broadcast Announcement",
+	0x1857: "Electronic Shelf Label",
+	0x1858: "Gaming Audio",
+	0x1859: "Mesh Proxy Solicitation",
+	0xFCD2: "Allterco Robotics ltd",
+	0xFD5A: "Samsung Electronics",
+	0xFD6F: "Apple",
+	0xFE03: "Amazon.com Services",
+	0xFE07: "Sonos",
+	0xFE0F: "Signify Netherlands",
+	0xFE2C: "Google",
+	0xFE59: "Nordic Semiconductor",
+	0xFE95: "Xiaomi",
+	0xFE9F: "Google",
+	0xFEA0: "Google",
+	0xFEAA: "Google",
+	0xFEBE: "Bose",
+	0xFEC7: "Apple",
+	0xFED8: "Google",
+	0xFEEC: "Tile",
+	0xFEED: "Tile",
+	0xFEF3: "Google",
+}
+
+// appearanceNames maps Appearance values (category<<6 | subcategory) to names.
+var appearanceNames = map[uint16]string{
+	0x0000: "Unknown",
+	0x0040: "Phone",
+	0x0080: "Computer",
+	0x0081: "Desktop Workstation",
+	0x0082: "Server-class Computer",
+	0x0083: "Laptop",
+	0x0084: "Handheld PC/PDA (clamshell)",
+	0x0085: "Palm-size PC/PDA",
+	0x0086: "Wearable computer (watch size)",
+	0x0087: "Tablet",
+	0x0088: "Docking Station",
+	0x0089: "All in One",
+	0x008A: "Blade Server",
+	0x008B: "Convertible",
+	0x008C: "Detachable",
+	0x008D: "IoT Gateway",
+	0x008E: "Mini PC",
+	0x008F: "Stick PC",
+	0x00C0: "Watch",
+	0x00C1: "Sports Watch",
+	0x00C2: "Smartwatch",
+	0x0100: "Clock",
+	0x0140: "Display",
+	0x0180: "Remote Control",
+	0x01C0: "Eye-glasses",
+	0x0200: "Tag",
+	0x0240: "Keyring",
+	0x0280: "Media Player",
+	0x02C0: "Barcode Scanner",
+	0x0300: "Thermometer",
+	0x0340: "Heart Rate Sensor",
+	0x0341: "Heart Rate Belt",
+	0x0380: "Blood Pressure",
+	0x03C0: "Human Interface Device",
+	0x03C1: "Keyboard",
+	0x03C2: "Mouse",
+	0x03C3: "Joystick",
+	0x03C4: "Gamepad",
+	0x03C5: "Digitizer Tablet",
+	0x03C6: "Card Reader",
+	0x03C7: "Digital Pen",
+	0x03C8: "Barcode Scanner",
+	0x03C9: "Touchpad",
+	0x03CA: "Presentation Remote",
+	0x0400: "Glucose Meter",
+	0x0440: "Running Walking Sensor",
+	0x0480: "Cycling",
+	0x04C0: "Control Device",
+	0x0500: "Network Device",
+	0x0540: "Sensor",
+	0x0580: "Light Fixtures",
+	0x05C0: "Fan",
+	0x0600: "HVAC",
+	0x0640: "Air Conditioning",
+	0x0680: "Humidifier",
+	0x06C0: "Heating",
+	0x0700: "Access Control",
+	0x0740: "Motorized Device",
+	0x0780: "Power Device",
+	0x07C0: "Light Source",
+	0x0800: "Window Covering",
+	0x0840: "Audio Sink",
+	0x0841: "Standalone Speaker",
+	0x0842: "Soundbar",
+	0x0843: "Bookshelf Speaker",
+	0x0844: "Standmounted Speaker",
+	0x0845: "Speakerphone",
+	0x0880: "Audio Source",
+	0x0881: "Microphone",
+	0x0882: "Alarm",
+	0x0883: "Bell",
+	0x0884: "Horn",
+	0x0885: "Broadcasting Device",
+	0x0886: "Service Desk",
+	0x0887: "Kiosk",
+	0x0888: "Broadcasting Room",
+	0x0889: "Auditorium",
+	0x08C0: "Motorized Vehicle",
+	0x0900: "Domestic Appliance",
+	0x0940: "Wearable Audio Device",
+	0x0941: "Earbud",
+	0x0942: "Headset",
+	0x0943: "Headphones",
+	0x0944: "Neck Band",
+	0x0980: "Aircraft",
+	0x09C0: "AV Equipment",
+	0x0A00: "Display Equipment",
+	0x0A40: "Hearing aid",
+	0x0A80: "Gaming",
+	0x0A81: "Home Video Game Console",
+	0x0A82: "Portable handheld console",
+	0x0AC0: "Signage",
+	0x0C40: "Pulse Oximeter",
+	0x0C80: "Weight Scale",
+	0x0CC0: "Personal Mobility Device",
+	0x0D00: "Continuous Glucose Monitor",
+	0x0D40: "Insulin Pump",
+	0x0D80: "Medication Delivery",
+	0x0DC0: "Spirometer",
+	0x1440: "Outdoor Sports Activity",
+}
+
+// adTypeNames maps GAP AD types to names.
+var adTypeNames = map[byte]string{
+	0x01: "Flags",
+	0x02: "Incomplete List of 16-bit Service or Service Class UUIDs",
+	0x03: "Complete List of 16-bit Service or Service Class UUIDs",
+	0x04: "Incomplete List of 32-bit Service or Service Class UUIDs",
+	0x05: "Complete List of 32-bit Service or Service Class UUIDs",
+	0x06: "Incomplete List of 128-bit Service or Service Class UUIDs",
+	0x07: "Complete List of 128-bit Service or Service Class UUIDs",
+	0x08: "Shortened Local Name",
+	0x09: "Complete Local Name",
+	0x0A: "Tx Power Level",
+	0x0D: "Class of Device",
+	0x0E: "Simple Pairing Hash C-192",
+	0x0F: "Simple Pairing Randomizer R-192",
+	0x10: "Device ID",
+	0x11: "Security Manager Out of Band Flags",
+	0x12: "Peripheral Connection Interval Range",
+	0x14: "List of 16-bit Service Solicitation UUIDs",
+	0x15: "List of 128-bit Service Solicitation UUIDs",
+	0x16: "Service Data - 16-bit UUID",
+	0x17: "Public Target Address",
+	0x18: "Random Target Address",
+	0x19: "Appearance",
+	0x1A: "Advertising Interval",
+	0x1B: "LE Bluetooth Device Address",
+	0x1C: "LE Role",
+	0x1D: "Simple Pairing Hash C-256",
+	0x1E: "Simple Pairing Randomizer R-256",
+	0x1F: "List of 32-bit Service Solicitation UUIDs",
+	0x20: "Service Data - 32-bit UUID",
+	0x21: "Service Data - 128-bit UUID",
+	0x22: "LE Secure Connections Confirmation Value",
+	0x23: "LE Secure Connections Random Value",
+	0x24: "URI",
+	0x25: "Indoor Positioning",
+	0x26: "Transport Discovery Data",
+	0x27: "LE Supported Features",
+	0x28: "Channel Map Update Indication",
+	0x29: "PB-ADV",
+	0x2A: "Mesh Message",
+	0x2B: "Mesh Beacon",
+	0x2C: "BIGInfo",
+	0x2D: "Broadcast_Code",
+	0x2E: "Resolvable Set Identifier",
+	0x2F: "Advertising Interval - long",
+	0x30: "Broadcast_Name",
+	0x31: "Encrypted Advertising Data",
+	0x32: "Periodic Advertising Response Timing Information",
+	0x34: "Electronic Shelf Label",
+	0x3D: "3D Information Data",
+	0xFF: "Manufacturer Specific Data",
+}
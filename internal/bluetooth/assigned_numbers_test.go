@@ -0,0 +1,125 @@
+package bluetooth
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestLookupManufacturer(t *testing.T) {
+	tests := []struct {
+		id   uint16
+		want string
+	}{
+		{0x0006, "Microsoft"},
+		{0x004C, "Apple"},
+		{0x00E0, "Google"},
+		{0x0499, "Ruuvi Innovations"}, // "Ruuvi Innovations Ltd."
+		{0x0969, "Woan Technology"},   // "Woan Technology (Shenzhen) Co., Ltd."
+		{0xFFFF, ""},                  // Reserved for testing
+		{0xFFFE, ""},
+	}
+	for _, tt := range tests {
+		if got := LookupManufacturer(tt.id); got != tt.want {
+			t.Errorf("LookupManufacturer(0x%04X) = %q, want %q", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestLookupService(t *testing.T) {
+	tests := []struct {
+		uuid uint16
+		want string
+	}{
+		{0x180D, "Heart Rate"},
+		{0x180F, "Battery"},
+		{0xFE2C, "Google"}, // Member UUID: Fast Pair
+		{0xFE95, "Xiaomi"}, // Member UUID: MiBeacon
+		{0x0001, ""},
+		{0xFFFF, ""},
+	}
+	for _, tt := range tests {
+		if got := LookupService(tt.uuid); got != tt.want {
+			t.Errorf("LookupService(0x%04X) = %q, want %q", tt.uuid, got, tt.want)
+		}
+	}
+
+	if got := lookupServiceUUID("180f"); got != "Battery" {
+		t.Errorf(`lookupServiceUUID("180f") = %q, want "Battery"`, got)
+	}
+	for _, uuid := range []string{"", "180", "xyz0", "0000180f-0000-1000-8000-00805f9b34fb"} {
+		if got := lookupServiceUUID(uuid); got != "" {
+			t.Errorf("lookupServiceUUID(%q) = %q, want empty", uuid, got)
+		}
+	}
+}
+
+func TestLookupAppearance(t *testing.T) {
+	tests := []struct {
+		appearance uint16
+		want       string
+	}{
+		{0x0040, "Phone"},                  // Category without subcategories
+		{0x03C0, "Human Interface Device"}, // Generic subcategory
+		{0x03C1, "Keyboard"},
+		{0x03C2, "Mouse"},
+		{0x03FF, "Human Interface Device"}, // Unknown subcategory falls back to the category
+		{0x0C80, "Weight Scale"},
+		{0xFFC0, ""}, // Unknown category
+		{0xFFC1, ""},
+	}
+	for _, tt := range tests {
+		if got := LookupAppearance(tt.appearance); got != tt.want {
+			t.Errorf("LookupAppearance(0x%04X) = %q, want %q", tt.appearance, got, tt.want)
+		}
+	}
+}
+
+func TestLookupADType(t *testing.T) {
+	tests := []struct {
+		typ  byte
+		want string
+	}{
+		{0x01, "Flags"},
+		{0x09, "Complete Local Name"},
+		{0x16, "Service Data - 16-bit UUID"},
+		{0xFF, "Manufacturer Specific Data"},
+		{0x00, ""},
+		{0x80, ""},
+	}
+	for _, tt := range tests {
+		if got := LookupADType(tt.typ); got != tt.want {
+			t.Errorf("LookupADType(0x%02X) = %q, want %q", tt.typ, got, tt.want)
+		}
+	}
+}
+
+// TestAssignedNumbersUpToDate fails when assigned_numbers.go was not
+// regenerated after the vendored YAML or the generator changed.
+func TestAssignedNumbersUpToDate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the generator")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not found")
+	}
+	out := filepath.Join(t.TempDir(), "assigned_numbers.go")
+	cmd := exec.Command(goTool, "run", "gen_assigned_numbers.go", "-o", out)
+	if msg, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("gen_assigned_numbers.go: %v\n%s", err, msg)
+	}
+	want, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile("assigned_numbers.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("assigned_numbers.go is stale; run go generate ./internal/bluetooth")
+	}
+}
@@ -0,0 +1,250 @@
+package bluetooth
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// BeaconFormat identifies the beacon protocol of an advertisement.
+type BeaconFormat int
+
+const (
+	BeaconIBeacon BeaconFormat = iota + 1
+	BeaconEddystone
+)
+
+func (f BeaconFormat) String() string {
+	switch f {
+	case BeaconIBeacon:
+		return "iBeacon"
+	case BeaconEddystone:
+		return "Eddystone"
+	default:
+		return ""
+	}
+}
+
+// MarshalText encodes the format by name for JSON output.
+func (f BeaconFormat) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+// UnmarshalText parses a format name written by MarshalText.
+func (f *BeaconFormat) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "iBeacon":
+		*f = BeaconIBeacon
+	case "Eddystone":
+		*f = BeaconEddystone
+	case "":
+		*f = 0
+	default:
+		return fmt.Errorf("unknown beacon format %q", text)
+	}
+	return nil
+}
+
+// Company ID and service UUID used by the supported beacon formats.
+const (
+	companyApple        = 0x004C
+	serviceEddystone    = 0xFEAA
+	iBeaconType         = 0x02
+	iBeaconLength       = 0x15
+	eddystoneFrameUID   = 0x00
+	eddystoneFrameURL   = 0x10
+	eddystoneFrameTLM   = 0x20
+	eddystoneFrameEID   = 0x30
+	eddystoneZeroToOneM = 41 // dB lost between 0 m and 1 m per the Eddystone spec
+)
+
+// Beacon holds decoded iBeacon or Eddystone fields. Eddystone devices
+// interleave several frame types, so a Beacon accumulates them via Merge.
+type Beacon struct {
+	Format BeaconFormat `json:"format"`
+
+	// iBeacon
+	UUID  string `json:"uuid,omitempty"`
+	Major uint16 `json:"major,omitempty"`
+	Minor uint16 `json:"minor,omitempty"`
+
+	// Eddystone-UID / -URL / -EID
+	Namespace string `json:"namespace,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	URL       string `json:"url,omitempty"`
+	EID       string `json:"eid,omitempty"`
+
+	// Eddystone-TLM
+	HasTelemetry bool          `json:"has_telemetry,omitempty"`
+	BatteryMV    uint16        `json:"battery_mv,omitempty"`
+	Temperature  float64       `json:"temperature_c,omitempty"`
+	AdvCount     uint32        `json:"adv_count,omitempty"`
+	Uptime       time.Duration `json:"uptime,omitempty"`
+
+	// Calibrated RSSI at 1 m in dBm. Eddystone's 0 m value is converted.
+	MeasuredPower    int8 `json:"measured_power,omitempty"`
+	HasMeasuredPower bool `json:"has_measured_power,omitempty"`
+}
+
+// Label returns a short identifier suitable as a fallback device name.
+func (b *Beacon) Label() string {
+	switch {
+	case b.Format == BeaconIBeacon:
+		return fmt.Sprintf("iBeacon %d/%d", b.Major, b.Minor)
+	case b.URL != "":
+		u := b.URL
+		for _, p := range []string{"https://", "http://", "www."} {
+			u = strings.TrimPrefix(u, p)
+		}
+		return "Eddystone " + u
+	case b.Instance != "":
+		return "Eddystone " + b.Instance
+	default:
+		return "Eddystone"
+	}
+}
+
+// Merge copies the frame fields present in other into b.
+func (b *Beacon) Merge(other *Beacon) {
+	if other == nil {
+		return
+	}
+	if b.Format != other.Format {
+		*b = *other
+		return
+	}
+	if other.UUID != "" {
+		b.UUID, b.Major, b.Minor = other.UUID, other.Major, other.Minor
+	}
+	if other.Namespace != "" {
+		b.Namespace, b.Instance = other.Namespace, other.Instance
+	}
+	if other.URL != "" {
+		b.URL = other.URL
+	}
+	if other.EID != "" {
+		b.EID = other.EID
+	}
+	if other.HasTelemetry {
+		b.HasTelemetry = true
+		b.BatteryMV = other.BatteryMV
+		b.Temperature = other.Temperature
+		b.AdvCount = other.AdvCount
+		b.Uptime = other.Uptime
+	}
+	if other.HasMeasuredPower {
+		b.MeasuredPower, b.HasMeasuredPower = other.MeasuredPower, true
+	}
+}
+
+// ParseIBeacon decodes Apple manufacturer data carrying an iBeacon frame.
+// Returns nil if the data is not an iBeacon.
+func ParseIBeacon(companyID uint16, data []byte) *Beacon {
+	// type(1) len(1) uuid(16) major(2) minor(2) power(1)
+	if companyID != companyApple || len(data) < 23 || data[0] != iBeaconType || data[1] != iBeaconLength {
+		return nil
+	}
+	u := data[2:18]
+	return &Beacon{
+		Format: BeaconIBeacon,
+		UUID: fmt.Sprintf("%s-%s-%s-%s-%s",
+			hex.EncodeToString(u[0:4]), hex.EncodeToString(u[4:6]), hex.EncodeToString(u[6:8]),
+			hex.EncodeToString(u[8:10]), hex.EncodeToString(u[10:16])),
+		Major:            binary.BigEndian.Uint16(data[18:20]),
+		Minor:            binary.BigEndian.Uint16(data[20:22]),
+		MeasuredPower:    int8(data[22]),
+		HasMeasuredPower: true,
+	}
+}
+
+// ParseEddystone decodes the service data of the Eddystone service (0xFEAA).
+// Returns nil for unknown or truncated frames.
+func ParseEddystone(data []byte) *Beacon {
+	if len(data) < 2 {
+		return nil
+	}
+	b := &Beacon{Format: BeaconEddystone}
+
+	switch data[0] {
+	case eddystoneFrameUID:
+		// type(1) tx(1) namespace(10) instance(6) [rfu(2)]
+		if len(data) < 18 {
+			return nil
+		}
+		b.setZeroMeterPower(data[1])
+		b.Namespace = hex.EncodeToString(data[2:12])
+		b.Instance = hex.EncodeToString(data[12:18])
+
+	case eddystoneFrameURL:
+		// type(1) tx(1) scheme(1) url(0-17)
+		if len(data) < 3 {
+			return nil
+		}
+		url, ok := decodeEddystoneURL(data[2], data[3:])
+		if !ok {
+			return nil
+		}
+		b.setZeroMeterPower(data[1])
+		b.URL = url
+
+	case eddystoneFrameTLM:
+		// type(1) version(1) vbatt(2) temp(2) adv_cnt(4) sec_cnt(4)
+		if len(data) < 14 || data[1] != 0x00 {
+			return nil // encrypted TLM (version 1) is not supported
+		}
+		b.HasTelemetry = true
+		b.BatteryMV = binary.BigEndian.Uint16(data[2:4])
+		b.Temperature = float64(int16(binary.BigEndian.Uint16(data[4:6]))) / 256.0
+		b.AdvCount = binary.BigEndian.Uint32(data[6:10])
+		b.Uptime = time.Duration(binary.BigEndian.Uint32(data[10:14])) * 100 * time.Millisecond
+
+	case eddystoneFrameEID:
+		// type(1) tx(1) eid(8)
+		if len(data) < 10 {
+			return nil
+		}
+		b.setZeroMeterPower(data[1])
+		b.EID = hex.EncodeToString(data[2:10])
+
+	default:
+		return nil
+	}
+	return b
+}
+
+func (b *Beacon) setZeroMeterPower(raw byte) {
+	p := int(int8(raw)) - eddystoneZeroToOneM
+	if p < -128 {
+		p = -128
+	}
+	b.MeasuredPower = int8(p)
+	b.HasMeasuredPower = true
+}
+
+var eddystoneSchemes = []string{"http://www.", "https://www.", "http://", "https://"}
+
+var eddystoneExpansions = []string{
+	".com/", ".org/", ".edu/", ".net/", ".info/", ".biz/", ".gov/",
+	".com", ".org", ".edu", ".net", ".info", ".biz", ".gov",
+}
+
+func decodeEddystoneURL(scheme byte, encoded []byte) (string, bool) {
+	if int(scheme) >= len(eddystoneSchemes) {
+		return "", false
+	}
+	var sb strings.Builder
+	sb.WriteString(eddystoneSchemes[scheme])
+	for _, c := range encoded {
+		switch {
+		case int(c) < len(eddystoneExpansions):
+			sb.WriteString(eddystoneExpansions[c])
+		case c > 0x20 && c < 0x7F:
+			sb.WriteByte(c)
+		default:
+			return "", false
+		}
+	}
+	return sb.String(), true
+}
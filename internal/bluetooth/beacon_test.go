@@ -0,0 +1,175 @@
+package bluetooth
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseIBeacon(t *testing.T) {
+	tests := []struct {
+		name string
+		data string // Apple manufacturer data after the company ID
+		want *Beacon
+	}{
+		{
+			name: "ibeacon",
+			data: "02 15 FDA50693A4E24FB1AFCFC6EB07647825 2774 6B5E C5",
+			want: &Beacon{
+				Format: BeaconIBeacon, UUID: "fda50693-a4e2-4fb1-afcf-c6eb07647825",
+				Major: 10100, Minor: 27486, MeasuredPower: -59, HasMeasuredPower: true,
+			},
+		},
+		{
+			name: "trailing byte",
+			data: "02 15 E2C56DB5DFFB48D2B060D0F5A71096E0 0001 FFFF B3 00",
+			want: &Beacon{
+				Format: BeaconIBeacon, UUID: "e2c56db5-dffb-48d2-b060-d0f5a71096e0",
+				Major: 1, Minor: 65535, MeasuredPower: -77, HasMeasuredPower: true,
+			},
+		},
+		{name: "truncated", data: "02 15 FDA50693A4E24FB1AFCFC6EB07647825 2774 6B5E"},
+		{name: "wrong length byte", data: "02 16 FDA50693A4E24FB1AFCFC6EB07647825 2774 6B5E C5"},
+		{name: "continuity", data: "10 05 1B 1C 4B7E3A"},
+		{name: "empty", data: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseIBeacon(companyApple, unhex(t, tt.data))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseIBeacon = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if b := ParseIBeacon(companyMicrosoft, unhex(t, tests[0].data)); b != nil {
+		t.Error("decoded manufacturer data of another company")
+	}
+}
+
+func TestParseEddystone(t *testing.T) {
+	tests := []struct {
+		name string
+		data string // Service data of 0xFEAA
+		want *Beacon
+	}{
+		{
+			// 0 m power -18 dBm is -59 dBm at 1 m
+			name: "uid",
+			data: "00 EE 8B0CA750E5A0B7F1E0E7 000000000001 0000",
+			want: &Beacon{
+				Format: BeaconEddystone, Namespace: "8b0ca750e5a0b7f1e0e7", Instance: "000000000001",
+				MeasuredPower: -59, HasMeasuredPower: true,
+			},
+		},
+		{
+			name: "uid without reserved bytes",
+			data: "00 EE 8B0CA750E5A0B7F1E0E7 000000000001",
+			want: &Beacon{
+				Format: BeaconEddystone, Namespace: "8b0ca750e5a0b7f1e0e7", Instance: "000000000001",
+				MeasuredPower: -59, HasMeasuredPower: true,
+			},
+		},
+		{
+			name: "url with suffix",
+			data: "10 F4 03 676F6F676C65 07",
+			want: &Beacon{Format: BeaconEddystone, URL: "https://google.com", MeasuredPower: -53, HasMeasuredPower: true},
+		},
+		{
+			name: "url with path",
+			data: "10 EB 00 6578616D706C65 00 696E646578",
+			want: &Beacon{Format: BeaconEddystone, URL: "http://www.example.com/index", MeasuredPower: -62, HasMeasuredPower: true},
+		},
+		{
+			name: "url every expansion",
+			data: "10 EB 02 61 01 62 02 63 03 64 04 65 05 66 06 67 08 68 09",
+			want: &Beacon{
+				Format: BeaconEddystone, URL: "http://a.org/b.edu/c.net/d.info/e.biz/f.gov/g.orgh.edu",
+				MeasuredPower: -62, HasMeasuredPower: true,
+			},
+		},
+		{
+			name: "url scheme only",
+			data: "10 EB 01",
+			want: &Beacon{Format: BeaconEddystone, URL: "https://www.", MeasuredPower: -62, HasMeasuredPower: true},
+		},
+		{
+			// 0x1780 is 23.5 °C in 8.8 fixed point; uptime in 0.1 s units
+			name: "tlm",
+			data: "20 00 0BB8 1780 00000064 00000A00",
+			want: &Beacon{
+				Format: BeaconEddystone, HasTelemetry: true, BatteryMV: 3000, Temperature: 23.5,
+				AdvCount: 100, Uptime: 256 * time.Second,
+			},
+		},
+		{
+			name: "tlm negative temperature",
+			data: "20 00 0C1C FF80 FFFFFFFF 00000001",
+			want: &Beacon{
+				Format: BeaconEddystone, HasTelemetry: true, BatteryMV: 3100, Temperature: -0.5,
+				AdvCount: 4294967295, Uptime: 100 * time.Millisecond,
+			},
+		},
+		{
+			name: "eid",
+			data: "30 EE 0102030405060708",
+			want: &Beacon{Format: BeaconEddystone, EID: "0102030405060708", MeasuredPower: -59, HasMeasuredPower: true},
+		},
+		{
+			// -128 dBm at 0 m would be -169 at 1 m, out of range
+			name: "power clamped",
+			data: "30 80 0102030405060708",
+			want: &Beacon{Format: BeaconEddystone, EID: "0102030405060708", MeasuredPower: -128, HasMeasuredPower: true},
+		},
+		{name: "uid truncated", data: "00 EE 8B0CA750E5A0B7F1E0E7 0000000000"},
+		{name: "url truncated", data: "10 EB"},
+		{name: "url unknown scheme", data: "10 EB 04 61"},
+		{name: "url control character", data: "10 EB 00 61 20 62"},
+		{name: "url non-ascii", data: "10 EB 00 61 80"},
+		{name: "tlm truncated", data: "20 00 0BB8 1780 00000064 00000A"},
+		{name: "tlm encrypted", data: "20 01 0BB8 1780 00000064 00000A00 0000"},
+		{name: "eid truncated", data: "30 EE 01020304050607"},
+		{name: "unknown frame", data: "40 00 0000"},
+		{name: "short", data: "10"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseEddystone(unhex(t, tt.data))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseEddystone = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBeaconMerge(t *testing.T) {
+	b := ParseEddystone(unhex(t, "00 EE 8B0CA750E5A0B7F1E0E7 000000000001"))
+	b.Merge(ParseEddystone(unhex(t, "20 00 0BB8 1780 00000064 00000A00")))
+	b.Merge(ParseEddystone(unhex(t, "10 F4 03 676F6F676C65 07")))
+	want := &Beacon{
+		Format: BeaconEddystone, Namespace: "8b0ca750e5a0b7f1e0e7", Instance: "000000000001",
+		URL: "https://google.com", HasTelemetry: true, BatteryMV: 3000, Temperature: 23.5,
+		AdvCount: 100, Uptime: 256 * time.Second, MeasuredPower: -53, HasMeasuredPower: true,
+	}
+	if !reflect.DeepEqual(b, want) {
+		t.Errorf("merged frames = %+v, want %+v", b, want)
+	}
+	if got := b.Label(); got != "Eddystone google.com" {
+		t.Errorf("Label = %q, want Eddystone google.com", got)
+	}
+
+	// A different format replaces the beacon.
+	ib := ParseIBeacon(companyApple, unhex(t, "02 15 FDA50693A4E24FB1AFCFC6EB07647825 2774 6B5E C5"))
+	b.Merge(ib)
+	if !reflect.DeepEqual(b, ib) {
+		t.Errorf("after iBeacon = %+v, want %+v", b, ib)
+	}
+	if got := b.Label(); got != "iBeacon 10100/27486" {
+		t.Errorf("Label = %q, want iBeacon 10100/27486", got)
+	}
+
+	b.Merge(nil)
+	if !reflect.DeepEqual(b, ib) {
+		t.Error("Merge(nil) changed the beacon")
+	}
+}
@@ -0,0 +1,116 @@
+package bluetooth
+
+// Service UUID and device information bits of BTHome v2.
+const (
+	serviceBTHome        = 0xFCD2
+	btHomeEncrypted      = 0x01
+	btHomeVersionShift   = 5
+	btHomeVersion2       = 2
+	btHomeVariableLength = 0 // Size of text and raw objects: a length byte follows the ID
+	standardGravity      = 9.80665
+)
+
+// btHomeObject describes how to read one BTHome object: its size in bytes
+// (little-endian), signedness, scale factor and the quantity it maps to.
+// Objects without a quantity are skipped; their size still matters to
+// find the next object.
+type btHomeObject struct {
+	size     int
+	signed   bool
+	factor   float64
+	quantity Quantity
+}
+
+// btHomeObjects lists the BTHome v2 object IDs by their fixed sizes. An
+// unknown ID ends decoding, as the rest of the frame cannot be located.
+var btHomeObjects = map[byte]btHomeObject{
+	0x00: {size: 1},                                       // packet id
+	0x01: {size: 1, factor: 1, quantity: QuantityBattery}, // battery %
+	0x02: {size: 2, signed: true, factor: 0.01, quantity: QuantityTemperature},
+	0x03: {size: 2, factor: 0.01, quantity: QuantityHumidity},
+	0x04: {size: 3, factor: 0.01, quantity: QuantityPressure},
+	0x05: {size: 3, factor: 0.01, quantity: QuantityIlluminance},
+	0x06: {size: 2}, 0x07: {size: 2}, // mass kg, lb
+	0x08: {size: 2, signed: true},    // dew point
+	0x09: {size: 1},                  // count
+	0x0A: {size: 3}, 0x0B: {size: 3}, // energy, power
+	0x0C: {size: 2, factor: 0.001, quantity: QuantityVoltage},
+	0x0D: {size: 2}, 0x0E: {size: 2}, // PM2.5, PM10
+	0x0F: {size: 1}, 0x10: {size: 1}, 0x11: {size: 1}, // generic, power, opening
+	0x12: {size: 2}, 0x13: {size: 2}, 0x14: {size: 2}, // CO2, TVOC, moisture
+	0x15: {size: 1}, 0x16: {size: 1}, 0x17: {size: 1}, 0x18: {size: 1},
+	0x19: {size: 1}, 0x1A: {size: 1}, 0x1B: {size: 1}, 0x1C: {size: 1},
+	0x1D: {size: 1}, 0x1E: {size: 1}, 0x1F: {size: 1}, 0x20: {size: 1},
+	0x21: {size: 1, factor: 1, quantity: QuantityMotion}, // motion
+	0x22: {size: 1, factor: 1, quantity: QuantityMotion}, // moving
+	0x23: {size: 1}, 0x24: {size: 1}, 0x25: {size: 1}, 0x26: {size: 1},
+	0x27: {size: 1}, 0x28: {size: 1}, 0x29: {size: 1}, 0x2A: {size: 1},
+	0x2B: {size: 1}, 0x2C: {size: 1}, 0x2D: {size: 1},
+	0x2E: {size: 1, factor: 1, quantity: QuantityHumidity},
+	0x2F: {size: 1},                  // moisture %
+	0x3A: {size: 1}, 0x3C: {size: 2}, // button, dimmer
+	0x3D: {size: 2}, 0x3E: {size: 4}, 0x3F: {size: 2}, // count, count, rotation
+	0x40: {size: 2}, 0x41: {size: 2}, 0x42: {size: 3}, // distance mm, m, duration
+	0x43: {size: 2}, 0x44: {size: 2}, // current, speed
+	0x45: {size: 2, signed: true, factor: 0.1, quantity: QuantityTemperature},
+	0x46: {size: 1}, 0x47: {size: 2}, 0x48: {size: 2}, 0x49: {size: 2}, // UV, volumes, flow
+	0x4A: {size: 2, factor: 0.1, quantity: QuantityVoltage},
+	0x4B: {size: 3}, 0x4C: {size: 4}, 0x4D: {size: 4}, 0x4E: {size: 4}, // gas, energy, volume
+	0x4F: {size: 4}, 0x50: {size: 4}, // water, timestamp
+	0x51: {size: 2, factor: 0.001 / standardGravity, quantity: QuantityAcceleration},
+	0x52: {size: 2},                    // gyroscope
+	0x53: {size: btHomeVariableLength}, // text
+	0x54: {size: btHomeVariableLength}, // raw
+	0x55: {size: 4}, 0x56: {size: 2},   // volume storage, conductivity
+	0x57: {size: 1, signed: true, factor: 1, quantity: QuantityTemperature},
+	0x58: {size: 1, signed: true, factor: 0.35, quantity: QuantityTemperature},
+	0x59: {size: 1}, 0x5A: {size: 2}, 0x5B: {size: 4}, // signed counts
+	0x5C: {size: 4}, 0x5D: {size: 2}, // power, current
+}
+
+// ParseBTHome decodes BTHome v2 service data. Encrypted frames cannot be
+// read without the device key and return nil, as do other versions.
+func ParseBTHome(data []byte) *Sensor {
+	if len(data) < 1 || data[0]&btHomeEncrypted != 0 || data[0]>>btHomeVersionShift != btHomeVersion2 {
+		return nil
+	}
+	s := &Sensor{Format: SensorBTHome}
+	for rest := data[1:]; len(rest) >= 1; {
+		obj, ok := btHomeObjects[rest[0]]
+		if !ok {
+			break
+		}
+		rest = rest[1:]
+		size := obj.size
+		if size == btHomeVariableLength {
+			if len(rest) < 1 {
+				break
+			}
+			size, rest = int(rest[0]), rest[1:]
+		}
+		if size > len(rest) {
+			break
+		}
+		if obj.quantity != 0 {
+			s.set(obj.quantity, littleEndian(rest[:size], obj.signed)*obj.factor)
+		}
+		rest = rest[size:]
+	}
+	if len(s.Readings) == 0 {
+		return nil
+	}
+	return s
+}
+
+// littleEndian reads an unsigned or two's complement integer of up to
+// four bytes.
+func littleEndian(b []byte, signed bool) float64 {
+	var v uint64
+	for i := len(b) - 1; i >= 0; i-- {
+		v = v<<8 | uint64(b[i])
+	}
+	if signed && len(b) > 0 && b[len(b)-1]&0x80 != 0 {
+		return float64(int64(v) - int64(1)<<(8*len(b)))
+	}
+	return float64(v)
+}
@@ -0,0 +1,190 @@
+package bluetooth
+
+import "strings"
+
+// DeviceClass is a Bluetooth Class of Device: service class bits 13-23,
+// major class bits 8-12 and minor class bits 2-7.
+// See: Bluetooth Assigned Numbers, section 2.8 (Class of Device)
+type DeviceClass uint32
+
+// MajorClass is the major device class of a DeviceClass.
+type MajorClass uint8
+
+const (
+	MajorMisc          MajorClass = 0x00
+	MajorComputer      MajorClass = 0x01
+	MajorPhone         MajorClass = 0x02
+	MajorNetwork       MajorClass = 0x03 // LAN/Network Access Point
+	MajorAudioVideo    MajorClass = 0x04
+	MajorPeripheral    MajorClass = 0x05 // Keyboard, mouse, joystick...
+	MajorImaging       MajorClass = 0x06
+	MajorWearable      MajorClass = 0x07
+	MajorToy           MajorClass = 0x08
+	MajorHealth        MajorClass = 0x09
+	MajorUncategorized MajorClass = 0x1F
+)
+
+var majorClasses = map[MajorClass]struct {
+	name  string
+	glyph string // Radar and device list marker
+}{
+	MajorMisc:          {"Miscellaneous", "B"},
+	MajorComputer:      {"Computer", "C"},
+	MajorPhone:         {"Phone", "P"},
+	MajorNetwork:       {"Network access point", "N"},
+	MajorAudioVideo:    {"Audio/Video", "A"},
+	MajorPeripheral:    {"Peripheral", "K"},
+	MajorImaging:       {"Imaging", "I"},
+	MajorWearable:      {"Wearable", "O"},
+	MajorToy:           {"Toy", "T"},
+	MajorHealth:        {"Health", "H"},
+	MajorUncategorized: {"Uncategorized", "B"},
+}
+
+// String returns the name of the major class, e.g. "Audio/Video".
+func (m MajorClass) String() string {
+	if c, ok := majorClasses[m]; ok {
+		return c.name
+	}
+	return "Reserved"
+}
+
+// Minor class names indexed by the 6-bit minor class, per major class.
+// Peripheral and imaging minor classes are bit fields, see MinorName.
+var (
+	computerMinors = []string{"", "Desktop", "Server", "Laptop", "Handheld PC/PDA",
+		"Palm-size PC/PDA", "Wearable computer", "Tablet"}
+	phoneMinors = []string{"", "Cellular", "Cordless", "Smartphone",
+		"Modem or voice gateway", "ISDN access"}
+	audioVideoMinors = []string{"", "Headset", "Hands-free", "", "Microphone",
+		"Loudspeaker", "Headphones", "Portable audio", "Car audio", "Set-top box",
+		"HiFi audio", "VCR", "Video camera", "Camcorder", "Video monitor",
+		"Video display and loudspeaker", "Video conferencing", "", "Gaming/toy"}
+	wearableMinors = []string{"", "Wristwatch", "Pager", "Jacket", "Helmet", "Glasses", "Pin"}
+	toyMinors      = []string{"", "Robot", "Vehicle", "Doll/action figure", "Controller", "Game"}
+	healthMinors   = []string{"", "Blood pressure monitor", "Thermometer", "Weighing scale",
+		"Glucose meter", "Pulse oximeter", "Heart rate monitor", "Health data display",
+		"Step counter", "Body composition analyzer", "Peak flow monitor",
+		"Medication monitor", "Knee prosthesis", "Ankle prosthesis",
+		"Generic health manager", "Personal mobility device"}
+
+	peripheralKinds   = []string{"", "Keyboard", "Pointing device", "Keyboard and pointing device"}
+	peripheralDevices = []string{"", "Joystick", "Gamepad", "Remote control", "Sensing device",
+		"Digitizer tablet", "Card reader", "Digital pen", "Handheld scanner", "Gestural input"}
+	imagingKinds = []string{"Display", "Camera", "Scanner", "Printer"} // Bits 2-5 of the minor class
+)
+
+// Service class bits, from bit 13 up.
+var serviceClasses = []string{"Limited discoverable", "LE audio", "", "Positioning",
+	"Networking", "Rendering", "Capturing", "Object transfer", "Audio", "Telephony",
+	"Information"}
+
+// Major returns the major device class.
+func (c DeviceClass) Major() MajorClass {
+	return MajorClass(c >> 8 & 0x1F)
+}
+
+// Minor returns the 6-bit minor device class, whose meaning depends on
+// the major class.
+func (c DeviceClass) Minor() uint8 {
+	return uint8(c >> 2 & 0x3F)
+}
+
+// MinorName names the minor device class, e.g. "Headphones", or returns ""
+// if it is uncategorized or reserved.
+func (c DeviceClass) MinorName() string {
+	minor := int(c.Minor())
+	pick := func(names []string) string {
+		if minor < len(names) {
+			return names[minor]
+		}
+		return ""
+	}
+	switch c.Major() {
+	case MajorComputer:
+		return pick(computerMinors)
+	case MajorPhone:
+		return pick(phoneMinors)
+	case MajorNetwork:
+		if load := minor >> 3; load > 0 {
+			return []string{"", "1-17% utilized", "17-33% utilized", "33-50% utilized",
+				"50-67% utilized", "67-83% utilized", "83-99% utilized", "No service available"}[load]
+		}
+		return "Fully available"
+	case MajorAudioVideo:
+		return pick(audioVideoMinors)
+	case MajorPeripheral:
+		kind, dev := minor>>4, minor&0x0F
+		var parts []string
+		if kind > 0 {
+			parts = append(parts, peripheralKinds[kind])
+		}
+		if dev < len(peripheralDevices) && dev > 0 {
+			parts = append(parts, peripheralDevices[dev])
+		}
+		return strings.Join(parts, ", ")
+	case MajorImaging:
+		var parts []string
+		for i, name := range imagingKinds {
+			if minor&(1<<(i+2)) != 0 {
+				parts = append(parts, name)
+			}
+		}
+		return strings.Join(parts, ", ")
+	case MajorWearable:
+		return pick(wearableMinors)
+	case MajorToy:
+		return pick(toyMinors)
+	case MajorHealth:
+		return pick(healthMinors)
+	}
+	return ""
+}
+
+// Services lists the service classes set, e.g. ["Audio", "Telephony"].
+func (c DeviceClass) Services() []string {
+	var names []string
+	for i, name := range serviceClasses {
+		if name != "" && c&(1<<(13+i)) != 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// String describes the major and minor class, e.g. "Audio/Video:
+// Headphones".
+func (c DeviceClass) String() string {
+	if minor := c.MinorName(); minor != "" {
+		return c.Major().String() + ": " + minor
+	}
+	return c.Major().String()
+}
+
+// Label returns the most specific class name, e.g. "Headphones", or ""
+// for a zero class. Access points are labelled by their major class, as
+// their minor class is a load figure.
+func (c DeviceClass) Label() string {
+	if c == 0 {
+		return ""
+	}
+	if minor := c.MinorName(); minor != "" && c.Major() != MajorNetwork {
+		return minor
+	}
+	return c.Major().String()
+}
+
+// Glyph returns the marker for classic devices of this major class: "B"
+// when it gives no hint.
+func (c DeviceClass) Glyph() string {
+	return c.Major().Glyph()
+}
+
+// Glyph returns the radar and device list marker of the major class, "B"
+// for miscellaneous, uncategorized and reserved classes.
+func (m MajorClass) Glyph() string {
+	if c, ok := majorClasses[m]; ok {
+		return c.glyph
+	}
+	return "B"
+}
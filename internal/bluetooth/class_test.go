@@ -0,0 +1,79 @@
+package bluetooth
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDeviceClass(t *testing.T) {
+	tests := []struct {
+		class    DeviceClass
+		major    MajorClass
+		minor    string
+		services []string
+		label    string
+		glyph    string
+	}{
+		// Headphones with Audio and Rendering services
+		{0x240418, MajorAudioVideo, "Headphones", []string{"Rendering", "Audio"}, "Headphones", "A"},
+		// Hands-free headset: Audio, Telephony
+		{0x600408, MajorAudioVideo, "Hands-free", []string{"Audio", "Telephony"}, "Hands-free", "A"},
+		// Laptop with Networking, Capturing, Object transfer, Audio, Telephony
+		{0x7A010C, MajorComputer, "Laptop", []string{"Networking", "Capturing", "Object transfer", "Audio", "Telephony"}, "Laptop", "C"},
+		// Smartphone with Networking, Capturing, Object transfer, Audio, Telephony
+		{0x7A020C, MajorPhone, "Smartphone", []string{"Networking", "Capturing", "Object transfer", "Audio", "Telephony"}, "Smartphone", "P"},
+		// Keyboard: peripheral kind in the top two minor bits
+		{0x002540, MajorPeripheral, "Keyboard", []string{"Limited discoverable"}, "Keyboard", "K"},
+		// Combo keyboard and pointing device acting as a gamepad
+		{0x0005C8, MajorPeripheral, "Keyboard and pointing device, Gamepad", nil, "Keyboard and pointing device, Gamepad", "K"},
+		// Imaging minor classes are bit fields
+		{0x040680, MajorImaging, "Printer", []string{"Rendering"}, "Printer", "I"},
+		{0x0006C0, MajorImaging, "Scanner, Printer", nil, "Scanner, Printer", "I"},
+		// Access point load: labelled by the major class
+		{0x020300, MajorNetwork, "Fully available", []string{"Networking"}, "Network access point", "N"},
+		{0x0203A0, MajorNetwork, "67-83% utilized", []string{"Networking"}, "Network access point", "N"},
+		{0x000704, MajorWearable, "Wristwatch", nil, "Wristwatch", "O"},
+		{0x000804, MajorToy, "Robot", nil, "Robot", "T"},
+		{0x000910, MajorHealth, "Glucose meter", nil, "Glucose meter", "H"},
+		// Reserved minor class
+		{0x0004FC, MajorAudioVideo, "", nil, "Audio/Video", "A"},
+		{0x001F00, MajorUncategorized, "", nil, "Uncategorized", "B"},
+		{0x000000, MajorMisc, "", nil, "", "B"},
+		{0x001500, MajorClass(0x15), "", nil, "Reserved", "B"},
+	}
+	for _, tt := range tests {
+		c := tt.class
+		if got := c.Major(); got != tt.major {
+			t.Errorf("0x%06X Major = %v, want %v", uint32(c), got, tt.major)
+		}
+		if got := c.MinorName(); got != tt.minor {
+			t.Errorf("0x%06X MinorName = %q, want %q", uint32(c), got, tt.minor)
+		}
+		if got := c.Services(); !reflect.DeepEqual(got, tt.services) {
+			t.Errorf("0x%06X Services = %q, want %q", uint32(c), got, tt.services)
+		}
+		if got := c.Label(); got != tt.label {
+			t.Errorf("0x%06X Label = %q, want %q", uint32(c), got, tt.label)
+		}
+		if got := c.Glyph(); got != tt.glyph {
+			t.Errorf("0x%06X Glyph = %q, want %q", uint32(c), got, tt.glyph)
+		}
+	}
+}
+
+func TestDeviceClassString(t *testing.T) {
+	tests := []struct {
+		class DeviceClass
+		want  string
+	}{
+		{0x240418, "Audio/Video: Headphones"},
+		{0x0004FC, "Audio/Video"},
+		{0x000000, "Miscellaneous"},
+		{0x001500, "Reserved"},
+	}
+	for _, tt := range tests {
+		if got := tt.class.String(); got != tt.want {
+			t.Errorf("DeviceClass(0x%06X).String() = %q, want %q", uint32(tt.class), got, tt.want)
+		}
+	}
+}
@@ -3,47 +3,97 @@ package bluetooth
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"os/exec"
 	"strings"
+	"sync"
 	"time"
-
-	tea "github.com/charmbracelet/bubbletea"
 )
 
-// ClassicScanner discovers classic Bluetooth devices via hcitool.
+func init() {
+	Register(Registration{
+		Name:        "classic",
+		Description: "Classic Bluetooth inquiry via BlueZ (hcitool fallback)",
+		Default:     true,
+		Available:   ClassicScannerAvailable,
+		New: func(opts ScannerOptions) (Scanner, error) {
+			if err := CheckAdapter(opts.Adapter); err != nil {
+				return nil, err
+			}
+			return NewClassicScanner(opts.Adapter, opts.ClassicInterval), nil
+		},
+	})
+}
+
+// classicBackend runs one classic inquiry on an adapter and passes each
+// device found to found, returning when the inquiry ends or ctx is done.
+type classicBackend interface {
+	inquire(ctx context.Context, adapter string, found func(DeviceDiscoveredMsg)) error
+}
+
+// ClassicScanner discovers classic Bluetooth devices through BlueZ over
+// D-Bus, or via hcitool where bluetoothd is not running.
 type ClassicScanner struct {
-	program  *tea.Program
-	running  bool
+	health
+	sink     Sink
 	cancel   context.CancelFunc
+	adapter  string
 	interval time.Duration
+
+	mu       sync.Mutex // Guards backend
+	backend  classicBackend
+	fallback classicBackend // Takes over when backend fails, nil if none
 }
 
-// NewClassicScanner creates a classic BT scanner.
-func NewClassicScanner(interval time.Duration) *ClassicScanner {
-	return &ClassicScanner{
+// NewClassicScanner creates a classic BT scanner for the given adapter.
+func NewClassicScanner(adapter string, interval time.Duration) *ClassicScanner {
+	s := &ClassicScanner{
+		adapter:  adapter,
 		interval: interval,
+		backend:  hcitoolBackend{},
 	}
+	if bus, err := bluezSystemBus(); err == nil {
+		s.backend = &bluezBackend{bus: bus, window: classicInquiry}
+		if _, err := exec.LookPath("hcitool"); err == nil {
+			s.fallback = hcitoolBackend{}
+		}
+	}
+	return s
+}
+
+// Name implements Scanner.
+func (s *ClassicScanner) Name() string { return "classic" }
+
+// Capabilities implements Scanner. hcitool reports no RSSI.
+func (s *ClassicScanner) Capabilities() Capability {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.backend.(hcitoolBackend); ok {
+		return CapClassic
+	}
+	return CapClassic | CapRSSI
 }
 
 // Start begins periodic classic BT scans in a goroutine.
-func (s *ClassicScanner) Start(p *tea.Program) error {
-	s.program = p
-	s.running = true
+func (s *ClassicScanner) Start(ctx context.Context, sink Sink) error {
+	s.sink = sink
 
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(ctx)
 	s.cancel = cancel
+	s.reset()
 
 	go s.loop(ctx)
 	return nil
 }
 
 func (s *ClassicScanner) loop(ctx context.Context) {
+	defer s.set(StateStopped, nil)
 	for {
-		if !s.running {
+		if ctx.Err() != nil {
 			return
 		}
-		s.scan()
+		s.scan(ctx)
 		select {
 		case <-ctx.Done():
 			return
@@ -52,17 +102,44 @@ func (s *ClassicScanner) loop(ctx context.Context) {
 	}
 }
 
-func (s *ClassicScanner) scan() {
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+func (s *ClassicScanner) scan(ctx context.Context) {
+	found := func(msg DeviceDiscoveredMsg) {
+		s.emit(s.sink, msg)
+	}
+	err := s.backend.inquire(ctx, s.adapter, found)
+	if err != nil && s.fallback != nil && ctx.Err() == nil &&
+		!errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
+		// BlueZ refused the inquiry, e.g. bluetoothd went away or denies
+		// discovery to this user: hcitool talks to the adapter directly.
+		s.mu.Lock()
+		s.backend, s.fallback = s.fallback, nil
+		s.mu.Unlock()
+		err = s.backend.inquire(ctx, s.adapter, found)
+	}
+	if err != nil {
+		s.set(StateFailed, ClassicScanErrorMsg{err})
+		return
+	}
+	// A round that succeeds after a failed one recovers; Err keeps the
+	// last failure.
+	s.set(StateRunning, nil)
+}
+
+// hcitoolBackend runs `hcitool scan`, which is deprecated and reports
+// neither RSSI nor Class of Device.
+type hcitoolBackend struct{}
+
+func (hcitoolBackend) inquire(parent context.Context, adapter string, found func(DeviceDiscoveredMsg)) error {
+	ctx, cancel := context.WithTimeout(parent, 15*time.Second)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "hcitool", "scan", "--flush")
+	cmd := exec.CommandContext(ctx, "hcitool", "-i", adapter, "scan", "--flush")
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
-		return
+		return err
 	}
 	if err := cmd.Start(); err != nil {
-		return
+		return err
 	}
 
 	scanner := bufio.NewScanner(stdout)
@@ -85,23 +162,23 @@ func (s *ClassicScanner) scan() {
 			continue
 		}
 
-		msg := DeviceDiscoveredMsg{
-			MAC:  mac,
-			Name: name,
-			RSSI: -75, // hcitool scan doesn't provide RSSI; use default
-			Type: DeviceTypeClassic,
-		}
-		if s.program != nil {
-			s.program.Send(msg)
-		}
+		found(DeviceDiscoveredMsg{
+			MAC:     mac,
+			Name:    name,
+			RSSI:    -75, // hcitool scan doesn't provide RSSI; use default
+			Type:    DeviceTypeClassic,
+			Adapter: adapter,
+
+			AddressType: AddressPublic, // BR/EDR addresses are always public
+		})
 	}
 
 	_ = cmd.Wait()
+	return nil
 }
 
 // Stop halts the classic scanner.
 func (s *ClassicScanner) Stop() {
-	s.running = false
 	if s.cancel != nil {
 		s.cancel()
 	}
@@ -125,13 +202,17 @@ func isValidMAC(mac string) bool {
 	return true
 }
 
-// ClassicScannerAvailable checks if hcitool is available on the system.
+// ClassicScannerAvailable checks if bluetoothd is running or hcitool is
+// available on the system.
 func ClassicScannerAvailable() bool {
+	if _, err := bluezSystemBus(); err == nil {
+		return true
+	}
 	_, err := exec.LookPath("hcitool")
 	return err == nil
 }
 
-// ClassicScanErrorMsg reports hcitool errors.
+// ClassicScanErrorMsg reports classic inquiry errors.
 type ClassicScanErrorMsg struct {
 	Err error
 }
@@ -0,0 +1,272 @@
+package bluetooth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// BlueZ D-Bus names used by the classic discovery backend.
+const (
+	bluezService         = "org.bluez"
+	bluezAdapter1        = "org.bluez.Adapter1"
+	bluezDevice1         = "org.bluez.Device1"
+	dbusProperties       = "org.freedesktop.DBus.Properties"
+	dbusObjectManager    = "org.freedesktop.DBus.ObjectManager"
+	bluezErrorInProgress = "org.bluez.Error.InProgress"
+)
+
+// classicInquiry is how long one BlueZ discovery round runs. A BR/EDR
+// inquiry takes about 10 s; the rest lets late results arrive.
+const classicInquiry = 12 * time.Second
+
+// bluezBus is the part of the system bus the BlueZ backend uses, so that
+// it can run against a fake BlueZ service.
+type bluezBus interface {
+	// Call invokes method on the BlueZ object at path and stores the
+	// reply in ret unless ret is nil.
+	Call(ctx context.Context, path dbus.ObjectPath, method string, ret any, args ...any) error
+	// Subscribe delivers the PropertiesChanged and InterfacesAdded signals
+	// of BlueZ to ch until the returned function is called.
+	Subscribe(ch chan<- *dbus.Signal) (func(), error)
+}
+
+// systemBus is a bluezBus on the D-Bus system bus.
+type systemBus struct {
+	conn *dbus.Conn
+}
+
+// bluezSystemBus connects to the system bus and checks that bluetoothd
+// is running.
+func bluezSystemBus() (*systemBus, error) {
+	conn, err := dbus.SystemBus()
+	if err != nil {
+		return nil, fmt.Errorf("connecting to the system bus: %w", err)
+	}
+	var running bool
+	err = conn.BusObject().Call("org.freedesktop.DBus.NameHasOwner", 0, bluezService).Store(&running)
+	if err != nil {
+		return nil, err
+	}
+	if !running {
+		return nil, errors.New("bluetoothd is not running")
+	}
+	return &systemBus{conn: conn}, nil
+}
+
+// Call implements bluezBus.
+func (b *systemBus) Call(ctx context.Context, path dbus.ObjectPath, method string, ret any, args ...any) error {
+	call := b.conn.Object(bluezService, path).CallWithContext(ctx, method, 0, args...)
+	if call.Err != nil || ret == nil {
+		return call.Err
+	}
+	return call.Store(ret)
+}
+
+// bluezSignals selects the signals the backend follows.
+var bluezSignals = [][]dbus.MatchOption{
+	{dbus.WithMatchSender(bluezService), dbus.WithMatchInterface(dbusProperties), dbus.WithMatchMember("PropertiesChanged")},
+	{dbus.WithMatchSender(bluezService), dbus.WithMatchInterface(dbusObjectManager), dbus.WithMatchMember("InterfacesAdded")},
+}
+
+// Subscribe implements bluezBus.
+func (b *systemBus) Subscribe(ch chan<- *dbus.Signal) (func(), error) {
+	for i, match := range bluezSignals {
+		if err := b.conn.AddMatchSignal(match...); err != nil {
+			for _, m := range bluezSignals[:i] {
+				_ = b.conn.RemoveMatchSignal(m...)
+			}
+			return nil, err
+		}
+	}
+	b.conn.Signal(ch)
+	return func() {
+		b.conn.RemoveSignal(ch)
+		for _, m := range bluezSignals {
+			_ = b.conn.RemoveMatchSignal(m...)
+		}
+	}, nil
+}
+
+// bluezBackend runs classic discovery through BlueZ, which reports the
+// RSSI, Class of Device and alias of every device it finds.
+type bluezBackend struct {
+	bus    bluezBus
+	window time.Duration
+}
+
+// bluezDevice is the last known state of an org.bluez.Device1 object.
+type bluezDevice struct {
+	address string
+	name    string
+	alias   string
+	class   uint32
+	classic bool // Has a Class of Device, so it answered a BR/EDR inquiry
+	rssi    int16
+	hasRSSI bool
+}
+
+// update applies changed Device1 properties and reports whether any that
+// a discovery message carries changed.
+func (d *bluezDevice) update(props map[string]dbus.Variant, invalidated []string) bool {
+	changed := false
+	for key, v := range props {
+		switch key {
+		case "Address":
+			changed = v.Store(&d.address) == nil || changed
+		case "Name":
+			changed = v.Store(&d.name) == nil || changed
+		case "Alias":
+			changed = v.Store(&d.alias) == nil || changed
+		case "Class":
+			if v.Store(&d.class) == nil {
+				d.classic, changed = true, true
+			}
+		case "RSSI":
+			if v.Store(&d.rssi) == nil {
+				d.hasRSSI, changed = true, true
+			}
+		}
+	}
+	for _, key := range invalidated {
+		if key == "RSSI" {
+			d.hasRSSI = false
+		}
+	}
+	return changed
+}
+
+// displayName prefers an alias set by the user over the remote name. BlueZ
+// defaults the alias to the name, or to the address with dashes.
+func (d *bluezDevice) displayName() string {
+	if d.alias != "" && d.alias != strings.ReplaceAll(d.address, ":", "-") {
+		return d.alias
+	}
+	return d.name
+}
+
+// inquire runs one discovery round on adapter, restricted to BR/EDR, and
+// reports every classic device BlueZ hears until the round ends or ctx is
+// done.
+func (b *bluezBackend) inquire(ctx context.Context, adapter string, found func(DeviceDiscoveredMsg)) error {
+	signals := make(chan *dbus.Signal, 64)
+	unsubscribe, err := b.bus.Subscribe(signals)
+	if err != nil {
+		return fmt.Errorf("subscribing to BlueZ signals: %w", err)
+	}
+	defer unsubscribe()
+
+	path := dbus.ObjectPath("/org/bluez/" + adapter)
+	filter := map[string]dbus.Variant{"Transport": dbus.MakeVariant("bredr")}
+	if err := b.bus.Call(ctx, path, bluezAdapter1+".SetDiscoveryFilter", nil, filter); err != nil {
+		return fmt.Errorf("setting discovery filter: %w", err)
+	}
+
+	// Devices BlueZ already knows only report changed properties, so
+	// start from their full state.
+	var objects map[dbus.ObjectPath]map[string]map[string]dbus.Variant
+	if err := b.bus.Call(ctx, "/", dbusObjectManager+".GetManagedObjects", &objects); err != nil {
+		return fmt.Errorf("querying BlueZ (is bluetoothd running?): %w", err)
+	}
+	devices := make(map[dbus.ObjectPath]*bluezDevice)
+	for p, ifaces := range objects {
+		if props, ok := ifaces[bluezDevice1]; ok && onAdapter(p, path) {
+			d := &bluezDevice{}
+			d.update(props, nil)
+			devices[p] = d
+			b.report(d, adapter, found)
+		}
+	}
+
+	err = b.bus.Call(ctx, path, bluezAdapter1+".StartDiscovery", nil)
+	var dbusErr dbus.Error
+	if err != nil && !(errors.As(err, &dbusErr) && dbusErr.Name == bluezErrorInProgress) {
+		return fmt.Errorf("starting discovery: %w", err)
+	}
+	defer func() {
+		stopCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		_ = b.bus.Call(stopCtx, path, bluezAdapter1+".StopDiscovery", nil)
+	}()
+
+	window := time.NewTimer(b.window)
+	defer window.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-window.C:
+			return nil
+		case sig := <-signals:
+			p, props, invalidated, ok := deviceProperties(sig)
+			if !ok || !onAdapter(p, path) {
+				continue
+			}
+			d, known := devices[p]
+			if !known {
+				d = &bluezDevice{}
+				devices[p] = d
+			}
+			if d.update(props, invalidated) {
+				b.report(d, adapter, found)
+			}
+		}
+	}
+}
+
+// report passes on a device heard over BR/EDR with a current RSSI.
+func (b *bluezBackend) report(d *bluezDevice, adapter string, found func(DeviceDiscoveredMsg)) {
+	if !d.classic || !d.hasRSSI || !isValidMAC(d.address) {
+		return
+	}
+	found(DeviceDiscoveredMsg{
+		MAC:           d.address,
+		Name:          d.displayName(),
+		RSSI:          d.rssi,
+		Type:          DeviceTypeClassic,
+		Adapter:       adapter,
+		ClassOfDevice: DeviceClass(d.class),
+
+		AddressType: AddressPublic, // BR/EDR addresses are always public
+	})
+}
+
+// deviceProperties extracts the Device1 properties carried by a
+// PropertiesChanged or InterfacesAdded signal.
+func deviceProperties(sig *dbus.Signal) (dbus.ObjectPath, map[string]dbus.Variant, []string, bool) {
+	switch sig.Name {
+	case dbusProperties + ".PropertiesChanged":
+		if len(sig.Body) < 2 {
+			return "", nil, nil, false
+		}
+		iface, _ := sig.Body[0].(string)
+		props, ok := sig.Body[1].(map[string]dbus.Variant)
+		if iface != bluezDevice1 || !ok {
+			return "", nil, nil, false
+		}
+		var invalidated []string
+		if len(sig.Body) >= 3 {
+			invalidated, _ = sig.Body[2].([]string)
+		}
+		return sig.Path, props, invalidated, true
+	case dbusObjectManager + ".InterfacesAdded":
+		if len(sig.Body) < 2 {
+			return "", nil, nil, false
+		}
+		path, _ := sig.Body[0].(dbus.ObjectPath)
+		ifaces, _ := sig.Body[1].(map[string]map[string]dbus.Variant)
+		props, ok := ifaces[bluezDevice1]
+		return path, props, nil, ok
+	}
+	return "", nil, nil, false
+}
+
+// onAdapter reports whether the object at p belongs to the adapter at
+// adapter, e.g. /org/bluez/hci0/dev_AA_BB_CC_DD_EE_FF.
+func onAdapter(p, adapter dbus.ObjectPath) bool {
+	return strings.HasPrefix(string(p), string(adapter)+"/")
+}
@@ -0,0 +1,260 @@
+package bluetooth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// fakeBus is a BlueZ service that answers GetManagedObjects with objects
+// and emits signals once discovery starts.
+type fakeBus struct {
+	objects  map[dbus.ObjectPath]map[string]map[string]dbus.Variant
+	signals  []*dbus.Signal
+	startErr error
+
+	calls []string
+	ch    chan<- *dbus.Signal
+}
+
+func (f *fakeBus) Call(ctx context.Context, path dbus.ObjectPath, method string, ret any, args ...any) error {
+	f.calls = append(f.calls, string(path)+" "+method)
+	switch method {
+	case dbusObjectManager + ".GetManagedObjects":
+		*(ret.(*map[dbus.ObjectPath]map[string]map[string]dbus.Variant)) = f.objects
+	case bluezAdapter1 + ".StartDiscovery":
+		for _, sig := range f.signals {
+			f.ch <- sig
+		}
+		return f.startErr
+	}
+	return nil
+}
+
+func (f *fakeBus) Subscribe(ch chan<- *dbus.Signal) (func(), error) {
+	f.ch = ch
+	return func() {}, nil
+}
+
+func device(props map[string]any) map[string]map[string]dbus.Variant {
+	v := make(map[string]dbus.Variant, len(props))
+	for k, p := range props {
+		v[k] = dbus.MakeVariant(p)
+	}
+	return map[string]map[string]dbus.Variant{bluezDevice1: v}
+}
+
+func propertiesChanged(path dbus.ObjectPath, props map[string]any, invalidated ...string) *dbus.Signal {
+	return &dbus.Signal{
+		Path: path,
+		Name: dbusProperties + ".PropertiesChanged",
+		Body: []any{bluezDevice1, device(props)[bluezDevice1], invalidated},
+	}
+}
+
+func interfacesAdded(path dbus.ObjectPath, props map[string]any) *dbus.Signal {
+	return &dbus.Signal{
+		Path: "/",
+		Name: dbusObjectManager + ".InterfacesAdded",
+		Body: []any{path, device(props)},
+	}
+}
+
+// inquire runs one short discovery round on hci0 against bus.
+func inquire(t *testing.T, bus *fakeBus) []DeviceDiscoveredMsg {
+	t.Helper()
+	b := &bluezBackend{bus: bus, window: 50 * time.Millisecond}
+	var found []DeviceDiscoveredMsg
+	err := b.inquire(context.Background(), "hci0", func(msg DeviceDiscoveredMsg) {
+		found = append(found, msg)
+	})
+	if err != nil {
+		t.Fatalf("inquire: %v", err)
+	}
+	return found
+}
+
+const (
+	headphones = "/org/bluez/hci0/dev_00_1A_7D_DA_71_13"
+	keyboard   = "/org/bluez/hci0/dev_04_52_C7_11_22_33"
+)
+
+func TestBluezSnapshot(t *testing.T) {
+	bus := &fakeBus{objects: map[dbus.ObjectPath]map[string]map[string]dbus.Variant{
+		"/org/bluez/hci0": {bluezAdapter1: {}},
+		headphones: device(map[string]any{
+			"Address": "00:1A:7D:DA:71:13", "Name": "WH-1000XM4", "Alias": "WH-1000XM4",
+			"Class": uint32(0x240404), "RSSI": int16(-58),
+		}),
+		// LE-only device: no Class of Device
+		"/org/bluez/hci0/dev_C0_00_00_00_00_01": device(map[string]any{
+			"Address": "C0:00:00:00:00:01", "Name": "Tag", "RSSI": int16(-70),
+		}),
+		// Cached, not heard in this round: no RSSI
+		keyboard: device(map[string]any{
+			"Address": "04:52:C7:11:22:33", "Name": "Keyboard", "Class": uint32(0x002540),
+		}),
+	}}
+	found := inquire(t, bus)
+
+	if len(found) != 1 {
+		t.Fatalf("found %d devices, want 1: %+v", len(found), found)
+	}
+	got := found[0]
+	want := DeviceDiscoveredMsg{
+		MAC: "00:1A:7D:DA:71:13", Name: "WH-1000XM4", RSSI: -58, Type: DeviceTypeClassic,
+		Adapter: "hci0", ClassOfDevice: 0x240404, AddressType: AddressPublic,
+	}
+	if got.MAC != want.MAC || got.Name != want.Name || got.RSSI != want.RSSI || got.Type != want.Type ||
+		got.Adapter != want.Adapter || got.ClassOfDevice != want.ClassOfDevice || got.AddressType != want.AddressType {
+		t.Errorf("found %+v, want %+v", got, want)
+	}
+
+	wantCalls := []string{
+		"/org/bluez/hci0 " + bluezAdapter1 + ".SetDiscoveryFilter",
+		"/ " + dbusObjectManager + ".GetManagedObjects",
+		"/org/bluez/hci0 " + bluezAdapter1 + ".StartDiscovery",
+		"/org/bluez/hci0 " + bluezAdapter1 + ".StopDiscovery",
+	}
+	if len(bus.calls) != len(wantCalls) {
+		t.Fatalf("calls = %q, want %q", bus.calls, wantCalls)
+	}
+	for i := range wantCalls {
+		if bus.calls[i] != wantCalls[i] {
+			t.Errorf("call %d = %q, want %q", i, bus.calls[i], wantCalls[i])
+		}
+	}
+}
+
+func TestBluezSignals(t *testing.T) {
+	bus := &fakeBus{
+		objects: map[dbus.ObjectPath]map[string]map[string]dbus.Variant{
+			keyboard: device(map[string]any{
+				"Address": "04:52:C7:11:22:33", "Name": "Keyboard", "Class": uint32(0x002540),
+			}),
+		},
+		signals: []*dbus.Signal{
+			// A cached device is heard: only its RSSI changes.
+			propertiesChanged(keyboard, map[string]any{"RSSI": int16(-61)}),
+			// A new device appears with all its properties.
+			interfacesAdded(headphones, map[string]any{
+				"Address": "00:1A:7D:DA:71:13", "Name": "WH-1000XM4",
+				"Class": uint32(0x240404), "RSSI": int16(-50),
+			}),
+			propertiesChanged(headphones, map[string]any{"RSSI": int16(-52)}),
+		},
+	}
+	found := inquire(t, bus)
+
+	want := []struct {
+		mac  string
+		name string
+		rssi int16
+	}{
+		{"04:52:C7:11:22:33", "Keyboard", -61},
+		{"00:1A:7D:DA:71:13", "WH-1000XM4", -50},
+		{"00:1A:7D:DA:71:13", "WH-1000XM4", -52},
+	}
+	if len(found) != len(want) {
+		t.Fatalf("found %d reports, want %d: %+v", len(found), len(want), found)
+	}
+	for i, w := range want {
+		if found[i].MAC != w.mac || found[i].Name != w.name || found[i].RSSI != w.rssi {
+			t.Errorf("report %d = %s %q %d, want %s %q %d", i,
+				found[i].MAC, found[i].Name, found[i].RSSI, w.mac, w.name, w.rssi)
+		}
+	}
+}
+
+func TestBluezDiscoveryInProgress(t *testing.T) {
+	bus := &fakeBus{
+		startErr: dbus.Error{Name: bluezErrorInProgress},
+		signals: []*dbus.Signal{interfacesAdded(headphones, map[string]any{
+			"Address": "00:1A:7D:DA:71:13", "Class": uint32(0x240404), "RSSI": int16(-50),
+		})},
+	}
+	if found := inquire(t, bus); len(found) != 1 {
+		t.Errorf("found %d devices while another client was discovering, want 1", len(found))
+	}
+
+	bus = &fakeBus{startErr: dbus.Error{Name: "org.bluez.Error.NotReady"}}
+	b := &bluezBackend{bus: bus, window: 50 * time.Millisecond}
+	err := b.inquire(context.Background(), "hci0", func(DeviceDiscoveredMsg) {})
+	var dbusErr dbus.Error
+	if !errors.As(err, &dbusErr) || dbusErr.Name != "org.bluez.Error.NotReady" {
+		t.Errorf("inquire error = %v, want org.bluez.Error.NotReady", err)
+	}
+}
+
+func TestBluezRSSIInvalidated(t *testing.T) {
+	bus := &fakeBus{
+		signals: []*dbus.Signal{
+			interfacesAdded(headphones, map[string]any{
+				"Address": "00:1A:7D:DA:71:13", "Class": uint32(0x240404), "RSSI": int16(-50),
+			}),
+			// Out of range: BlueZ drops the RSSI, then the name resolves.
+			propertiesChanged(headphones, map[string]any{}, "RSSI"),
+			propertiesChanged(headphones, map[string]any{"Name": "WH-1000XM4"}),
+			// Heard again.
+			propertiesChanged(headphones, map[string]any{"RSSI": int16(-70)}),
+		},
+	}
+	found := inquire(t, bus)
+
+	if len(found) != 2 {
+		t.Fatalf("found %d reports, want 2: %+v", len(found), found)
+	}
+	if found[1].RSSI != -70 || found[1].Name != "WH-1000XM4" {
+		t.Errorf("report after invalidation = %q %d, want %q -70", found[1].Name, found[1].RSSI, "WH-1000XM4")
+	}
+}
+
+func TestBluezDisplayName(t *testing.T) {
+	tests := []struct {
+		name, alias, want string
+	}{
+		{"WH-1000XM4", "WH-1000XM4", "WH-1000XM4"},
+		{"WH-1000XM4", "Living room", "Living room"},
+		{"", "00-1A-7D-DA-71-13", ""}, // BlueZ default alias for a nameless device
+		{"WH-1000XM4", "", "WH-1000XM4"},
+	}
+	for _, tt := range tests {
+		d := bluezDevice{address: "00:1A:7D:DA:71:13", name: tt.name, alias: tt.alias}
+		if got := d.displayName(); got != tt.want {
+			t.Errorf("displayName(name %q, alias %q) = %q, want %q", tt.name, tt.alias, got, tt.want)
+		}
+	}
+}
+
+func TestBluezOtherAdapter(t *testing.T) {
+	other := dbus.ObjectPath("/org/bluez/hci1/dev_00_1A_7D_DA_71_13")
+	props := map[string]any{"Address": "00:1A:7D:DA:71:13", "Class": uint32(0x240404), "RSSI": int16(-50)}
+	bus := &fakeBus{
+		objects: map[dbus.ObjectPath]map[string]map[string]dbus.Variant{other: device(props)},
+		signals: []*dbus.Signal{
+			interfacesAdded(other, props),
+			propertiesChanged(other, map[string]any{"RSSI": int16(-40)}),
+		},
+	}
+	if found := inquire(t, bus); len(found) != 0 {
+		t.Errorf("hci0 reported %d devices heard by hci1", len(found))
+	}
+
+	tests := []struct {
+		path, adapter dbus.ObjectPath
+		want          bool
+	}{
+		{"/org/bluez/hci0/dev_00_1A_7D_DA_71_13", "/org/bluez/hci0", true},
+		{"/org/bluez/hci1/dev_00_1A_7D_DA_71_13", "/org/bluez/hci0", false},
+		{"/org/bluez/hci10/dev_00_1A_7D_DA_71_13", "/org/bluez/hci1", false},
+		{"/org/bluez/hci0", "/org/bluez/hci0", false},
+	}
+	for _, tt := range tests {
+		if got := onAdapter(tt.path, tt.adapter); got != tt.want {
+			t.Errorf("onAdapter(%s, %s) = %v, want %v", tt.path, tt.adapter, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package bluetooth
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// stubBackend answers every inquiry with the same devices and error.
+type stubBackend struct {
+	found []DeviceDiscoveredMsg
+	err   error
+	calls *int
+}
+
+func (b stubBackend) inquire(ctx context.Context, adapter string, found func(DeviceDiscoveredMsg)) error {
+	if b.calls != nil {
+		*b.calls++
+	}
+	for _, msg := range b.found {
+		found(msg)
+	}
+	return b.err
+}
+
+// recordSink collects the messages sent to it.
+type recordSink []tea.Msg
+
+func (r *recordSink) Send(msg tea.Msg) { *r = append(*r, msg) }
+
+func TestClassicScanFallsBack(t *testing.T) {
+	var calls int
+	dev := DeviceDiscoveredMsg{MAC: "00:11:22:33:44:55", Type: DeviceTypeClassic}
+	hcitool := stubBackend{found: []DeviceDiscoveredMsg{dev}, calls: &calls}
+
+	tests := []struct {
+		name      string
+		ctx       func() context.Context
+		err       error
+		wantCalls int
+		wantState ScannerState
+	}{
+		{"bluez error", context.Background, errors.New("org.bluez.Error.NotReady"), 1, StateRunning},
+		{"cancelled", func() context.Context {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			return ctx
+		}, context.Canceled, 0, StateFailed},
+		{"timeout", context.Background, context.DeadlineExceeded, 0, StateFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = 0
+			var sink recordSink
+			s := &ClassicScanner{
+				sink:     &sink,
+				backend:  stubBackend{err: tt.err},
+				fallback: hcitool,
+			}
+			s.reset()
+			s.scan(tt.ctx())
+
+			if calls != tt.wantCalls {
+				t.Errorf("fallback inquiries = %d, want %d", calls, tt.wantCalls)
+			}
+			if got := s.Status().State; got != tt.wantState {
+				t.Errorf("state = %v, want %v", got, tt.wantState)
+			}
+			if len(sink) != tt.wantCalls {
+				t.Errorf("delivered %d messages, want %d", len(sink), tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestClassicScanFallbackSticks(t *testing.T) {
+	var calls int
+	s := &ClassicScanner{
+		backend:  stubBackend{err: errors.New("bluetoothd went away")},
+		fallback: stubBackend{calls: &calls},
+	}
+	s.reset()
+	s.scan(context.Background())
+	s.scan(context.Background())
+
+	if calls != 2 {
+		t.Errorf("fallback inquiries = %d, want 2", calls)
+	}
+	if s.fallback != nil {
+		t.Error("fallback still pending after switching to it")
+	}
+}
+
+func TestClassicScanFailure(t *testing.T) {
+	want := errors.New("hcitool: no such device")
+	s := &ClassicScanner{backend: stubBackend{err: want}}
+	s.reset()
+	s.scan(context.Background())
+
+	st := s.Status()
+	if st.State != StateFailed {
+		t.Errorf("state = %v, want %v", st.State, StateFailed)
+	}
+	var scanErr ClassicScanErrorMsg
+	if !errors.As(st.Err, &scanErr) || scanErr.Err != want {
+		t.Errorf("err = %v, want %v", st.Err, want)
+	}
+
+	// A later successful round recovers but keeps the last error.
+	s.backend = stubBackend{}
+	s.scan(context.Background())
+	if st := s.Status(); st.State != StateRunning || st.Err == nil {
+		t.Errorf("after recovery: state = %v, err = %v; want running with the last error", st.State, st.Err)
+	}
+}
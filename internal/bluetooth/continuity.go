@@ -0,0 +1,317 @@
+package bluetooth
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net/netip"
+	"slices"
+)
+
+// Apple Continuity message types carried as type-length-value entries in
+// Apple manufacturer data. iBeacon (0x02) and Find My (0x12) frames are
+// decoded by ParseIBeacon and ParseFindMy instead.
+const (
+	continuityAirPrint          = 0x03
+	continuityAirDrop           = 0x05
+	continuityHomeKit           = 0x06
+	continuityProximityPairing  = 0x07
+	continuityHeySiri           = 0x08
+	continuityAirPlayTarget     = 0x09
+	continuityAirPlaySource     = 0x0A
+	continuityMagicSwitch       = 0x0B
+	continuityHandoff           = 0x0C
+	continuityTetheringTarget   = 0x0D
+	continuityTetheringSource   = 0x0E
+	continuityNearbyAction      = 0x0F
+	continuityNearbyInfo        = 0x10
+	nearbyInfoWiFiFlag          = 0x04
+	handoffClipboardFlag        = 0x08
+	airPodsFlipFlag             = 0x20 // Status bit: clear when the left pod reports in the high nibble
+	airPodsCaseChargingFlag     = 0x04
+	airPodsLidClosedFlag        = 0x08
+	airPodsBatteryUnknownNibble = 0x0F
+)
+
+var continuityNames = map[byte]string{
+	continuityAirPrint:         "AirPrint",
+	continuityAirDrop:          "AirDrop",
+	continuityHomeKit:          "HomeKit",
+	continuityProximityPairing: "Proximity Pairing",
+	continuityHeySiri:          "Hey Siri",
+	continuityAirPlayTarget:    "AirPlay Target",
+	continuityAirPlaySource:    "AirPlay Source",
+	continuityMagicSwitch:      "Magic Switch",
+	continuityHandoff:          "Handoff",
+	continuityTetheringTarget:  "Tethering Target",
+	continuityTetheringSource:  "Instant Hotspot",
+	continuityNearbyAction:     "Nearby Action",
+	continuityNearbyInfo:       "Nearby Info",
+}
+
+// nearbyActivities names the action code in the low nibble of the first
+// Nearby Info byte.
+var nearbyActivities = map[byte]string{
+	0x00: "unknown",
+	0x01: "reporting disabled",
+	0x03: "idle",
+	0x05: "audio playing, locked",
+	0x07: "screen on",
+	0x09: "video playing",
+	0x0A: "on wrist, unlocked",
+	0x0B: "recently used",
+	0x0D: "driving",
+	0x0E: "on a call",
+}
+
+// nearbyActions names the Nearby Action types, sent while the user goes
+// through a setup or sharing flow.
+var nearbyActions = map[byte]string{
+	0x01: "Apple TV setup",
+	0x04: "mobile backup",
+	0x05: "Watch setup",
+	0x06: "Apple TV pairing",
+	0x07: "internet relay",
+	0x08: "Wi-Fi password sharing",
+	0x09: "iOS setup",
+	0x0A: "repair",
+	0x0B: "speaker setup",
+	0x0C: "Apple Pay",
+	0x0D: "whole home audio setup",
+	0x0E: "developer tools pairing",
+	0x0F: "answered call",
+	0x10: "ended call",
+	0x13: "remote autofill",
+	0x14: "companion link",
+	0x15: "remote management",
+	0x17: "remote display",
+}
+
+// airPodsModels maps the Proximity Pairing device model.
+var airPodsModels = map[uint16]string{
+	0x0220: "AirPods",
+	0x0F20: "AirPods 2",
+	0x1320: "AirPods 3",
+	0x1920: "AirPods 4",
+	0x1B20: "AirPods 4 ANC",
+	0x0E20: "AirPods Pro",
+	0x1420: "AirPods Pro 2",
+	0x2420: "AirPods Pro 2 USB-C",
+	0x0A20: "AirPods Max",
+	0x1F20: "AirPods Max USB-C",
+	0x0320: "Powerbeats3",
+	0x0B20: "Powerbeats Pro",
+	0x0520: "BeatsX",
+	0x0620: "Beats Solo3",
+	0x0920: "Beats Studio3",
+	0x0C20: "Beats Solo Pro",
+	0x1020: "Beats Flex",
+	0x1120: "Beats Studio Buds",
+	0x1220: "Beats Fit Pro",
+	0x1620: "Beats Studio Buds+",
+	0x1720: "Beats Studio Pro",
+}
+
+// Device classes derived from Continuity messages, least specific first.
+// Merge keeps the most specific class seen.
+var continuityClassRank = map[string]int{
+	"":                 0,
+	"Mac":              1,
+	"iPhone/iPad":      2,
+	"Apple TV/HomePod": 3,
+	"iPhone":           4,
+	"Apple Watch":      4,
+}
+
+// AirPods holds the pod and case status of a Proximity Pairing message.
+// Battery levels are percentages in steps of 10, or -1 if not reported,
+// e.g. for a pod that is out of range of the case.
+type AirPods struct {
+	Model         string `json:"model"`
+	Left          int    `json:"left_battery"`
+	Right         int    `json:"right_battery"`
+	Case          int    `json:"case_battery"`
+	LeftCharging  bool   `json:"left_charging,omitempty"`
+	RightCharging bool   `json:"right_charging,omitempty"`
+	CaseCharging  bool   `json:"case_charging,omitempty"`
+	LidOpen       bool   `json:"lid_open,omitempty"`
+}
+
+// Continuity holds what the Apple Continuity messages of a device reveal.
+// Devices rotate through messages between advertisements, so a Continuity
+// accumulates them via Merge.
+type Continuity struct {
+	Messages  []string `json:"messages"`             // Message types seen, sorted
+	Class     string   `json:"class,omitempty"`      // Derived device class, e.g. "Apple Watch" or an AirPods model
+	Activity  string   `json:"activity,omitempty"`   // Nearby Info activity, e.g. "screen on"
+	WiFi      string   `json:"wifi,omitempty"`       // on or off, from Nearby Info
+	Clipboard bool     `json:"clipboard,omitempty"`  // Handoff announces copied data
+	Action    string   `json:"action,omitempty"`     // Last Nearby Action, e.g. "Wi-Fi password sharing"
+	AirPlayIP string   `json:"airplay_ip,omitempty"` // Address of an AirPlay target
+	AirPods   *AirPods `json:"airpods,omitempty"`
+}
+
+// ParseContinuity decodes the Continuity messages in Apple manufacturer
+// data. Returns nil if the data carries none.
+func ParseContinuity(companyID uint16, data []byte) *Continuity {
+	if companyID != companyApple {
+		return nil
+	}
+	c := &Continuity{}
+	for len(data) >= 2 {
+		typ, n := data[0], int(data[1])
+		if 2+n > len(data) {
+			break
+		}
+		v := data[2 : 2+n]
+		data = data[2+n:]
+
+		name, ok := continuityNames[typ]
+		if !ok {
+			continue
+		}
+		if !slices.Contains(c.Messages, name) {
+			c.Messages = append(c.Messages, name)
+		}
+		switch typ {
+		case continuityNearbyInfo:
+			// status|action(1) data flags(1) auth tag(3)
+			if len(v) >= 2 {
+				c.Activity = nearbyActivities[v[0]&0x0F]
+				c.WiFi = "off"
+				if v[1]&nearbyInfoWiFiFlag != 0 {
+					c.WiFi = "on"
+				}
+			}
+		case continuityHandoff:
+			// clipboard(1) sequence(2) auth tag(1) encrypted payload(10)
+			if len(v) >= 1 {
+				c.Clipboard = v[0]&handoffClipboardFlag != 0
+			}
+		case continuityNearbyAction:
+			// flags(1) type(1) auth tag(3) [parameters]
+			if len(v) >= 2 {
+				c.Action = nearbyActions[v[1]]
+				if c.Action == "" {
+					c.Action = fmt.Sprintf("type 0x%02X", v[1])
+				}
+			}
+		case continuityAirPlayTarget:
+			// flags(1) config seed(1) IPv4 address(4)
+			if len(v) >= 6 {
+				c.AirPlayIP = netip.AddrFrom4([4]byte(v[2:6])).String()
+			}
+		case continuityProximityPairing:
+			c.AirPods = parseProximityPairing(v)
+		}
+	}
+	if len(c.Messages) == 0 {
+		return nil
+	}
+	slices.Sort(c.Messages)
+	c.Class = c.classify()
+	return c
+}
+
+// parseProximityPairing decodes the status of AirPods and Beats headphones:
+// prefix(1) model(2) status(1) pod battery(1) charging|case battery(1)
+// lid(1) color(1) followed by an encrypted payload.
+func parseProximityPairing(v []byte) *AirPods {
+	if len(v) < 7 {
+		return nil
+	}
+	model := binary.BigEndian.Uint16(v[1:3])
+	a := &AirPods{Model: airPodsModels[model]}
+	if a.Model == "" {
+		a.Model = fmt.Sprintf("Apple audio 0x%04X", model)
+	}
+	// The pod reporting in the high nibble depends on which one is primary.
+	hi, lo := v[4]>>4, v[4]&0x0F
+	charge := v[5] >> 4
+	left, right := hi, lo
+	leftBit, rightBit := byte(0x01), byte(0x02)
+	if v[3]&airPodsFlipFlag != 0 {
+		left, right = lo, hi
+		leftBit, rightBit = 0x02, 0x01
+	}
+	a.Left, a.Right, a.Case = podBattery(left), podBattery(right), podBattery(v[5]&0x0F)
+	a.LeftCharging = charge&leftBit != 0
+	a.RightCharging = charge&rightBit != 0
+	a.CaseCharging = charge&airPodsCaseChargingFlag != 0
+	a.LidOpen = v[6]&airPodsLidClosedFlag == 0
+	return a
+}
+
+// podBattery converts a battery nibble to a percentage, -1 if unknown.
+func podBattery(n byte) int {
+	if n == airPodsBatteryUnknownNibble || n > 10 {
+		return -1
+	}
+	return int(n) * 10
+}
+
+// classify derives the device class from the messages seen. Only iPhones
+// and iPads send Nearby Info together with hotspot, call and driving
+// states; Macs send Handoff without Nearby Info.
+func (c *Continuity) classify() string {
+	has := func(name string) bool { return slices.Contains(c.Messages, name) }
+	switch {
+	case c.AirPods != nil:
+		return c.AirPods.Model
+	case has("Magic Switch") || c.Activity == "on wrist, unlocked":
+		return "Apple Watch"
+	case has("Instant Hotspot") || c.Activity == "driving" || c.Activity == "on a call":
+		return "iPhone"
+	case has("AirPlay Target") && !has("Nearby Info"):
+		return "Apple TV/HomePod"
+	case has("Nearby Info"):
+		return "iPhone/iPad"
+	case has("Handoff"):
+		return "Mac"
+	}
+	return ""
+}
+
+// Merge folds the messages of a later advertisement into c. Message types
+// accumulate, the latest state wins and the most specific class is kept.
+func (c *Continuity) Merge(other *Continuity) {
+	if other == nil {
+		return
+	}
+	for _, m := range other.Messages {
+		if !slices.Contains(c.Messages, m) {
+			c.Messages = append(c.Messages, m)
+		}
+	}
+	slices.Sort(c.Messages)
+	if slices.Contains(other.Messages, "Nearby Info") {
+		c.Activity, c.WiFi = other.Activity, other.WiFi
+	}
+	if slices.Contains(other.Messages, "Handoff") {
+		c.Clipboard = other.Clipboard
+	}
+	if other.Action != "" {
+		c.Action = other.Action
+	}
+	if other.AirPlayIP != "" {
+		c.AirPlayIP = other.AirPlayIP
+	}
+	if other.AirPods != nil {
+		a := *other.AirPods
+		c.AirPods = &a
+	}
+	class := c.classify()
+	if c.AirPods != nil || continuityClassRank[class] >= continuityClassRank[c.Class] {
+		c.Class = class
+	}
+}
+
+// clone returns a deep copy of c.
+func (c *Continuity) clone() *Continuity {
+	cp := *c
+	cp.Messages = slices.Clone(c.Messages)
+	if c.AirPods != nil {
+		a := *c.AirPods
+		cp.AirPods = &a
+	}
+	return &cp
+}
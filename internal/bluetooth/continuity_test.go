@@ -0,0 +1,128 @@
+package bluetooth
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseContinuity(t *testing.T) {
+	tests := []struct {
+		name string
+		data string // Apple manufacturer data after the company ID
+		want *Continuity
+	}{
+		{
+			name: "nearby info",
+			data: "10 05 1B 1C 4B7E3A",
+			want: &Continuity{Messages: []string{"Nearby Info"}, Class: "iPhone/iPad", Activity: "recently used", WiFi: "on"},
+		},
+		{
+			name: "nearby info wifi off",
+			data: "10 05 07 18 4B7E3A",
+			want: &Continuity{Messages: []string{"Nearby Info"}, Class: "iPhone/iPad", Activity: "screen on", WiFi: "off"},
+		},
+		{
+			name: "handoff from a mac",
+			data: "0C 0E 08 1234 5C 00112233445566778899",
+			want: &Continuity{Messages: []string{"Handoff"}, Class: "Mac", Clipboard: true},
+		},
+		{
+			name: "handoff and nearby info",
+			data: "0C 0E 00 1234 5C 00112233445566778899 10 05 0D 1C 4B7E3A",
+			want: &Continuity{Messages: []string{"Handoff", "Nearby Info"}, Class: "iPhone", Activity: "driving", WiFi: "on"},
+		},
+		{
+			name: "watch",
+			data: "0B 03 01 02 03 10 05 0A 1C 4B7E3A",
+			want: &Continuity{Messages: []string{"Magic Switch", "Nearby Info"}, Class: "Apple Watch", Activity: "on wrist, unlocked", WiFi: "on"},
+		},
+		{
+			name: "instant hotspot",
+			data: "0E 06 00 00 64 01 0F 00",
+			want: &Continuity{Messages: []string{"Instant Hotspot"}, Class: "iPhone"},
+		},
+		{
+			name: "airplay target",
+			data: "09 06 03 0A C0A80105",
+			want: &Continuity{Messages: []string{"AirPlay Target"}, Class: "Apple TV/HomePod", AirPlayIP: "192.168.1.5"},
+		},
+		{
+			name: "nearby action",
+			data: "0F 05 90 08 A1B2C3",
+			want: &Continuity{Messages: []string{"Nearby Action"}, Action: "Wi-Fi password sharing"},
+		},
+		{
+			name: "unknown nearby action",
+			data: "0F 05 90 7F A1B2C3",
+			want: &Continuity{Messages: []string{"Nearby Action"}, Action: "type 0x7F"},
+		},
+		{
+			// Right pod primary: left 50 %, right 70 %; right pod and case
+			// charging, case 50 %, lid closed
+			name: "airpods pro",
+			data: "07 19 01 0E20 0B 57 65 09 00 00 00000000000000000000000000000000",
+			want: &Continuity{Messages: []string{"Proximity Pairing"}, Class: "AirPods Pro", AirPods: &AirPods{
+				Model: "AirPods Pro", Left: 50, Right: 70, Case: 50, RightCharging: true, CaseCharging: true,
+			}},
+		},
+		{
+			// Flip bit: the left pod reports in the low nibble; case unknown
+			name: "airpods flipped",
+			data: "07 19 01 1420 2B 57 1F 01 00 00 00000000000000000000000000000000",
+			want: &Continuity{Messages: []string{"Proximity Pairing"}, Class: "AirPods Pro 2", AirPods: &AirPods{
+				Model: "AirPods Pro 2", Left: 70, Right: 50, Case: -1, RightCharging: true, LidOpen: true,
+			}},
+		},
+		{
+			name: "unknown audio model",
+			data: "07 19 01 FF20 0B AA 0F 01 00 00 00000000000000000000000000000000",
+			want: &Continuity{Messages: []string{"Proximity Pairing"}, Class: "Apple audio 0xFF20", AirPods: &AirPods{
+				Model: "Apple audio 0xFF20", Left: 100, Right: 100, Case: -1, LidOpen: true,
+			}},
+		},
+		{name: "ibeacon only", data: "02 15 FDA50693A4E24FB1AFCFC6EB07647825 2774 6B5E C5"},
+		{name: "truncated", data: "10 05 1B 1C"},
+		{name: "empty", data: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseContinuity(companyApple, unhex(t, tt.data))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseContinuity = %+v, want %+v", got, tt.want)
+				if got != nil && tt.want != nil && !reflect.DeepEqual(got.AirPods, tt.want.AirPods) {
+					t.Errorf("AirPods = %+v, want %+v", got.AirPods, tt.want.AirPods)
+				}
+			}
+		})
+	}
+
+	if c := ParseContinuity(0x0006, unhex(t, "10 05 1B 1C 4B7E3A")); c != nil {
+		t.Error("decoded manufacturer data of another company")
+	}
+}
+
+func TestContinuityMerge(t *testing.T) {
+	c := ParseContinuity(companyApple, unhex(t, "0C 0E 08 1234 5C 00112233445566778899"))
+	if c.Class != "Mac" {
+		t.Fatalf("class = %q, want Mac", c.Class)
+	}
+
+	c.Merge(ParseContinuity(companyApple, unhex(t, "10 05 0E 18 4B7E3A")))
+	want := &Continuity{
+		Messages: []string{"Handoff", "Nearby Info"}, Class: "iPhone",
+		Activity: "on a call", WiFi: "off", Clipboard: true,
+	}
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("after Nearby Info = %+v, want %+v", c, want)
+	}
+
+	// A later, less specific state keeps the class learned earlier.
+	c.Merge(ParseContinuity(companyApple, unhex(t, "10 05 07 1C 4B7E3A")))
+	if c.Class != "iPhone" || c.Activity != "screen on" || c.WiFi != "on" {
+		t.Errorf("after screen on: class %q, activity %q, wifi %q; want iPhone, screen on, on", c.Class, c.Activity, c.WiFi)
+	}
+	c.Merge(nil)
+	if len(c.Messages) != 2 {
+		t.Errorf("Merge(nil) changed the messages: %v", c.Messages)
+	}
+}
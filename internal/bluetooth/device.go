@@ -3,7 +3,9 @@ package bluetooth
 import (
 	"crypto/sha256"
 	"encoding/binary"
+	"fmt"
 	"math"
+	"strings"
 	"time"
 )
 
@@ -11,7 +13,7 @@ import (
 type DeviceType int
 
 const (
-	DeviceTypeBLE     DeviceType = iota
+	DeviceTypeBLE DeviceType = iota
 	DeviceTypeClassic
 	DeviceTypeWiFi
 )
@@ -27,18 +29,118 @@ func (dt DeviceType) String() string {
 	}
 }
 
+// ParseDeviceType parses a device type name as accepted on the command line
+// ("ble", "classic"/"cls", "wifi"), case-insensitively.
+func ParseDeviceType(s string) (DeviceType, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "ble":
+		return DeviceTypeBLE, nil
+	case "classic", "cls":
+		return DeviceTypeClassic, nil
+	case "wifi":
+		return DeviceTypeWiFi, nil
+	}
+	return 0, fmt.Errorf("unknown device type %q (want ble, classic or wifi)", s)
+}
+
 // Device represents a discovered Bluetooth or WiFi device.
 type Device struct {
-	MAC       string
-	Name      string
-	RSSI      float64
-	Type      DeviceType
-	LastSeen  time.Time
-	Angle     float64 // Radians, 0=north, clockwise
-	Distance  float64 // Estimated distance in meters
-	Elevation float64 // [-1, +1], 0=same level, +1=above, -1=below
-	Frequency int     // MHz (e.g. 2437, 5180). Zero for BLE/Classic.
-	Channel   int     // WiFi channel number. Zero for BLE/Classic.
+	MAC           string // Store key: the first address heard, kept across rotations
+	Name          string
+	RSSI          float64 // Smoothed RSSI (dBm)
+	RawRSSI       float64 // Most recent unfiltered sample (dBm)
+	Type          DeviceType
+	FirstSeen     time.Time
+	LastSeen      time.Time
+	Angle         float64          // Radians, 0=north, clockwise
+	Distance      float64          // Estimated distance in meters
+	Elevation     float64          // [-1, +1], 0=same level, +1=above, -1=below
+	Frequency     int              // MHz (e.g. 2437, 5180). Zero for BLE/Classic.
+	Channel       int              // WiFi channel number. Zero for BLE/Classic.
+	Beacon        *Beacon          // Decoded iBeacon/Eddystone data, nil if none
+	Tracker       *Tracker         // Decoded Find My/Tile/SmartTag data, nil if none
+	Continuity    *Continuity      // Decoded Apple Continuity messages, nil if none
+	Microsoft     *MicrosoftBeacon // Decoded CDP or Swift Pair beacon, nil if none
+	FastPair      *FastPair        // Decoded Google Fast Pair advertisement, nil if none
+	Sensor        *Sensor          // Latest environmental sensor readings, nil if none
+	Platform      string           // Apple, Windows, Android, Xbox or Linux; empty if unknown
+	Advertisement *Advertisement   // Every AD field heard, accumulated; nil for Classic/WiFi
+	ClassOfDevice DeviceClass      // Class of Device of a Classic device, zero if unknown
+
+	TxPower        int8 // Advertised TX Power Level (dBm), valid if HasTxPower
+	HasTxPower     bool
+	RefPower       float64     // RSSI at 1 m used for the distance estimate
+	RefPowerSource PowerSource // Where RefPower came from
+	PathLossExp    float64     // Path loss exponent used for Distance
+
+	Address     string        // Address last heard; differs from MAC once the device rotated
+	Addresses   []string      // Every address linked to this device, oldest first
+	AddressType AddressType   // Type of the address last heard
+	Fingerprint string        // Advertisement shape, see AdvShape
+	AdvInterval time.Duration // Estimated time between advertisements, zero if unknown
+	Identity    string        // Known device whose IRK resolved the address, see IdentityResolver
+	Vendor      string        // Organization owning the address's OUI; empty for random addresses
+	linkChecked bool          // The store tried to link this address to a rotated device
+
+	Adapters    map[string]AdapterReading // Per-adapter readings; nil for adapter-less sources
+	BestAdapter string                    // Adapter whose reading RSSI reflects
+
+	Located    bool    // Angle and Distance come from a Localizer, not the MAC hash
+	Position   Point   // Estimated position, valid if Located
+	Confidence float64 // Localization confidence [0, 1]
+	Pinned     bool    // Angle was set by hand (fox hunt) and overrides the rest
+}
+
+// AdapterReading is the signal of a device as heard by one adapter.
+type AdapterReading struct {
+	RSSI     float64 // Smoothed RSSI (dBm)
+	RawRSSI  float64 // Most recent unfiltered sample (dBm)
+	LastSeen time.Time
+}
+
+// PathLoss holds the parameters of the log-distance path loss model.
+type PathLoss struct {
+	MeasuredPower float64 `json:"measured_power"` // RSSI at 1 m (dBm)
+	Exponent      float64 `json:"path_loss_exp"`  // Path loss exponent (N)
+}
+
+// PowerSource identifies which 1 m reference was used for distance.
+type PowerSource int
+
+const (
+	PowerDefault    PowerSource = iota // Configured measured power
+	PowerAdvertised                    // TX Power Level AD field
+	PowerBeacon                        // iBeacon/Eddystone calibrated power
+	PowerCalibrated                    // Saved calibration profile
+)
+
+func (p PowerSource) String() string {
+	switch p {
+	case PowerAdvertised:
+		return "advertised"
+	case PowerBeacon:
+		return "beacon"
+	case PowerCalibrated:
+		return "calibrated"
+	default:
+		return "default"
+	}
+}
+
+// txPowerToOneMeter is the free-space loss between the transmitter and 1 m,
+// used to turn an advertised TX power into a 1 m RSSI reference.
+const txPowerToOneMeter = 41
+
+// ReferencePower returns the RSSI at 1 m to use for this device: beacon
+// calibration first, then the advertised TX power, then defaultPower.
+func (d *Device) ReferencePower(defaultPower float64) (float64, PowerSource) {
+	if d.Beacon != nil && d.Beacon.HasMeasuredPower {
+		return float64(d.Beacon.MeasuredPower), PowerBeacon
+	}
+	if d.HasTxPower {
+		return float64(d.TxPower) - txPowerToOneMeter, PowerAdvertised
+	}
+	return defaultPower, PowerDefault
 }
 
 // Symbol returns the radar character for this device type.
@@ -64,14 +166,23 @@ func (d *Device) Band() string {
 	return ""
 }
 
-// DisplayName returns the device name or "[unnamed]" if empty.
-func (d *Device) DisplayName() string {
-	if d.Name == "" {
-		return "[unnamed]"
+// KnownName returns the resolved identity, else the advertised name. It is
+// empty for anonymous devices.
+func (d *Device) KnownName() string {
+	if d.Identity != "" {
+		return d.Identity
 	}
 	return d.Name
 }
 
+// DisplayName returns KnownName or "[unnamed]" if empty.
+func (d *Device) DisplayName() string {
+	if name := d.KnownName(); name != "" {
+		return name
+	}
+	return "[unnamed]"
+}
+
 // MacToAngle derives a consistent angle from a MAC address using a hash.
 // Returns radians in [0, 2π), where 0=north, increasing clockwise.
 func MacToAngle(mac string) float64 {
@@ -101,3 +212,41 @@ func RSSIToDistance(rssi, measuredPower, pathLossExp float64) float64 {
 	}
 	return d
 }
+
+// clone returns a deep copy of the device, safe to hand out of the store.
+func (d *Device) clone() *Device {
+	cp := *d
+	if d.Beacon != nil {
+		b := *d.Beacon
+		cp.Beacon = &b
+	}
+	if d.Tracker != nil {
+		t := *d.Tracker
+		cp.Tracker = &t
+	}
+	if d.Continuity != nil {
+		cp.Continuity = d.Continuity.clone()
+	}
+	if d.Microsoft != nil {
+		m := *d.Microsoft
+		cp.Microsoft = &m
+	}
+	if d.FastPair != nil {
+		f := *d.FastPair
+		cp.FastPair = &f
+	}
+	if d.Sensor != nil {
+		cp.Sensor = d.Sensor.clone()
+	}
+	if d.Advertisement != nil {
+		cp.Advertisement = d.Advertisement.clone()
+	}
+	cp.Addresses = append([]string(nil), d.Addresses...)
+	if d.Adapters != nil {
+		cp.Adapters = make(map[string]AdapterReading, len(d.Adapters))
+		for id, r := range d.Adapters {
+			cp.Adapters[id] = r
+		}
+	}
+	return &cp
+}
@@ -0,0 +1,99 @@
+package bluetooth
+
+import "fmt"
+
+// Service UUID and field types of Google Fast Pair advertisements.
+const (
+	serviceFastPair         = 0xFE2C
+	fastPairModelIDLength   = 3
+	fastPairFieldKeysShowUI = 0x0 // Account key filter, show pairing UI
+	fastPairFieldKeysHideUI = 0x2 // Account key filter, pair silently
+)
+
+// fastPairModels maps well-known Fast Pair model IDs to product names.
+// Model IDs are registered with Google per product.
+var fastPairModels = map[uint32]string{
+	0x000006: "Google Pixel Buds",
+	0x000047: "Arduino 101",
+	0x0000F0: "Bose QuietComfort 35 II",
+	0x0001F0: "Bisto CSR8670 Dev Board",
+	0x0002F0: "JBL Everest 110GA",
+	0x0003F0: "LG HBS-835S",
+	0x001000: "LG HBS-1110",
+	0x002000: "AIAIAI TMA-2",
+	0x003000: "Libratone Q Adapt On-Ear",
+	0x00AA48: "Jabra Elite 2",
+	0x00AA91: "Beoplay E8 2.0",
+	0x00C95C: "Sony WF-1000X",
+	0x01EEB4: "Sony WH-1000XM4",
+	0x02C95C: "Sony WH-1000XM2",
+	0x038CC7: "JBL Tune 760NC",
+	0x0E30C3: "Razer Hammerhead TWS",
+	0x2D7A23: "Sony WF-1000XM4",
+	0x718FA4: "JBL Live 300TWS",
+	0x821F66: "JBL Flip 6",
+	0x92BBBD: "Pixel Buds",
+	0xCD8256: "Bose NC 700",
+	0xD446A7: "Sony WH-1000XM5",
+	0xF52494: "JBL Buds Pro",
+}
+
+// FastPair holds a decoded Google Fast Pair advertisement. A discoverable
+// accessory advertises its model ID; once paired it advertises a filter of
+// the account keys it holds instead, so phones signed in to one of those
+// accounts recognize it.
+type FastPair struct {
+	ModelID          string `json:"model_id,omitempty"` // 24-bit hex, only while discoverable
+	Model            string `json:"model,omitempty"`    // Product name of ModelID, if known
+	AccountKeyFilter bool   `json:"account_key_filter,omitempty"`
+	ShowUI           bool   `json:"show_ui,omitempty"` // Phones should offer to pair
+}
+
+// Label returns a short identifier suitable as a fallback device name.
+func (f *FastPair) Label() string {
+	switch {
+	case f.Model != "":
+		return f.Model
+	case f.ModelID != "":
+		return "Fast Pair " + f.ModelID
+	default:
+		return "Fast Pair device"
+	}
+}
+
+// merge returns a copy of other that keeps the model of f, so a paired
+// accessory stays identified after it stops advertising its model ID.
+// f may be nil.
+func (f *FastPair) merge(other *FastPair) *FastPair {
+	cp := *other
+	if cp.ModelID == "" && f != nil {
+		cp.ModelID, cp.Model = f.ModelID, f.Model
+	}
+	return &cp
+}
+
+// ParseFastPair decodes the service data of the Fast Pair service.
+func ParseFastPair(data []byte) *FastPair {
+	if len(data) == fastPairModelIDLength {
+		id := uint32(data[0])<<16 | uint32(data[1])<<8 | uint32(data[2])
+		return &FastPair{ModelID: fmt.Sprintf("%06X", id), Model: fastPairModels[id], ShowUI: true}
+	}
+	// flags(1) then fields of length|type(1) value(length)
+	f := &FastPair{}
+	if len(data) == 0 {
+		return f
+	}
+	for rest := data[1:]; len(rest) >= 1; {
+		n, typ := int(rest[0]>>4), rest[0]&0x0F
+		if 1+n > len(rest) {
+			break
+		}
+		// Salt and battery fields are skipped.
+		if typ == fastPairFieldKeysShowUI || typ == fastPairFieldKeysHideUI {
+			f.AccountKeyFilter = n > 0
+			f.ShowUI = typ == fastPairFieldKeysShowUI
+		}
+		rest = rest[1+n:]
+	}
+	return f
+}
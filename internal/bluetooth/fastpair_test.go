@@ -0,0 +1,85 @@
+package bluetooth
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFastPair(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		want      *FastPair
+		wantLabel string
+	}{
+		{
+			name:      "known model",
+			data:      "D446A7",
+			want:      &FastPair{ModelID: "D446A7", Model: "Sony WH-1000XM5", ShowUI: true},
+			wantLabel: "Sony WH-1000XM5",
+		},
+		{
+			name:      "unknown model",
+			data:      "123456",
+			want:      &FastPair{ModelID: "123456", ShowUI: true},
+			wantLabel: "Fast Pair 123456",
+		},
+		{
+			// flags, account key filter (6 bytes, show UI), salt (1 byte)
+			name:      "account key filter",
+			data:      "00 60 0A1B2C3D4E5F 11 C7",
+			want:      &FastPair{AccountKeyFilter: true, ShowUI: true},
+			wantLabel: "Fast Pair device",
+		},
+		{
+			name:      "account key filter hide ui",
+			data:      "00 62 0A1B2C3D4E5F 11 C7 33 5A6478",
+			want:      &FastPair{AccountKeyFilter: true},
+			wantLabel: "Fast Pair device",
+		},
+		{
+			// Battery notification after the filter is skipped.
+			name:      "empty filter",
+			data:      "00 00 11 C7 33 5A6478",
+			want:      &FastPair{ShowUI: true},
+			wantLabel: "Fast Pair device",
+		},
+		{
+			name:      "truncated filter",
+			data:      "00 60 0A1B",
+			want:      &FastPair{},
+			wantLabel: "Fast Pair device",
+		},
+		{
+			name:      "empty",
+			data:      "",
+			want:      &FastPair{},
+			wantLabel: "Fast Pair device",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseFastPair(unhex(t, tt.data))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFastPair = %+v, want %+v", got, tt.want)
+			}
+			if l := got.Label(); l != tt.wantLabel {
+				t.Errorf("Label = %q, want %q", l, tt.wantLabel)
+			}
+		})
+	}
+}
+
+func TestFastPairMerge(t *testing.T) {
+	discoverable := ParseFastPair(unhex(t, "D446A7"))
+	paired := discoverable.merge(ParseFastPair(unhex(t, "00 62 0A1B2C3D4E5F")))
+	want := &FastPair{ModelID: "D446A7", Model: "Sony WH-1000XM5", AccountKeyFilter: true}
+	if !reflect.DeepEqual(paired, want) {
+		t.Errorf("merge = %+v, want %+v", paired, want)
+	}
+
+	var none *FastPair
+	if got := none.merge(discoverable); !reflect.DeepEqual(got, discoverable) || got == discoverable {
+		t.Errorf("nil merge = %+v, want a copy of %+v", got, discoverable)
+	}
+}
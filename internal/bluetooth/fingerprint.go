@@ -0,0 +1,58 @@
+package bluetooth
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+)
+
+// AdvShape is the part of an advertisement that stays the same when a
+// device rotates its address: which fields it sends and how long they are,
+// not their changing contents.
+type AdvShape struct {
+	Name         string
+	Manufacturer []ManufacturerShape
+	Services     []string // Advertised service UUIDs
+	ServiceData  []string // UUIDs that carry service data
+	TxPower      int8
+	HasTxPower   bool
+}
+
+// ManufacturerShape describes one manufacturer data field. Type is the first
+// payload byte, which most vendors use as a message type (e.g. Apple
+// Continuity).
+type ManufacturerShape struct {
+	CompanyID uint16
+	Type      byte
+	Length    int
+}
+
+// Fingerprint returns a short hash of the shape, or "" if the shape has
+// nothing to tell devices apart by.
+func (s AdvShape) Fingerprint() string {
+	var parts []string
+	if s.Name != "" {
+		parts = append(parts, "n="+s.Name)
+	}
+	for _, m := range s.Manufacturer {
+		parts = append(parts, fmt.Sprintf("m=%04x/%02x/%d", m.CompanyID, m.Type, m.Length))
+	}
+	for _, u := range s.Services {
+		parts = append(parts, "s="+strings.ToLower(u))
+	}
+	for _, u := range s.ServiceData {
+		parts = append(parts, "d="+strings.ToLower(u))
+	}
+	if s.HasTxPower {
+		parts = append(parts, fmt.Sprintf("t=%d", s.TxPower))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	sort.Strings(parts)
+
+	h := fnv.New64a()
+	h.Write([]byte(strings.Join(parts, ";")))
+	return fmt.Sprintf("%016x", h.Sum64())
+}
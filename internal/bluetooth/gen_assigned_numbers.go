@@ -0,0 +1,188 @@
+//go:build ignore
+
+// gen_assigned_numbers builds assigned_numbers.go from the Bluetooth SIG
+// assigned numbers YAML vendored under assigned_numbers/. Run it with
+// go generate after updating those files, or with -fetch to download the
+// current upstream files first:
+//
+//	go run gen_assigned_numbers.go -fetch
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	dir      = "assigned_numbers"
+	upstream = "https://bitbucket.org/bluetooth-SIG/public/raw/main/assigned_numbers/"
+)
+
+var (
+	output = flag.String("o", "assigned_numbers.go", "Go file to write")
+	fetch  = flag.Bool("fetch", false, "download the YAML files from "+upstream+" into "+dir+"/ first")
+)
+
+// sources are the vendored files, relative to dir and upstream.
+var sources = []string{
+	"company_identifiers/company_identifiers.yaml",
+	"uuids/service_uuids.yaml",
+	"uuids/member_uuids.yaml",
+	"core/appearance_values.yaml",
+	"core/ad_types.yaml",
+}
+
+type entry struct {
+	Value uint16 `yaml:"value"`
+	UUID  uint16 `yaml:"uuid"`
+	Name  string `yaml:"name"`
+}
+
+type appearance struct {
+	Category    uint16  `yaml:"category"`
+	Name        string  `yaml:"name"`
+	Subcategory []entry `yaml:"subcategory"`
+}
+
+// legalSuffixes and trailing parentheses are stripped from company names
+// so that they fit the device list, e.g. "Apple, Inc." becomes "Apple".
+var legalSuffixes = []string{
+	" Co., Ltd.", " Co.,Ltd.", " Co.,Ltd", " Co. Ltd.", " Co,. LTD.",
+	" Inc.", " Inc", " Ltd.", " Ltd", " Limited", " LIMITED", " LLC",
+	" Corporation", " Corp.", " Corp", " GmbH & Co. KG", " GmbH",
+	" AG", " AB", " ASA", " A/S", " B.V.", " BV", " S.A.S", " SAS",
+	" S.A.", " SA", " S.P.A.", " SE", " Oy.", " Oy", " OY", " Pty. Ltd.", " srl",
+}
+
+func shortName(name string) string {
+	for {
+		trimmed := strings.TrimRight(name, ", ")
+		if i := strings.LastIndex(trimmed, " ("); i > 0 && strings.HasSuffix(trimmed, ")") {
+			trimmed = trimmed[:i]
+		}
+		for _, s := range legalSuffixes {
+			trimmed = strings.TrimSuffix(trimmed, s)
+		}
+		trimmed = strings.TrimRight(trimmed, ", ")
+		if trimmed == name || trimmed == "" {
+			return name
+		}
+		name = trimmed
+	}
+}
+
+// download replaces the vendored copy of path with the upstream file.
+func download(path string) {
+	resp, err := http.Get(upstream + path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		log.Fatalf("%s: %s", path, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		log.Fatalf("%s: %v", path, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, path), data, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func load(path, key string, v any) {
+	data, err := os.ReadFile(filepath.Join(dir, path))
+	if err != nil {
+		log.Fatal(err)
+	}
+	var doc map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		log.Fatalf("%s: %v", path, err)
+	}
+	node, ok := doc[key]
+	if !ok {
+		log.Fatalf("%s: no %q list", path, key)
+	}
+	if err := node.Decode(v); err != nil {
+		log.Fatalf("%s: %v", path, err)
+	}
+}
+
+func writeMap(b *bytes.Buffer, comment, name, keyType string, m map[uint16]string, width int) {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, int(k))
+	}
+	sort.Ints(keys)
+	fmt.Fprintf(b, "// %s\nvar %s = map[%s]string{\n", comment, name, keyType)
+	for _, k := range keys {
+		fmt.Fprintf(b, "\t0x%0*X: %q,\n", width, k, m[uint16(k)])
+	}
+	b.WriteString("}\n\n")
+}
+
+func main() {
+	flag.Parse()
+	if *fetch {
+		for _, path := range sources {
+			download(path)
+		}
+	}
+
+	var companies, services, members, adTypes []entry
+	var appearances []appearance
+	load(sources[0], "company_identifiers", &companies)
+	load(sources[1], "uuids", &services)
+	load(sources[2], "uuids", &members)
+	load(sources[3], "appearance_values", &appearances)
+	load(sources[4], "ad_types", &adTypes)
+
+	companyNames := make(map[uint16]string, len(companies))
+	for _, c := range companies {
+		companyNames[c.Value] = shortName(c.Name)
+	}
+	serviceNames := make(map[uint16]string, len(services)+len(members))
+	for _, m := range members {
+		serviceNames[m.UUID] = shortName(m.Name)
+	}
+	for _, s := range services {
+		serviceNames[s.UUID] = s.Name
+	}
+	appearanceNames := make(map[uint16]string)
+	for _, a := range appearances {
+		appearanceNames[a.Category<<6] = a.Name
+		for _, s := range a.Subcategory {
+			appearanceNames[a.Category<<6|s.Value] = s.Name
+		}
+	}
+	adTypeNames := make(map[uint16]string, len(adTypes))
+	for _, t := range adTypes {
+		adTypeNames[t.Value] = t.Name
+	}
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by gen_assigned_numbers.go from assigned_numbers/; DO NOT EDIT.\n\npackage bluetooth\n\n")
+	writeMap(&b, "companyNames maps SIG company identifiers to company names.", "companyNames", "uint16", companyNames, 4)
+	writeMap(&b, "serviceNames maps 16-bit GATT service and member UUIDs to names.", "serviceNames", "uint16", serviceNames, 4)
+	writeMap(&b, "appearanceNames maps Appearance values (category<<6 | subcategory) to names.", "appearanceNames", "uint16", appearanceNames, 4)
+	writeMap(&b, "adTypeNames maps GAP AD types to names.", "adTypeNames", "byte", adTypeNames, 2)
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,76 @@
+package bluetooth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/hex"
+	"strings"
+)
+
+// IdentityKey is the Identity Resolving Key of a known device.
+type IdentityKey struct {
+	Name string
+	IRK  [16]byte // Most significant byte first
+}
+
+// IdentityResolver matches resolvable private addresses to known devices.
+type IdentityResolver struct {
+	names   []string
+	ciphers []cipher.Block // Two per key: as given and byte-reversed
+}
+
+// NewIdentityResolver prepares keys for resolution. Keys are tried in both
+// byte orders, since some pairing databases store them least significant
+// byte first.
+func NewIdentityResolver(keys []IdentityKey) (*IdentityResolver, error) {
+	r := &IdentityResolver{}
+	for _, k := range keys {
+		rev := k.IRK
+		for i, j := 0, len(rev)-1; i < j; i, j = i+1, j-1 {
+			rev[i], rev[j] = rev[j], rev[i]
+		}
+		for _, key := range [][16]byte{k.IRK, rev} {
+			c, err := aes.NewCipher(key[:])
+			if err != nil {
+				return nil, err
+			}
+			r.names = append(r.names, k.Name)
+			r.ciphers = append(r.ciphers, c)
+		}
+	}
+	return r, nil
+}
+
+// Resolve returns the name of the device whose key generated mac. Only
+// resolvable private addresses can match.
+func (r *IdentityResolver) Resolve(mac string) (string, bool) {
+	if r == nil || ClassifyAddress(mac, true) != AddressResolvable {
+		return "", false
+	}
+	addr, err := hex.DecodeString(strings.ReplaceAll(mac, ":", ""))
+	if err != nil || len(addr) != 6 {
+		return "", false
+	}
+	// The address is prand (most significant half) followed by hash.
+	var prand, hash [3]byte
+	copy(prand[:], addr[:3])
+	copy(hash[:], addr[3:])
+	for i, c := range r.ciphers {
+		if ah(c, prand) == hash {
+			return r.names[i], true
+		}
+	}
+	return "", false
+}
+
+// ah is the random address hash function of the Core specification
+// (Vol 3, Part H, 2.2.2): AES-128 of prand padded with zeros to 16 bytes,
+// truncated to its least significant 24 bits.
+func ah(c cipher.Block, prand [3]byte) [3]byte {
+	var in, out [16]byte
+	copy(in[13:], prand[:])
+	c.Encrypt(out[:], in[:])
+	var h [3]byte
+	copy(h[:], out[13:])
+	return h
+}
@@ -0,0 +1,70 @@
+package bluetooth
+
+import (
+	"crypto/aes"
+	"testing"
+)
+
+// Sample data of the Core specification, Vol 3, Part H, Appendix D.7
+var (
+	specIRK   = [16]byte{0xEC, 0x02, 0x34, 0xA3, 0x57, 0xC8, 0xAD, 0x05, 0x34, 0x10, 0x10, 0xA6, 0x0A, 0x39, 0x7D, 0x9B}
+	specPrand = [3]byte{0x70, 0x81, 0x94}
+	specHash  = [3]byte{0x0D, 0xFB, 0xAA}
+)
+
+func TestAh(t *testing.T) {
+	c, err := aes.NewCipher(specIRK[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ah(c, specPrand); got != specHash {
+		t.Errorf("ah = %X, want %X", got, specHash)
+	}
+}
+
+func TestIdentityResolver(t *testing.T) {
+	rev := specIRK
+	for i, j := 0, len(rev)-1; i < j; i, j = i+1, j-1 {
+		rev[i], rev[j] = rev[j], rev[i]
+	}
+	r, err := NewIdentityResolver([]IdentityKey{
+		{Name: "other", IRK: [16]byte{1, 2, 3}},
+		{Name: "phone", IRK: specIRK},
+		{Name: "reversed", IRK: rev},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		mac  string
+		want string
+	}{
+		{"70:81:94:0D:FB:AA", "phone"},
+		{"70:81:94:0d:fb:aa", "phone"},
+		{"70:81:94:0D:FB:AB", ""}, // Wrong hash
+		{"30:81:94:0D:FB:AA", ""}, // Non-resolvable private address
+		{"F0:81:94:0D:FB:AA", ""}, // Static random address
+		{"70:81:94", ""},
+	}
+	for _, tt := range tests {
+		got, ok := r.Resolve(tt.mac)
+		if got != tt.want || ok != (tt.want != "") {
+			t.Errorf("Resolve(%q) = %q, %v; want %q", tt.mac, got, ok, tt.want)
+		}
+	}
+
+	// A key stored least significant byte first resolves in either order.
+	r, err = NewIdentityResolver([]IdentityKey{{Name: "reversed", IRK: rev}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := r.Resolve("70:81:94:0D:FB:AA"); !ok || got != "reversed" {
+		t.Errorf("reversed key: Resolve = %q, %v; want reversed", got, ok)
+	}
+
+	var none *IdentityResolver
+	if _, ok := none.Resolve("70:81:94:0D:FB:AA"); ok {
+		t.Error("nil resolver resolved an address")
+	}
+}
@@ -0,0 +1,20 @@
+package bluetooth
+
+// Point is a position on the floor plane in meters: X east, Y north,
+// relative to the radar center.
+type Point struct {
+	X, Y float64
+}
+
+// Location is an estimated device position.
+type Location struct {
+	Position   Point
+	Angle      float64 // Bearing from the radar center, radians clockwise from north
+	Distance   float64 // Meters from the radar center
+	Confidence float64 // [0, 1]
+}
+
+// Localizer estimates where a device is from its per-adapter readings.
+type Localizer interface {
+	Locate(d *Device) (Location, bool)
+}
@@ -0,0 +1,93 @@
+package bluetooth
+
+import "encoding/binary"
+
+// Service UUID and frame control bits of Xiaomi MiBeacon.
+const (
+	serviceMiBeacon         = 0xFE95
+	miBeaconEncrypted       = 0x0008
+	miBeaconHasMAC          = 0x0010
+	miBeaconHasCapability   = 0x0020
+	miBeaconHasObject       = 0x0040
+	miBeaconIOCapability    = 0x20 // Capability bit: two I/O capability bytes follow
+	miBeaconHeaderLength    = 5    // frame control(2) product id(2) frame counter(1)
+	miBeaconObjectHeaderLen = 3    // object id(2) length(1)
+)
+
+// MiBeacon object IDs of the readings decoded.
+const (
+	miObjectMotionLight = 0x000F // Motion detected, with illuminance
+	miObjectTemperature = 0x1004
+	miObjectHumidity    = 0x1006
+	miObjectIlluminance = 0x1007
+	miObjectBattery     = 0x100A
+	miObjectTempHumid   = 0x100D
+	miObjectNoMotion    = 0x1017 // Seconds since motion was last detected
+)
+
+// ParseMiBeacon decodes the objects of an unencrypted MiBeacon frame.
+// Encrypted frames need the device's bind key and return nil, as do
+// frames without readings.
+func ParseMiBeacon(data []byte) *Sensor {
+	if len(data) < miBeaconHeaderLength {
+		return nil
+	}
+	fc := binary.LittleEndian.Uint16(data[0:2])
+	if fc&miBeaconEncrypted != 0 || fc&miBeaconHasObject == 0 {
+		return nil
+	}
+	rest := data[miBeaconHeaderLength:]
+	if fc&miBeaconHasMAC != 0 {
+		if len(rest) < 6 {
+			return nil
+		}
+		rest = rest[6:]
+	}
+	if fc&miBeaconHasCapability != 0 {
+		if len(rest) < 1 {
+			return nil
+		}
+		capability := rest[0]
+		rest = rest[1:]
+		if capability&miBeaconIOCapability != 0 {
+			if len(rest) < 2 {
+				return nil
+			}
+			rest = rest[2:]
+		}
+	}
+
+	s := &Sensor{Format: SensorMiBeacon}
+	for len(rest) >= miBeaconObjectHeaderLen {
+		id := binary.LittleEndian.Uint16(rest[0:2])
+		n := int(rest[2])
+		rest = rest[miBeaconObjectHeaderLen:]
+		if n > len(rest) {
+			break
+		}
+		v := rest[:n]
+		rest = rest[n:]
+		switch {
+		case id == miObjectTemperature && n == 2:
+			s.set(QuantityTemperature, littleEndian(v, true)/10)
+		case id == miObjectHumidity && n == 2:
+			s.set(QuantityHumidity, littleEndian(v, false)/10)
+		case id == miObjectTempHumid && n == 4:
+			s.set(QuantityTemperature, littleEndian(v[0:2], true)/10)
+			s.set(QuantityHumidity, littleEndian(v[2:4], false)/10)
+		case id == miObjectBattery && n == 1:
+			s.set(QuantityBattery, float64(v[0]))
+		case id == miObjectIlluminance && n == 3:
+			s.set(QuantityIlluminance, littleEndian(v, false))
+		case id == miObjectMotionLight && n == 3:
+			s.set(QuantityMotion, 1)
+			s.set(QuantityIlluminance, littleEndian(v, false))
+		case id == miObjectNoMotion && n == 4:
+			s.set(QuantityMotion, 0)
+		}
+	}
+	if len(s.Readings) == 0 {
+		return nil
+	}
+	return s
+}
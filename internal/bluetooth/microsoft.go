@@ -0,0 +1,122 @@
+package bluetooth
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Company ID and beacon IDs of Microsoft manufacturer data.
+const (
+	companyMicrosoft       = 0x0006
+	microsoftCDP           = 0x01 // Connected Devices Platform beacon (MS-CDP)
+	microsoftSwiftPair     = 0x03
+	cdpDeviceTypeMask      = 0x3F
+	swiftPairLEOnly        = 0x00 // Display name follows the reserved RSSI byte
+	swiftPairBREDR         = 0x01 // BR/EDR address and icon precede the name
+	swiftPairBREDRNoAddr   = 0x02 // Icon precedes the name
+	swiftPairHeaderLength  = 3    // Beacon ID, sub scenario, reserved RSSI
+	swiftPairAddressLength = 6
+	swiftPairIconLength    = 3
+)
+
+// cdpDeviceTypes names the device types of MS-CDP beacons together with
+// the platform they run.
+var cdpDeviceTypes = map[byte]struct{ name, platform string }{
+	1:  {"Xbox One", "Xbox"},
+	6:  {"iPhone", "Apple"},
+	7:  {"iPad", "Apple"},
+	8:  {"Android device", "Android"},
+	9:  {"Windows desktop", "Windows"},
+	11: {"Windows phone", "Windows"},
+	12: {"Linux device", "Linux"},
+	13: {"Windows IoT", "Windows"},
+	14: {"Surface Hub", "Windows"},
+	15: {"Windows laptop", "Windows"},
+	16: {"Windows tablet", "Windows"},
+}
+
+// MicrosoftBeacon holds a decoded Microsoft beacon: either a Connected
+// Devices Platform beacon, sent by Windows PCs and by phones running
+// Microsoft apps, or a Swift Pair advertisement from an accessory offering
+// to pair with Windows.
+type MicrosoftBeacon struct {
+	SwiftPair     bool        `json:"swift_pair,omitempty"`
+	DeviceType    string      `json:"device_type,omitempty"`     // CDP device type, e.g. "Windows laptop"
+	Name          string      `json:"name,omitempty"`            // Swift Pair display name
+	ClassOfDevice DeviceClass `json:"class_of_device,omitempty"` // Swift Pair icon, zero if not sent
+}
+
+// Label returns a short identifier suitable as a fallback device name.
+func (m *MicrosoftBeacon) Label() string {
+	switch {
+	case m.Name != "":
+		return m.Name
+	case m.DeviceType != "":
+		return m.DeviceType
+	case m.SwiftPair:
+		return "Swift Pair device"
+	default:
+		return "Microsoft device"
+	}
+}
+
+// ParseMicrosoft decodes Microsoft manufacturer data carrying a CDP or
+// Swift Pair beacon. Returns nil if the data is neither.
+func ParseMicrosoft(companyID uint16, data []byte) *MicrosoftBeacon {
+	if companyID != companyMicrosoft || len(data) < swiftPairHeaderLength {
+		return nil
+	}
+	switch data[0] {
+	case microsoftCDP:
+		// scenario(1) version|device type(1) version|flags(1) reserved(1)
+		// salt(4) device hash(16)
+		typ := data[1] & cdpDeviceTypeMask
+		m := &MicrosoftBeacon{DeviceType: cdpDeviceTypes[typ].name}
+		if m.DeviceType == "" {
+			m.DeviceType = fmt.Sprintf("CDP device type %d", typ)
+		}
+		return m
+
+	case microsoftSwiftPair:
+		// beacon(1) sub scenario(1) reserved RSSI(1) [address(6)] [icon(3)] name
+		m := &MicrosoftBeacon{SwiftPair: true}
+		rest := data[swiftPairHeaderLength:]
+		switch data[1] {
+		case swiftPairBREDR:
+			if len(rest) < swiftPairAddressLength {
+				return m
+			}
+			rest = rest[swiftPairAddressLength:]
+			fallthrough
+		case swiftPairBREDRNoAddr:
+			if len(rest) < swiftPairIconLength {
+				return m
+			}
+			m.ClassOfDevice = DeviceClass(rest[0]) | DeviceClass(rest[1])<<8 | DeviceClass(rest[2])<<16
+			rest = rest[swiftPairIconLength:]
+		case swiftPairLEOnly:
+		default:
+			return m
+		}
+		if utf8.Valid(rest) {
+			m.Name = strings.TrimRight(string(rest), "\x00")
+		}
+		return m
+	}
+	return nil
+}
+
+// Platform returns the platform of a CDP beacon's device, or Windows for
+// a Swift Pair accessory.
+func (m *MicrosoftBeacon) Platform() string {
+	if m.SwiftPair {
+		return "Windows"
+	}
+	for _, t := range cdpDeviceTypes {
+		if t.name == m.DeviceType {
+			return t.platform
+		}
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+package bluetooth
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMicrosoft(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         string // Microsoft manufacturer data after the company ID
+		want         *MicrosoftBeacon
+		wantPlatform string
+	}{
+		{
+			name:         "cdp windows desktop",
+			data:         "01 09 20 02 A1B2C3D4 00112233445566778899AABBCCDDEEFF",
+			want:         &MicrosoftBeacon{DeviceType: "Windows desktop"},
+			wantPlatform: "Windows",
+		},
+		{
+			// Version bits above the device type are masked off.
+			name:         "cdp android",
+			data:         "01 48 20 02 A1B2C3D4 00112233445566778899AABBCCDDEEFF",
+			want:         &MicrosoftBeacon{DeviceType: "Android device"},
+			wantPlatform: "Android",
+		},
+		{
+			name: "cdp unknown type",
+			data: "01 3F 20 02",
+			want: &MicrosoftBeacon{DeviceType: "CDP device type 63"},
+		},
+		{
+			// Examples of the Swift Pair documentation
+			name:         "swift pair le",
+			data:         "03 00 80 4E616D65",
+			want:         &MicrosoftBeacon{SwiftPair: true, Name: "Name"},
+			wantPlatform: "Windows",
+		},
+		{
+			name:         "swift pair br/edr",
+			data:         "03 01 80 AABBCCDDEEFF 040424 486561647365740000",
+			want:         &MicrosoftBeacon{SwiftPair: true, Name: "Headset", ClassOfDevice: 0x240404},
+			wantPlatform: "Windows",
+		},
+		{
+			name:         "swift pair br/edr without address",
+			data:         "03 02 80 040424 486561647365",
+			want:         &MicrosoftBeacon{SwiftPair: true, Name: "Headse", ClassOfDevice: 0x240404},
+			wantPlatform: "Windows",
+		},
+		{
+			name:         "swift pair truncated icon",
+			data:         "03 02 80 0404",
+			want:         &MicrosoftBeacon{SwiftPair: true},
+			wantPlatform: "Windows",
+		},
+		{
+			name:         "swift pair invalid name",
+			data:         "03 00 80 FFFE",
+			want:         &MicrosoftBeacon{SwiftPair: true},
+			wantPlatform: "Windows",
+		},
+		{name: "other beacon", data: "02 00 80 00"},
+		{name: "short", data: "03 00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseMicrosoft(companyMicrosoft, unhex(t, tt.data))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ParseMicrosoft = %+v, want %+v", got, tt.want)
+			}
+			if got != nil {
+				if p := got.Platform(); p != tt.wantPlatform {
+					t.Errorf("Platform = %q, want %q", p, tt.wantPlatform)
+				}
+			}
+		})
+	}
+
+	if m := ParseMicrosoft(companyApple, unhex(t, "03 00 80 4E616D65")); m != nil {
+		t.Error("decoded manufacturer data of another company")
+	}
+}
@@ -2,14 +2,26 @@ package bluetooth
 
 import (
 	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"encoding/hex"
 	"fmt"
 	"math"
 	"math/rand"
 	"time"
-
-	tea "github.com/charmbracelet/bubbletea"
 )
 
+func init() {
+	Register(Registration{
+		Name:        "mock",
+		Description: "Fake devices for demo mode (no hardware)",
+		New: func(opts ScannerOptions) (Scanner, error) {
+			return NewMockScanner(opts.Adapters, opts.AdapterPositions), nil
+		},
+	})
+}
+
 var mockDeviceTemplates = []struct {
 	Name string
 	Type DeviceType
@@ -34,8 +46,142 @@ var mockDeviceTemplates = []struct {
 	{"TP-Link_5GHz", DeviceTypeWiFi},
 	{"AndroidAP", DeviceTypeWiFi},
 	{"Starlink_WiFi", DeviceTypeWiFi},
+	{"", DeviceTypeBLE}, // iBeacon
+	{"", DeviceTypeBLE}, // Eddystone
+	{"", DeviceTypeBLE}, // AirTag away from its owner
+	{"", DeviceTypeBLE}, // Windows laptop (CDP)
+	{"", DeviceTypeBLE}, // Swift Pair mouse
+	{"", DeviceTypeBLE}, // Fast Pair earbuds in pairing mode
+	{"", DeviceTypeBLE}, // RuuviTag
+	{"GVH5075_3A1F", DeviceTypeBLE},
+	{"SBHT-003C", DeviceTypeBLE}, // Shelly BLU H&T, BTHome
+	{"MJ_HT_V1", DeviceTypeBLE},  // Xiaomi thermometer, MiBeacon
+}
+
+// mockBeacons maps template indexes to the beacon frame they advertise.
+var mockBeacons = map[int]*Beacon{
+	20: {
+		Format: BeaconIBeacon, UUID: "f7826da6-4fa2-4e98-8024-bc5b71e0893e",
+		Major: 100, Minor: 7, MeasuredPower: -62, HasMeasuredPower: true,
+	},
+	21: {
+		Format: BeaconEddystone, URL: "https://goo.gl/radar",
+		HasTelemetry: true, BatteryMV: 2980, Temperature: 21.5, Uptime: 36 * time.Hour,
+		MeasuredPower: -59, HasMeasuredPower: true,
+	},
+}
+
+// mockTrackers maps template indexes to the tracking tag frame they advertise.
+var mockTrackers = map[int]*Tracker{
+	10: {Kind: TrackerTile, Model: "Tile"},
+	22: {Kind: TrackerFindMy, Model: "AirTag", State: "separated", Separated: true, Battery: "full"},
+}
+
+// mockContinuity maps template indexes to the Apple manufacturer data they
+// advertise, decoded with ParseContinuity like a real scan.
+var mockContinuity = map[int][]byte{
+	// iPhone: Nearby Info (screen on, Wi-Fi on) and Handoff
+	0: {0x10, 0x05, 0x07, 0x1C, 0x3A, 0x91, 0x0E, 0x0C, 0x0E, 0x00, 0x4F, 0x12,
+		0x9D, 0x33, 0x70, 0x1B, 0xA8, 0x5E, 0xC2, 0x07, 0x64, 0xF1, 0x28},
+	// AirPods Pro: left 90%, right 80%, case 60% and charging, lid closed
+	3: {0x07, 0x19, 0x01, 0x0E, 0x20, 0x55, 0x98, 0x46, 0x09, 0x00, 0x8A, 0x1D,
+		0x64, 0x3B, 0xE0, 0x52, 0x97, 0x0C, 0xF5, 0x21, 0x7E, 0xB9, 0x46, 0x03,
+		0xD8, 0x6A, 0x11},
+	// MacBook: Handoff with copied data
+	5: {0x0C, 0x0E, 0x08, 0x21, 0x07, 0xE4, 0x5A, 0x13, 0x8F, 0xC0, 0x2D, 0x76,
+		0xB1, 0x49, 0x05, 0x9E},
+	// Apple Watch: Nearby Info (on wrist, Wi-Fi off)
+	6: {0x10, 0x05, 0x1A, 0x18, 0xC4, 0x6D, 0x02},
+	// iPad: Nearby Info (idle) and a Wi-Fi password sharing Nearby Action
+	13: {0x10, 0x05, 0x03, 0x1C, 0x77, 0x20, 0xB5, 0x0F, 0x05, 0x00, 0x08, 0x3E,
+		0x81, 0x5C},
+}
+
+// mockMicrosoft maps template indexes to the Microsoft manufacturer data
+// they advertise.
+var mockMicrosoft = map[int][]byte{
+	// Galaxy phone running Phone Link: CDP beacon, Android device
+	1: {0x01, 0x08, 0x20, 0x00, 0x5C, 0x19, 0xA2, 0x7E, 0x31, 0xD0, 0x8B, 0x46,
+		0xF2, 0x6A, 0x0D, 0x97, 0xC3, 0x58, 0xE1, 0x24, 0x7F, 0xB6, 0x03, 0x9A},
+	// CDP beacon, Windows laptop
+	23: {0x01, 0x0F, 0x20, 0x00, 0x83, 0x4E, 0x0B, 0xD7, 0x66, 0x2C, 0xF9, 0x15,
+		0xA0, 0x3D, 0xB8, 0x72, 0x4F, 0xE6, 0x91, 0x0C, 0x5B, 0x28, 0xC4, 0x7D},
+	// Swift Pair, LE only
+	24: append([]byte{0x03, 0x00, 0x80}, "Surface Precision Mouse"...),
+}
+
+// mockFastPair maps template indexes to their Fast Pair service data.
+var mockFastPair = map[int][]byte{
+	// Paired: account key filter of 6 bytes and a salt
+	14: {0x00, 0x60, 0x4B, 0x17, 0xE2, 0x09, 0x8C, 0x35, 0x11, 0xA6},
+	// Discoverable: model ID
+	25: {0x92, 0xBB, 0xBD},
+}
+
+// mockAppearance maps BLE templates to the GAP Appearance they advertise.
+var mockAppearance = map[int]uint16{
+	0: 0x0040, 1: 0x0040, 2: 0x0040, // phones
+	3: 0x0941, 14: 0x0941, 25: 0x0941, // earbuds
+	5: 0x0083, 23: 0x0083, // laptops
+	6: 0x00C2, 7: 0x00C1, // watches
+	10: 0x0200, 13: 0x0087, 24: 0x03C2,
+	26: 0x0540, 27: 0x0540, 28: 0x0540, 29: 0x0540, // sensors
+}
+
+// mockServices maps BLE templates to the 16-bit service UUIDs they list.
+var mockServices = map[int][]uint16{
+	2:  {0xFEF3},
+	7:  {0x180D, 0x180F},
+	10: {0xFEED},
+	21: {0xFEAA},
+	24: {0x1812},
+	27: {0xEC88},
+}
+
+// mockOUI maps Classic and WiFi templates to the OUI of their public
+// address, so that demo mode shows vendors. The others get random bytes.
+var mockOUI = map[int][]byte{
+	4:  {0x8C, 0x77, 0x12}, // Samsung
+	8:  {0x04, 0x5D, 0x4B}, // Sony
+	12: {0x98, 0xB6, 0xE9}, // Nintendo
+	15: {0xA0, 0x40, 0xA0}, // NETGEAR
+	17: {0x50, 0xC7, 0xBF}, // TP-Link
 }
 
+// mockClass maps Classic templates to the Class of Device they answer
+// inquiries with.
+var mockClass = map[int]DeviceClass{
+	4:  0x240404, // Audio/Video wearable headset
+	8:  0x240418, // Audio/Video headphones
+	9:  0x240414, // Audio/Video loudspeaker
+	12: 0x002508, // Peripheral gamepad
+}
+
+// mockHotspot is the template of a phone hotspot, whose BSSID is locally
+// administered as Android randomizes it.
+const mockHotspot = 18
+
+// mockRotating lists the templates that use rotating private addresses,
+// as phones, watches and earbuds do.
+var mockRotating = map[int]bool{0: true, 1: true, 2: true, 3: true, 6: true, 13: true, 14: true}
+
+// MockIRK is the Identity Resolving Key the mock Apple Watch derives its
+// private addresses from. Add it to the configured identities to see IRK
+// resolution in demo mode.
+const MockIRK = "0f2d5b7c9e41a3c6d8e0f1a2b3c4d5e6"
+
+// mockIdentity is the template whose addresses resolve with MockIRK.
+const mockIdentity = 6
+
+// mockRPARotation is how often (in simulated seconds) mock devices with
+// private addresses rotate them. Real devices rotate about every 15 minutes.
+const mockRPARotation = 45.0
+
+// mockRotation is how often (in simulated seconds) mock Find My tags change
+// address. Real tags away from their owner rotate daily; this shows the
+// tracker monitor following them.
+const mockRotation = 120.0
+
 type mockDevice struct {
 	mac       string
 	name      string
@@ -46,21 +192,48 @@ type mockDevice struct {
 	active    bool
 	freq      int
 	channel   int
+	beacon    *Beacon
+	tracker   *Tracker
+	apple     *Continuity
+	microsoft *MicrosoftBeacon
+	fastPair  *FastPair
+	sensor    func(t float64) *Sensor
+	adv       *Advertisement
+	platform  string
+	addrType  AddressType
+	oui       []byte       // Assigned prefix of a public address, nil for random bytes
+	class     DeviceClass  // Class of Device, Classic only
+	irk       cipher.Block // Generates resolvable addresses, nil for random ones
+	shape     string       // Advertisement fingerprint
+	rotated   float64      // Time of the last address rotation
+	offsets   []float64    // Per simulated adapter RSSI offset (dB)
 }
 
+// mockPathLossExp shapes the simulated per-adapter signal differences.
+const mockPathLossExp = 2.5
+
 // MockScanner generates fake devices for demo mode.
 type MockScanner struct {
-	program  *tea.Program
+	health
+	sink     Sink
 	devices  []mockDevice
-	running  bool
+	adapters []string
 	cancel   context.CancelFunc
 }
 
 // 5 GHz channel options for mock WiFi devices.
 var wifi5GChannels = []int{36, 40, 44, 48, 149, 153, 157, 161}
 
-// NewMockScanner creates a mock scanner with random fake devices.
-func NewMockScanner() *MockScanner {
+// NewMockScanner creates a mock scanner with random fake devices. With two
+// or more adapters, every BLE reading is repeated per adapter with its own
+// offset, as if several dongles were hearing the same devices. Adapters with
+// a known position get the offset a device at its MAC-hash bearing would
+// produce, so localization has something consistent to find.
+func NewMockScanner(adapters []string, positions map[string]Point) *MockScanner {
+	if len(adapters) < 2 {
+		adapters = nil
+	}
+
 	// Separate templates by type to guarantee representation
 	var bleTmpls, clsTmpls, wifiTmpls []int
 	for i, t := range mockDeviceTemplates {
@@ -110,13 +283,65 @@ func NewMockScanner() *MockScanner {
 	for i, ti := range picked {
 		tmpl := mockDeviceTemplates[ti]
 		md := mockDevice{
-			mac:       randomMAC(),
 			name:      tmpl.Name,
 			dtype:     tmpl.Type,
 			baseRSSI:  -40 - rand.Float64()*50, // -40 to -90 dBm
 			phase:     rand.Float64() * 2 * math.Pi,
 			amplitude: 3 + rand.Float64()*8, // 3-11 dBm fluctuation
 			active:    true,
+			beacon:    mockBeacons[ti],
+			tracker:   mockTrackers[ti],
+			apple:     ParseContinuity(companyApple, mockContinuity[ti]),
+			microsoft: ParseMicrosoft(companyMicrosoft, mockMicrosoft[ti]),
+		}
+		if sd, ok := mockFastPair[ti]; ok {
+			md.fastPair = ParseFastPair(sd)
+		}
+		if tmpl.Type == DeviceTypeBLE {
+			md.adv = mockAdvertisement(ti, tmpl.Name)
+		}
+		md.platform = platformOf(md.apple, md.microsoft, md.fastPair)
+		md.sensor = mockSensors[ti]
+		if md.beacon != nil {
+			md.name = md.beacon.Label()
+		}
+		if md.tracker != nil && md.name == "" {
+			md.name = md.tracker.Label()
+		}
+		if md.microsoft != nil && md.name == "" {
+			md.name = md.microsoft.Label()
+		}
+		if md.fastPair != nil && md.name == "" {
+			md.name = md.fastPair.Label()
+		}
+		if md.sensor != nil && md.name == "" {
+			md.name = md.sensor(0).Label()
+		}
+		switch {
+		case tmpl.Type == DeviceTypeClassic:
+			md.addrType = AddressPublic
+		case tmpl.Type == DeviceTypeWiFi:
+			md.addrType = AddressUnknown
+		case mockRotating[ti]:
+			md.addrType = AddressResolvable
+		default:
+			md.addrType = AddressRandomStatic
+		}
+		if ti == mockIdentity {
+			key, _ := hex.DecodeString(MockIRK)
+			md.irk, _ = aes.NewCipher(key)
+		}
+		md.oui = mockOUI[ti]
+		md.class = mockClass[ti]
+		if ti == mockHotspot {
+			md.oui = []byte{0x02 | byte(rand.Intn(64))<<2, byte(rand.Intn(256)), byte(rand.Intn(256))}
+		}
+		md.mac = md.newAddress()
+		if tmpl.Type == DeviceTypeBLE {
+			md.shape = AdvShape{Name: md.name}.Fingerprint()
+		}
+		for _, id := range adapters {
+			md.offsets = append(md.offsets, md.adapterOffset(positions, id))
 		}
 		if tmpl.Type == DeviceTypeWiFi {
 			if rand.Intn(2) == 0 {
@@ -133,16 +358,24 @@ func NewMockScanner() *MockScanner {
 		devices[i] = md
 	}
 
-	return &MockScanner{devices: devices}
+	return &MockScanner{devices: devices, adapters: adapters}
+}
+
+// Name implements Scanner.
+func (s *MockScanner) Name() string { return "mock" }
+
+// Capabilities implements Scanner.
+func (s *MockScanner) Capabilities() Capability {
+	return CapBLE | CapClassic | CapWiFi | CapSynthetic
 }
 
 // Start begins the mock scanner.
-func (s *MockScanner) Start(p *tea.Program) error {
-	s.program = p
-	s.running = true
+func (s *MockScanner) Start(ctx context.Context, sink Sink) error {
+	s.sink = sink
 
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(ctx)
 	s.cancel = cancel
+	s.reset()
 
 	go s.loop(ctx)
 	return nil
@@ -151,6 +384,7 @@ func (s *MockScanner) Start(p *tea.Program) error {
 func (s *MockScanner) loop(ctx context.Context) {
 	ticker := time.NewTicker(200 * time.Millisecond)
 	defer ticker.Stop()
+	defer s.set(StateStopped, nil)
 
 	t := 0.0
 	for {
@@ -158,9 +392,6 @@ func (s *MockScanner) loop(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			if !s.running {
-				return
-			}
 			t += 0.2
 			s.emitDevices(t)
 		}
@@ -178,6 +409,12 @@ func (s *MockScanner) emitDevices(t float64) {
 		if !d.active {
 			continue
 		}
+		if d.tracker != nil && d.tracker.Kind == TrackerFindMy && t-d.rotated >= mockRotation {
+			d.mac, d.rotated = d.newAddress(), t
+		}
+		if d.addrType.Rotating() && t-d.rotated >= mockRPARotation {
+			d.mac, d.rotated = d.newAddress(), t
+		}
 
 		// Sinusoidal RSSI fluctuation + noise
 		rssi := d.baseRSSI + d.amplitude*math.Sin(t*0.5+d.phase) + (rand.Float64()-0.5)*4
@@ -195,25 +432,132 @@ func (s *MockScanner) emitDevices(t float64) {
 			Type:      d.dtype,
 			Frequency: d.freq,
 			Channel:   d.channel,
+			Beacon:    d.beacon,
+			Tracker:   d.tracker,
+
+			Continuity:  d.apple,
+			Microsoft:   d.microsoft,
+			FastPair:    d.fastPair,
+			Platform:    d.platform,
+			AddressType: d.addrType,
+			Fingerprint: d.shape,
+
+			Advertisement: d.adv,
+			ClassOfDevice: d.class,
 		}
-		if s.program != nil {
-			s.program.Send(msg)
+		if d.adv != nil {
+			msg.TxPower, msg.HasTxPower = d.adv.TxPower, d.adv.HasTxPower
+		}
+		if d.sensor != nil {
+			msg.Sensor = d.sensor(t)
+		}
+		if len(s.adapters) == 0 || d.dtype != DeviceTypeBLE {
+			s.emit(s.sink, msg)
+			continue
+		}
+		for ai, id := range s.adapters {
+			m := msg
+			m.Adapter = id
+			m.RSSI = int16(rssi + d.offsets[ai] + (rand.Float64()-0.5)*4)
+			s.emit(s.sink, m)
 		}
 	}
 }
 
+// adapterOffset returns the RSSI difference between the radar center and
+// adapter id for this device, or a random offset if id has no position.
+func (d *mockDevice) adapterOffset(positions map[string]Point, id string) float64 {
+	at, ok := positions[id]
+	if !ok {
+		return (rand.Float64() - 0.5) * 16 // ±8 dB
+	}
+	// Place the device where the MAC hash points, at the distance its base
+	// RSSI implies, then apply the path loss difference to the adapter.
+	dist := RSSIToDistance(d.baseRSSI, -59, mockPathLossExp)
+	angle := MacToAngle(d.mac)
+	x, y := dist*math.Sin(angle), dist*math.Cos(angle)
+	fromAdapter := math.Max(0.1, math.Hypot(x-at.X, y-at.Y))
+	return -10 * mockPathLossExp * math.Log10(fromAdapter/dist)
+}
+
 // Stop halts the mock scanner.
 func (s *MockScanner) Stop() {
-	s.running = false
 	if s.cancel != nil {
 		s.cancel()
 	}
 }
 
-func randomMAC() string {
+// newAddress picks the device's next address: resolvable with its IRK if
+// it has one, under its OUI if it has one, otherwise random of its address
+// type.
+func (d *mockDevice) newAddress() string {
+	if d.oui != nil {
+		return fmt.Sprintf("%02X:%02X:%02X:%02X:%02X:%02X", d.oui[0], d.oui[1], d.oui[2],
+			rand.Intn(256), rand.Intn(256), rand.Intn(256))
+	}
+	if d.irk == nil {
+		return randomAddress(d.addrType)
+	}
+	prand := [3]byte{byte(rand.Intn(256))&0x3F | 0x40, byte(rand.Intn(256)), byte(rand.Intn(256))}
+	h := ah(d.irk, prand)
+	return fmt.Sprintf("%02X:%02X:%02X:%02X:%02X:%02X", prand[0], prand[1], prand[2], h[0], h[1], h[2])
+}
+
+// mockAdvertisement encodes the AD structures of a BLE template and parses
+// them back like a raw scan would. Beacons and trackers are advertised as
+// non-connectable; everything else is connectable and discoverable.
+func mockAdvertisement(ti int, name string) *Advertisement {
+	var raw []byte
+	add := func(typ byte, data ...byte) {
+		raw = append(append(raw, byte(len(data)+1), typ), data...)
+	}
+	connectable := mockBeacons[ti] == nil && mockTrackers[ti] == nil
+	if connectable {
+		add(adTypeFlags, byte(FlagLEGeneral|FlagNoBREDR))
+	}
+	if name != "" {
+		add(adTypeCompleteName, []byte(name)...)
+	}
+	if uuids := mockServices[ti]; len(uuids) > 0 {
+		var b []byte
+		for _, u := range uuids {
+			b = binary.LittleEndian.AppendUint16(b, u)
+		}
+		add(adTypeComplete16, b...)
+	}
+	if a, ok := mockAppearance[ti]; ok {
+		add(adTypeAppearance, byte(a), byte(a>>8))
+	}
+	if ti == 7 || ti == 24 {
+		add(adTypeTxPower, 4)
+	}
+	if data, ok := mockContinuity[ti]; ok {
+		add(adTypeManufacturer, append(binary.LittleEndian.AppendUint16(nil, companyApple), data...)...)
+	}
+	if data, ok := mockMicrosoft[ti]; ok {
+		add(adTypeManufacturer, append(binary.LittleEndian.AppendUint16(nil, companyMicrosoft), data...)...)
+	}
+	if data, ok := mockFastPair[ti]; ok {
+		add(adTypeServiceData16, append(binary.LittleEndian.AppendUint16(nil, serviceFastPair), data...)...)
+	}
+	a := ParseAdvertisement(raw)
+	a.Connectable, a.HasConnectable = connectable, true
+	return a
+}
+
+// randomAddress returns a random MAC whose top bits match the address type.
+func randomAddress(t AddressType) string {
 	b := make([]byte, 6)
 	for i := range b {
 		b[i] = byte(rand.Intn(256))
 	}
+	switch t {
+	case AddressRandomStatic:
+		b[0] |= 0xC0
+	case AddressResolvable:
+		b[0] = b[0]&0x3F | 0x40
+	case AddressNonResolvable:
+		b[0] &= 0x3F
+	}
 	return fmt.Sprintf("%02X:%02X:%02X:%02X:%02X:%02X", b[0], b[1], b[2], b[3], b[4], b[5])
 }
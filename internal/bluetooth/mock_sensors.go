@@ -0,0 +1,72 @@
+package bluetooth
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// mockSensors maps template indexes to a function building the sensor
+// frame they advertise at simulated time t. Frames are encoded and then
+// decoded with the real parsers.
+var mockSensors = map[int]func(t float64) *Sensor{
+	26: mockRuuvi,
+	27: mockGovee,
+	28: mockBTHome,
+	29: mockMiBeacon,
+}
+
+// mockClimate returns a slowly drifting indoor temperature and humidity.
+func mockClimate(t, phase float64) (temp, humidity float64) {
+	return 21.5 + 1.5*math.Sin(t/40+phase), 45 + 6*math.Sin(t/55+phase)
+}
+
+// mockRuuvi encodes a RuuviTag RAWv2 frame.
+func mockRuuvi(t float64) *Sensor {
+	temp, hum := mockClimate(t, 0)
+	b := make([]byte, ruuviRAWv2Length)
+	b[0] = ruuviRAWv2
+	binary.BigEndian.PutUint16(b[1:3], uint16(int16(temp/0.005)))
+	binary.BigEndian.PutUint16(b[3:5], uint16(hum/0.0025))
+	binary.BigEndian.PutUint16(b[5:7], uint16(101325+200*math.Sin(t/90)-50000))
+	binary.BigEndian.PutUint16(b[11:13], 1000) // 1 g down
+	binary.BigEndian.PutUint16(b[13:15], (2950-ruuviBatteryBaseMV)<<5|0x0C)
+	b[15] = byte(t / 30)
+	binary.BigEndian.PutUint16(b[16:18], uint16(t))
+	return ParseRuuvi(companyRuuvi, b)
+}
+
+// mockGovee encodes a Govee H5075 frame.
+func mockGovee(t float64) *Sensor {
+	temp, hum := mockClimate(t, 1)
+	v := uint32(math.Round(temp*10))*1000 + uint32(math.Round(hum*10))
+	return ParseGovee(companyGovee, []byte{0x00, byte(v >> 16), byte(v >> 8), byte(v), 87, 0x00})
+}
+
+// mockBTHome encodes an unencrypted BTHome v2 frame with packet id,
+// battery, temperature and humidity.
+func mockBTHome(t float64) *Sensor {
+	temp, hum := mockClimate(t, 2)
+	b := []byte{btHomeVersion2 << btHomeVersionShift, 0x00, byte(t), 0x01, 92, 0x02, 0, 0, 0x03, 0, 0}
+	binary.LittleEndian.PutUint16(b[6:8], uint16(int16(temp*100)))
+	binary.LittleEndian.PutUint16(b[9:11], uint16(hum*100))
+	return ParseBTHome(b)
+}
+
+// mockMiBeacon encodes an unencrypted MiBeacon frame of a Xiaomi
+// thermometer, which alternates between a reading and its battery level.
+func mockMiBeacon(t float64) *Sensor {
+	temp, hum := mockClimate(t, 3)
+	fc := uint16(0x5000 | miBeaconHasMAC | miBeaconHasObject)
+	b := []byte{byte(fc), byte(fc >> 8), 0xAA, 0x01, byte(t),
+		0x4C, 0x65, 0xA8, 0xD0, 0x1F, 0x3A}
+	if int(t)%3 == 0 {
+		b = binary.LittleEndian.AppendUint16(b, miObjectBattery)
+		b = append(b, 1, 76)
+	} else {
+		b = binary.LittleEndian.AppendUint16(b, miObjectTempHumid)
+		b = append(b, 4, 0, 0, 0, 0)
+		binary.LittleEndian.PutUint16(b[len(b)-4:], uint16(int16(temp*10)))
+		binary.LittleEndian.PutUint16(b[len(b)-2:], uint16(hum*10))
+	}
+	return ParseMiBeacon(b)
+}
@@ -0,0 +1,85 @@
+package bluetooth
+
+import (
+	"context"
+	"errors"
+)
+
+// MultiScanner runs several scanners of the same kind as one, e.g. a
+// BLEScanner per adapter. It reports a combined status.
+type MultiScanner struct {
+	name     string
+	scanners []Scanner
+}
+
+// NewMultiScanner groups scanners under a single registry name.
+func NewMultiScanner(name string, scanners ...Scanner) *MultiScanner {
+	return &MultiScanner{name: name, scanners: scanners}
+}
+
+// Name implements Scanner.
+func (m *MultiScanner) Name() string { return m.name }
+
+// Capabilities implements Scanner.
+func (m *MultiScanner) Capabilities() Capability {
+	var c Capability
+	for _, s := range m.scanners {
+		c |= s.Capabilities()
+	}
+	return c
+}
+
+// Start starts every member. If one fails, the ones already started are
+// stopped again and the error is returned.
+func (m *MultiScanner) Start(ctx context.Context, sink Sink) error {
+	for i, s := range m.scanners {
+		if err := s.Start(ctx, sink); err != nil {
+			for _, started := range m.scanners[:i] {
+				started.Stop()
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// Stop stops every member.
+func (m *MultiScanner) Stop() {
+	for _, s := range m.scanners {
+		s.Stop()
+	}
+}
+
+// Status implements Scanner. The group is running while any member runs
+// and failed only when every member failed.
+func (m *MultiScanner) Status() Status {
+	var st Status
+	var errs []error
+	running, failed := 0, 0
+	for _, s := range m.scanners {
+		ms := s.Status()
+		st.Results += ms.Results
+		if ms.LastResult.After(st.LastResult) {
+			st.LastResult = ms.LastResult
+		}
+		switch ms.State {
+		case StateRunning:
+			running++
+		case StateFailed:
+			failed++
+		}
+		if ms.Err != nil {
+			errs = append(errs, ms.Err)
+		}
+	}
+	switch {
+	case running > 0:
+		st.State = StateRunning
+	case failed == len(m.scanners) && failed > 0:
+		st.State = StateFailed
+	default:
+		st.State = StateStopped
+	}
+	st.Err = errors.Join(errs...)
+	return st
+}
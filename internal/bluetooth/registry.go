@@ -0,0 +1,165 @@
+package bluetooth
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ScannerOptions carries the settings a scanner factory may need.
+type ScannerOptions struct {
+	Adapter  string   // HCI adapter name, e.g. "hci0"
+	Adapters []string // Adapters to scan BLE on concurrently; empty uses Adapter
+
+	AdapterPositions map[string]Point // Known adapter positions, used by the mock
+
+	ClassicInterval time.Duration // Pause between classic inquiries
+	WiFiInterval    time.Duration // Pause between WiFi scans
+
+	ReplayPath  string  // Session file for the replay scanner
+	ReplaySpeed float64 // Playback speed multiplier, 0 = as fast as possible
+	ReplayStep  bool    // Advance one entry per Step() call
+}
+
+// BLEAdapters returns the adapters BLE scanning should run on.
+func (o ScannerOptions) BLEAdapters() []string {
+	if len(o.Adapters) > 0 {
+		return o.Adapters
+	}
+	return []string{o.Adapter}
+}
+
+// ScannerFactory builds a scanner from options.
+type ScannerFactory func(opts ScannerOptions) (Scanner, error)
+
+// Registration describes a scanner type known to the registry.
+type Registration struct {
+	Name        string
+	Description string
+	Default     bool        // Enabled when no explicit selection is made
+	Available   func() bool // Nil means always available
+	New         ScannerFactory
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Registration)
+)
+
+// Register adds a scanner type to the registry. It panics on duplicate
+// names, as registration happens from init functions.
+func Register(r Registration) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, dup := registry[r.Name]; dup {
+		panic("bluetooth: scanner registered twice: " + r.Name)
+	}
+	registry[r.Name] = r
+}
+
+// Registered returns all registrations sorted by name.
+func Registered() []Registration {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	result := make([]Registration, 0, len(registry))
+	for _, r := range registry {
+		result = append(result, r)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// LookupScanner returns the registration with the given name.
+func LookupScanner(name string) (Registration, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	r, ok := registry[name]
+	return r, ok
+}
+
+// NewScanners builds scanners by name. With no names, every default
+// registration that is available on this system is used. Names prefixed
+// with "-" are removed from the default set instead (e.g. "-wifi").
+func NewScanners(names []string, opts ScannerOptions) ([]Scanner, error) {
+	selected, err := selectScanners(names)
+	if err != nil {
+		return nil, err
+	}
+
+	scanners := make([]Scanner, 0, len(selected))
+	for _, r := range selected {
+		s, err := r.New(opts)
+		if err != nil {
+			return nil, fmt.Errorf("%s scanner: %w", r.Name, err)
+		}
+		scanners = append(scanners, s)
+	}
+	return scanners, nil
+}
+
+func selectScanners(names []string) ([]Registration, error) {
+	var include, exclude []string
+	for _, n := range names {
+		n = strings.TrimSpace(n)
+		if n == "" {
+			continue
+		}
+		if strings.HasPrefix(n, "-") {
+			exclude = append(exclude, n[1:])
+		} else {
+			include = append(include, n)
+		}
+	}
+
+	for _, n := range append(append([]string{}, include...), exclude...) {
+		if _, ok := LookupScanner(n); !ok {
+			return nil, fmt.Errorf("unknown scanner %q (available: %s)", n, strings.Join(ScannerNames(), ", "))
+		}
+	}
+
+	var selected []Registration
+	if len(include) > 0 {
+		for _, n := range include {
+			r, _ := LookupScanner(n)
+			if r.Available != nil && !r.Available() {
+				return nil, fmt.Errorf("scanner %q is not available on this system", n)
+			}
+			selected = append(selected, r)
+		}
+	} else {
+		for _, r := range Registered() {
+			if r.Default && (r.Available == nil || r.Available()) {
+				selected = append(selected, r)
+			}
+		}
+	}
+
+	if len(exclude) == 0 {
+		return selected, nil
+	}
+	skip := make(map[string]bool, len(exclude))
+	for _, n := range exclude {
+		skip[n] = true
+	}
+	result := selected[:0]
+	for _, r := range selected {
+		if !skip[r.Name] {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+// ScannerNames returns the names of all registered scanners.
+func ScannerNames() []string {
+	regs := Registered()
+	names := make([]string, len(regs))
+	for i, r := range regs {
+		names[i] = r.Name
+	}
+	return names
+}
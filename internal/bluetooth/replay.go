@@ -0,0 +1,145 @@
+package bluetooth
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+)
+
+func init() {
+	Register(Registration{
+		Name:        "replay",
+		Description: "Replays a recorded session file (--replay)",
+		New: func(opts ScannerOptions) (Scanner, error) {
+			if opts.ReplayPath == "" {
+				return nil, errors.New("no session file given (use --replay)")
+			}
+			return NewReplayScanner(opts.ReplayPath, opts.ReplaySpeed, opts.ReplayStep)
+		},
+	})
+}
+
+// Stepper is implemented by scanners that can be advanced manually.
+type Stepper interface {
+	Step()
+}
+
+// ReplayScanner feeds a recorded session back as DeviceDiscoveredMsg values,
+// preserving the original inter-message timing scaled by speed. Each message
+// carries its recorded time in At, so the store decides on the recorded
+// clock whatever the speed.
+type ReplayScanner struct {
+	health
+	sink    Sink
+	entries []SessionEntry
+	speed   float64 // 1 = real time, 2 = twice as fast, 0 = no delay
+	step    bool    // Wait for Step() before each entry
+	steps   chan struct{}
+	cancel  context.CancelFunc
+}
+
+// NewReplayScanner loads the session at path. In step mode each call to
+// Step emits the next entry; otherwise entries are emitted on their
+// recorded schedule divided by speed.
+func NewReplayScanner(path string, speed float64, step bool) (*ReplayScanner, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	entries, err := ReadSession(f)
+	if err != nil {
+		return nil, err
+	}
+	if speed < 0 {
+		speed = 0
+	}
+	return &ReplayScanner{
+		entries: entries,
+		speed:   speed,
+		step:    step,
+		steps:   make(chan struct{}, 1),
+	}, nil
+}
+
+// Name implements Scanner.
+func (s *ReplayScanner) Name() string { return "replay" }
+
+// Capabilities implements Scanner.
+func (s *ReplayScanner) Capabilities() Capability {
+	var c Capability = CapSynthetic | CapRSSI
+	for _, e := range s.entries {
+		switch e.Msg.Type {
+		case DeviceTypeClassic:
+			c |= CapClassic
+		case DeviceTypeWiFi:
+			c |= CapWiFi
+		default:
+			c |= CapBLE
+		}
+	}
+	return c
+}
+
+// Start begins playback in a goroutine.
+func (s *ReplayScanner) Start(ctx context.Context, sink Sink) error {
+	s.sink = sink
+
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+	s.reset()
+
+	go s.loop(ctx)
+	return nil
+}
+
+func (s *ReplayScanner) loop(ctx context.Context) {
+	defer s.set(StateStopped, nil)
+
+	for i, e := range s.entries {
+		if s.step {
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.steps:
+			}
+		} else if i > 0 && s.speed > 0 {
+			gap := e.Time.Sub(s.entries[i-1].Time)
+			if gap > 0 {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(time.Duration(float64(gap) / s.speed)):
+				}
+			}
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		msg := e.Msg
+		msg.At = e.Time
+		s.emit(s.sink, msg)
+	}
+}
+
+// Step releases the next entry in step mode. It never blocks.
+func (s *ReplayScanner) Step() {
+	select {
+	case s.steps <- struct{}{}:
+	default:
+	}
+}
+
+// Len returns the number of entries in the session.
+func (s *ReplayScanner) Len() int {
+	return len(s.entries)
+}
+
+// Stop halts playback.
+func (s *ReplayScanner) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
@@ -0,0 +1,133 @@
+package bluetooth
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// replayTimeout is the eviction timeout used when replaying testCapture.
+const replayTimeout = 2500 * time.Millisecond
+
+// testCapture is three seconds of a rotating private address and a public
+// device that goes quiet for longer than replayTimeout. The old address
+// must outlive the timeout until the new one settles to be linked.
+func testCapture(t0 time.Time) []DeviceDiscoveredMsg {
+	at := func(ms int) time.Time { return t0.Add(time.Duration(ms) * time.Millisecond) }
+	old := func(ms int) DeviceDiscoveredMsg {
+		return DeviceDiscoveredMsg{MAC: "4A:00:00:00:00:01", RSSI: -60, AddressType: AddressResolvable, Fingerprint: "F", At: at(ms)}
+	}
+	rotated := func(ms int) DeviceDiscoveredMsg {
+		return DeviceDiscoveredMsg{MAC: "5B:00:00:00:00:02", RSSI: -62, AddressType: AddressResolvable, Fingerprint: "F", At: at(ms)}
+	}
+	public := func(ms int) DeviceDiscoveredMsg {
+		return DeviceDiscoveredMsg{MAC: "00:11:22:33:44:55", RSSI: -75, AddressType: AddressPublic, At: at(ms)}
+	}
+	return []DeviceDiscoveredMsg{
+		old(0), public(100), old(300), old(600),
+		rotated(900), rotated(1500), rotated(2200), rotated(3000), public(3100),
+	}
+}
+
+// replayInto replays the session at path at speed through a new store, the
+// way the app and headless mode do, with a fast eviction ticker running
+// alongside, and returns the store once every entry was applied.
+func replayInto(t *testing.T, path string, speed float64) *DeviceStore {
+	t.Helper()
+	s, err := NewReplayScanner(path, speed, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch := make(chan tea.Msg, 16)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := s.Start(ctx, ContextSink(ctx, ch)); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+
+	store := NewDeviceStore(PathLoss{MeasuredPower: -59, Exponent: 2}, func() Smoother { return &emaSmoother{alpha: 0.5} })
+	evict := time.NewTicker(5 * time.Millisecond)
+	defer evict.Stop()
+	timeout := time.After(10 * time.Second)
+	for n := 0; n < s.Len(); {
+		select {
+		case <-evict.C:
+			store.Evict(replayTimeout)
+		case m := <-ch:
+			msg := m.(DeviceDiscoveredMsg)
+			store.Advance(msg.At, replayTimeout)
+			store.Upsert(msg)
+			n++
+		case <-timeout:
+			t.Fatalf("replay stalled after %d of %d entries", n, s.Len())
+		}
+	}
+	return store
+}
+
+func TestReplayDeterministic(t *testing.T) {
+	if testing.Short() {
+		t.Skip("replays three seconds in real time")
+	}
+	t0 := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	if err := os.WriteFile(path, recordSession(t, testCapture(t0)...), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	type state struct {
+		MAC                 string
+		Addresses           []string
+		FirstSeen, LastSeen time.Time
+		RSSI                float64
+	}
+	snapshot := func(store *DeviceStore) []state {
+		var out []state
+		for _, d := range store.Snapshot() {
+			out = append(out, state{d.MAC, d.Addresses, d.FirstSeen, d.LastSeen, d.RSSI})
+		}
+		return out
+	}
+
+	realTime := snapshot(replayInto(t, path, 1))
+	fast := snapshot(replayInto(t, path, 100))
+	unthrottled := snapshot(replayInto(t, path, 0))
+	if !reflect.DeepEqual(fast, realTime) {
+		t.Errorf("speed 100:\n%+v\nspeed 1:\n%+v", fast, realTime)
+	}
+	if !reflect.DeepEqual(unthrottled, realTime) {
+		t.Errorf("speed 0:\n%+v\nspeed 1:\n%+v", unthrottled, realTime)
+	}
+
+	// What the live capture decided: the rotation was linked once the new
+	// address settled, and the public device was evicted during its
+	// silence and seen afresh.
+	want := []state{
+		{
+			MAC:       "4A:00:00:00:00:01",
+			Addresses: []string{"4A:00:00:00:00:01", "5B:00:00:00:00:02"},
+			FirstSeen: t0, LastSeen: t0.Add(3000 * time.Millisecond),
+		},
+		{
+			MAC:       "00:11:22:33:44:55",
+			Addresses: []string{"00:11:22:33:44:55"},
+			FirstSeen: t0.Add(3100 * time.Millisecond), LastSeen: t0.Add(3100 * time.Millisecond),
+		},
+	}
+	if len(realTime) != len(want) {
+		t.Fatalf("replayed devices:\n%+v\nwant\n%+v", realTime, want)
+	}
+	for i, w := range want {
+		got := realTime[i]
+		got.RSSI = 0 // Depends on the smoother
+		if !reflect.DeepEqual(got, w) {
+			t.Errorf("device %d = %+v, want %+v", i, got, w)
+		}
+	}
+}
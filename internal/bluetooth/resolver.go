@@ -6,14 +6,13 @@ import (
 	"strings"
 	"sync"
 	"time"
-
-	tea "github.com/charmbracelet/bubbletea"
 )
 
 // NameResolver tries to resolve names for unnamed BLE devices in the background.
 // It uses hcitool name which sends a name request to the device.
 type NameResolver struct {
-	program  *tea.Program
+	adapter  string
+	sink     Sink
 	mu       sync.Mutex
 	tried    map[string]int // MAC -> attempt count
 	resolved map[string]bool
@@ -26,18 +25,19 @@ const (
 	resolvePause   = 3 * time.Second
 )
 
-// NewNameResolver creates a new resolver.
-func NewNameResolver() *NameResolver {
+// NewNameResolver creates a new resolver that queries through adapter.
+func NewNameResolver(adapter string) *NameResolver {
 	return &NameResolver{
+		adapter:  adapter,
 		tried:    make(map[string]int),
 		resolved: make(map[string]bool),
 		stop:     make(chan struct{}),
 	}
 }
 
-// Start begins the resolver background loop.
-func (r *NameResolver) Start(p *tea.Program) {
-	r.program = p
+// Start sets the sink that resolved names are delivered to.
+func (r *NameResolver) Start(sink Sink) {
+	r.sink = sink
 }
 
 // RequestResolve queues a MAC for background name resolution.
@@ -64,7 +64,7 @@ func (r *NameResolver) resolve(mac string) {
 	default:
 	}
 
-	name := tryHcitool(mac)
+	name := tryHcitool(r.adapter, mac)
 	if name == "" {
 		return
 	}
@@ -73,8 +73,8 @@ func (r *NameResolver) resolve(mac string) {
 	r.resolved[mac] = true
 	r.mu.Unlock()
 
-	if r.program != nil {
-		r.program.Send(DeviceDiscoveredMsg{
+	if r.sink != nil {
+		r.sink.Send(DeviceDiscoveredMsg{
 			MAC:  mac,
 			Name: name,
 			RSSI: -100, // placeholder, store will EMA smooth it
@@ -83,11 +83,11 @@ func (r *NameResolver) resolve(mac string) {
 	}
 }
 
-func tryHcitool(mac string) string {
+func tryHcitool(adapter, mac string) string {
 	ctx, cancel := context.WithTimeout(context.Background(), resolveTimeout)
 	defer cancel()
 
-	out, err := exec.CommandContext(ctx, "hcitool", "name", mac).Output()
+	out, err := exec.CommandContext(ctx, "hcitool", "-i", adapter, "name", mac).Output()
 	if err != nil {
 		return ""
 	}
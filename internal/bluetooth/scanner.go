@@ -1,55 +1,152 @@
 package bluetooth
 
 import (
+	"context"
 	"fmt"
+	"time"
 
-	tea "github.com/charmbracelet/bubbletea"
 	"tinygo.org/x/bluetooth"
 )
 
-// DeviceDiscoveredMsg is sent via tea.Program.Send when a device is found.
+func init() {
+	Register(Registration{
+		Name:        "ble",
+		Description: "Bluetooth Low Energy advertisements via BlueZ",
+		Default:     true,
+		New: func(opts ScannerOptions) (Scanner, error) {
+			adapters := opts.BLEAdapters()
+			scanners := make([]Scanner, 0, len(adapters))
+			for _, id := range adapters {
+				if err := CheckAdapter(id); err != nil {
+					return nil, err
+				}
+				scanners = append(scanners, NewBLEScanner(id))
+			}
+			if len(scanners) == 1 {
+				return scanners[0], nil
+			}
+			return NewMultiScanner("ble", scanners...), nil
+		},
+	})
+}
+
+// DeviceDiscoveredMsg is sent to a Sink when a device is found.
 type DeviceDiscoveredMsg struct {
-	MAC       string
-	Name      string
-	RSSI      int16
-	Type      DeviceType
-	Frequency int // MHz, zero for BLE/Classic
-	Channel   int // WiFi channel, zero for BLE/Classic
+	MAC           string
+	Name          string
+	RSSI          int16
+	Type          DeviceType
+	Frequency     int              // MHz, zero for BLE/Classic
+	Channel       int              // WiFi channel, zero for BLE/Classic
+	Beacon        *Beacon          // Decoded beacon frame, nil if none
+	Tracker       *Tracker         // Decoded tracking tag frame, nil if none
+	Continuity    *Continuity      // Decoded Apple Continuity messages, nil if none
+	Microsoft     *MicrosoftBeacon // Decoded CDP or Swift Pair beacon, nil if none
+	FastPair      *FastPair        // Decoded Google Fast Pair advertisement, nil if none
+	Sensor        *Sensor          // Decoded Ruuvi/BTHome/MiBeacon/Govee readings, nil if none
+	Advertisement *Advertisement   // Every AD field as received, nil for Classic/WiFi
+	Platform      string           // Ecosystem derived from the decoded frames, see platformOf
+	Adapter       string           // HCI adapter that heard it, empty if not adapter-specific
+	ClassOfDevice DeviceClass      // Class of Device of a Classic device, zero if not reported
+
+	AddressType AddressType // Derived from the address bits, unknown if not reported
+	Fingerprint string      // AdvShape fingerprint for linking rotating addresses
+
+	TxPower    int8 // Advertised TX Power Level (dBm)
+	HasTxPower bool // TxPower is valid
+
+	// At is the recorded time of a replayed message, zero for live ones.
+	// It is not written to session files: SessionEntry.Time carries it.
+	At time.Time `json:"-"`
 }
 
 // BLEScanner handles Bluetooth Low Energy scanning.
 type BLEScanner struct {
+	health
+	id      string
 	adapter *bluetooth.Adapter
-	program *tea.Program
-	running bool
+	sink    Sink
+	cancel  context.CancelFunc
+	props   *bluezLEProps // Device1 properties, nil without bluetoothd
 }
 
 // NewBLEScanner creates a scanner for the given adapter name (e.g., "hci0").
-func NewBLEScanner() *BLEScanner {
+func NewBLEScanner(adapter string) *BLEScanner {
 	return &BLEScanner{
-		adapter: bluetooth.DefaultAdapter,
+		id:      adapter,
+		adapter: bluetooth.NewAdapter(adapter),
 	}
 }
 
+// Name implements Scanner.
+func (s *BLEScanner) Name() string { return "ble" }
+
+// Capabilities implements Scanner.
+func (s *BLEScanner) Capabilities() Capability { return CapBLE | CapRSSI }
+
 // Start begins BLE scanning in a goroutine. Discovered devices are sent
-// as tea messages via program.Send().
-func (s *BLEScanner) Start(p *tea.Program) error {
-	s.program = p
+// to sink until ctx is cancelled or Stop is called.
+func (s *BLEScanner) Start(ctx context.Context, sink Sink) error {
+	s.sink = sink
 
 	if err := s.adapter.Enable(); err != nil {
-		return fmt.Errorf("failed to enable BLE adapter: %w (try running with sudo or setcap cap_net_admin+ep)", err)
+		err = fmt.Errorf("failed to enable BLE adapter %s: %w (try running with sudo or setcap cap_net_admin+ep)", s.id, err)
+		s.set(StateFailed, err)
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+	s.reset()
+
+	// Scan results carry no TX power, flags or appearance on Linux, but
+	// BlueZ keeps the ones it decoded on the device object. Best effort:
+	// without it those fields stay unset.
+	if bus, err := bluezSystemBus(); err == nil {
+		s.props = newBluezLEProps()
+		go func() { _ = s.props.watch(ctx, bus, s.id) }()
 	}
 
-	s.running = true
 	go func() {
-		_ = s.adapter.Scan(func(adapter *bluetooth.Adapter, result bluetooth.ScanResult) {
-			if !s.running {
+		<-ctx.Done()
+		s.set(StateStopped, nil)
+		_ = s.adapter.StopScan()
+	}()
+	go func() {
+		err := s.adapter.Scan(func(adapter *bluetooth.Adapter, result bluetooth.ScanResult) {
+			if ctx.Err() != nil {
 				return
 			}
 
 			name := result.LocalName()
+			beacon := decodeBeacon(result)
+			tracker := decodeTracker(result)
+			apple := decodeContinuity(result)
+			ms := decodeMicrosoft(result)
+			fastPair := decodeFastPair(result)
+			sensor := decodeSensor(result)
+			adv := decodeAdvertisement(result)
 
-			// Fallback: identify device by manufacturer data
+			// Fallback: identify device by beacon, tracker, decoded device
+			// class or model, or manufacturer data
+			if name == "" && beacon != nil {
+				name = beacon.Label()
+			}
+			if name == "" && tracker != nil {
+				name = tracker.Label()
+			}
+			if name == "" && apple != nil {
+				name = apple.Class
+			}
+			if name == "" && ms != nil {
+				name = ms.Label()
+			}
+			if name == "" && fastPair != nil {
+				name = fastPair.Label()
+			}
+			if name == "" && sensor != nil {
+				name = sensor.Label()
+			}
 			if name == "" {
 				mfrs := result.ManufacturerData()
 				if len(mfrs) > 0 {
@@ -61,23 +158,212 @@ func (s *BLEScanner) Start(p *tea.Program) error {
 				}
 			}
 
+			mac := result.Address.String()
 			msg := DeviceDiscoveredMsg{
-				MAC:  result.Address.String(),
-				Name: name,
-				RSSI: result.RSSI,
-				Type: DeviceTypeBLE,
+				MAC:           mac,
+				Name:          name,
+				RSSI:          result.RSSI,
+				Type:          DeviceTypeBLE,
+				Beacon:        beacon,
+				Tracker:       tracker,
+				Continuity:    apple,
+				Microsoft:     ms,
+				FastPair:      fastPair,
+				Sensor:        sensor,
+				Advertisement: adv,
+				Platform:      platformOf(apple, ms, fastPair),
+				Adapter:       s.id,
+				AddressType:   ClassifyAddress(mac, result.Address.IsRandom()),
 			}
-			if s.program != nil {
-				s.program.Send(msg)
+			msg.TxPower, msg.HasTxPower = adv.TxPower, adv.HasTxPower
+			msg.Fingerprint = shapeOf(result, msg).Fingerprint()
+			// Completed after the fingerprint, which must not change once
+			// BlueZ reports the properties of a new address.
+			if len(adv.Raw) == 0 {
+				s.props.complete(mac, adv)
+				msg.TxPower, msg.HasTxPower = adv.TxPower, adv.HasTxPower
 			}
+			s.emit(s.sink, msg)
 		})
+		if err != nil && ctx.Err() == nil {
+			s.set(StateFailed, err)
+		}
 	}()
 
 	return nil
 }
 
+// decodeAdvertisement returns every AD field of the result. Stacks that
+// report the raw bytes are parsed in full; BlueZ only passes on the
+// decoded name, services, manufacturer data and service data, and the
+// scanner completes the TX power, flags and appearance from bluezLEProps.
+// BlueZ does not say whether a service list was complete, so none is.
+func decodeAdvertisement(result bluetooth.ScanResult) *Advertisement {
+	if raw := result.Bytes(); len(raw) > 0 {
+		return ParseAdvertisement(raw)
+	}
+	a := &Advertisement{LocalName: result.LocalName()}
+	for _, u := range result.ServiceUUIDs() {
+		a.addService(u, false)
+	}
+	for _, m := range result.ManufacturerData() {
+		a.Manufacturer = append(a.Manufacturer, ManufacturerData{CompanyID: m.CompanyID, Data: append(HexBytes(nil), m.Data...)})
+	}
+	for _, sd := range result.ServiceData() {
+		a.addServiceData(sd.UUID, sd.Data)
+	}
+	return a
+}
+
+// decodeBeacon returns the first iBeacon or Eddystone frame in the result.
+func decodeBeacon(result bluetooth.ScanResult) *Beacon {
+	for _, m := range result.ManufacturerData() {
+		if b := ParseIBeacon(m.CompanyID, m.Data); b != nil {
+			return b
+		}
+	}
+	eddystone := bluetooth.New16BitUUID(serviceEddystone)
+	for _, sd := range result.ServiceData() {
+		if sd.UUID == eddystone {
+			if b := ParseEddystone(sd.Data); b != nil {
+				return b
+			}
+		}
+	}
+	return nil
+}
+
+// decodeContinuity returns the Apple Continuity messages in the result.
+func decodeContinuity(result bluetooth.ScanResult) *Continuity {
+	for _, m := range result.ManufacturerData() {
+		if c := ParseContinuity(m.CompanyID, m.Data); c != nil {
+			return c
+		}
+	}
+	return nil
+}
+
+// decodeMicrosoft returns the CDP or Swift Pair beacon in the result.
+func decodeMicrosoft(result bluetooth.ScanResult) *MicrosoftBeacon {
+	for _, m := range result.ManufacturerData() {
+		if b := ParseMicrosoft(m.CompanyID, m.Data); b != nil {
+			return b
+		}
+	}
+	return nil
+}
+
+// decodeFastPair returns the Fast Pair advertisement in the result.
+func decodeFastPair(result bluetooth.ScanResult) *FastPair {
+	fastPair := bluetooth.New16BitUUID(serviceFastPair)
+	for _, sd := range result.ServiceData() {
+		if sd.UUID == fastPair {
+			return ParseFastPair(sd.Data)
+		}
+	}
+	return nil
+}
+
+// decodeSensor returns the readings of a Ruuvi, Govee, BTHome or MiBeacon
+// frame in the result.
+func decodeSensor(result bluetooth.ScanResult) *Sensor {
+	for _, m := range result.ManufacturerData() {
+		if s := ParseRuuvi(m.CompanyID, m.Data); s != nil {
+			return s
+		}
+		if s := ParseGovee(m.CompanyID, m.Data); s != nil {
+			return s
+		}
+	}
+	btHome := bluetooth.New16BitUUID(serviceBTHome)
+	miBeacon := bluetooth.New16BitUUID(serviceMiBeacon)
+	for _, sd := range result.ServiceData() {
+		switch sd.UUID {
+		case btHome:
+			if s := ParseBTHome(sd.Data); s != nil {
+				return s
+			}
+		case miBeacon:
+			if s := ParseMiBeacon(sd.Data); s != nil {
+				return s
+			}
+		}
+	}
+	return nil
+}
+
+// platformOf derives the ecosystem a device belongs to or pairs with:
+// Apple, Windows, Android, Xbox or Linux. Empty if nothing tells.
+func platformOf(apple *Continuity, ms *MicrosoftBeacon, fastPair *FastPair) string {
+	switch {
+	case apple != nil:
+		return "Apple"
+	case ms != nil:
+		return ms.Platform()
+	case fastPair != nil:
+		return "Android"
+	}
+	return ""
+}
+
+// shapeOf extracts the address-independent shape of an advertisement.
+// The local name is used as advertised, not the fallback name.
+func shapeOf(result bluetooth.ScanResult, msg DeviceDiscoveredMsg) AdvShape {
+	shape := AdvShape{
+		Name:       result.LocalName(),
+		TxPower:    msg.TxPower,
+		HasTxPower: msg.HasTxPower,
+	}
+	for _, m := range result.ManufacturerData() {
+		ms := ManufacturerShape{CompanyID: m.CompanyID, Length: len(m.Data)}
+		if len(m.Data) > 0 {
+			ms.Type = m.Data[0]
+		}
+		shape.Manufacturer = append(shape.Manufacturer, ms)
+	}
+	for _, u := range result.ServiceUUIDs() {
+		shape.Services = append(shape.Services, u.String())
+	}
+	for _, sd := range result.ServiceData() {
+		shape.ServiceData = append(shape.ServiceData, sd.UUID.String())
+	}
+	return shape
+}
+
+// decodeTracker returns the Find My, Tile or SmartTag frame in the result.
+func decodeTracker(result bluetooth.ScanResult) *Tracker {
+	for _, m := range result.ManufacturerData() {
+		if t := ParseFindMy(m.CompanyID, m.Data); t != nil {
+			return t
+		}
+		if m.CompanyID == companyTile {
+			return tileTracker()
+		}
+	}
+	smartTag := bluetooth.New16BitUUID(serviceSmartTag)
+	for _, sd := range result.ServiceData() {
+		if sd.UUID == smartTag {
+			if t := ParseSmartTag(sd.Data); t != nil {
+				return t
+			}
+		}
+		if sd.UUID.Is16Bit() {
+			if t := ParseTile(sd.UUID.Get16Bit(), sd.Data); t != nil {
+				return t
+			}
+		}
+	}
+	for _, u := range []uint16{serviceTile, serviceTileLegacy} {
+		if result.HasServiceUUID(bluetooth.New16BitUUID(u)) {
+			return tileTracker()
+		}
+	}
+	return nil
+}
+
 // Stop halts the BLE scanner.
 func (s *BLEScanner) Stop() {
-	s.running = false
-	_ = s.adapter.StopScan()
+	if s.cancel != nil {
+		s.cancel()
+	}
 }
@@ -0,0 +1,134 @@
+package bluetooth
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// bluezLE is what BlueZ decoded from the advertisements of one device but
+// tinygo's ScanResult does not pass on.
+type bluezLE struct {
+	txPower       int16
+	hasTxPower    bool
+	appearance    uint16
+	hasAppearance bool
+	flags         AdvFlags
+	hasFlags      bool
+}
+
+// bluezLEProps follows the Device1 objects of one adapter, so that BLE
+// scan results can be completed with the properties BlueZ keeps there.
+type bluezLEProps struct {
+	mu      sync.Mutex
+	devices map[string]bluezLE // By address
+}
+
+func newBluezLEProps() *bluezLEProps {
+	return &bluezLEProps{devices: make(map[string]bluezLE)}
+}
+
+// watch loads the devices BlueZ already knows on adapter, then follows
+// their property changes until ctx is done.
+func (p *bluezLEProps) watch(ctx context.Context, bus bluezBus, adapter string) error {
+	signals := make(chan *dbus.Signal, 64)
+	unsubscribe, err := bus.Subscribe(signals)
+	if err != nil {
+		return err
+	}
+	defer unsubscribe()
+
+	path := dbus.ObjectPath("/org/bluez/" + adapter)
+	var objects map[dbus.ObjectPath]map[string]map[string]dbus.Variant
+	if err := bus.Call(ctx, "/", dbusObjectManager+".GetManagedObjects", &objects); err != nil {
+		return err
+	}
+	for obj, ifaces := range objects {
+		if props, ok := ifaces[bluezDevice1]; ok && onAdapter(obj, path) {
+			p.update(obj, props, nil)
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case sig := <-signals:
+			if dev, props, invalidated, ok := deviceProperties(sig); ok && onAdapter(dev, path) {
+				p.update(dev, props, invalidated)
+			}
+		}
+	}
+}
+
+// update applies changed Device1 properties of the object at path.
+func (p *bluezLEProps) update(path dbus.ObjectPath, props map[string]dbus.Variant, invalidated []string) {
+	addr := addressOf(path)
+	if addr == "" {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	d := p.devices[addr]
+	if v, ok := props["TxPower"]; ok && v.Store(&d.txPower) == nil {
+		d.hasTxPower = true
+	}
+	if v, ok := props["Appearance"]; ok && v.Store(&d.appearance) == nil {
+		d.hasAppearance = true
+	}
+	var flags []byte
+	if v, ok := props["AdvertisingFlags"]; ok && v.Store(&flags) == nil && len(flags) > 0 {
+		d.flags, d.hasFlags = AdvFlags(flags[0]), true
+	}
+	for _, key := range invalidated {
+		switch key {
+		case "TxPower":
+			d.hasTxPower = false
+		case "Appearance":
+			d.hasAppearance = false
+		case "AdvertisingFlags":
+			d.hasFlags = false
+		}
+	}
+	p.devices[addr] = d
+}
+
+// complete fills in the fields of a that BlueZ decoded for addr but the
+// scan result left out. A nil p leaves a unchanged.
+func (p *bluezLEProps) complete(addr string, a *Advertisement) {
+	if p == nil || a == nil {
+		return
+	}
+	p.mu.Lock()
+	d, ok := p.devices[addr]
+	p.mu.Unlock()
+	if !ok {
+		return
+	}
+	if d.hasTxPower && !a.HasTxPower {
+		a.TxPower, a.HasTxPower = int8(d.txPower), true
+	}
+	if d.hasAppearance && !a.HasAppearance {
+		a.Appearance, a.HasAppearance = d.appearance, true
+	}
+	if d.hasFlags && !a.HasFlags {
+		a.Flags, a.HasFlags = d.flags, true
+	}
+}
+
+// addressOf returns the address of the Device1 object at path, e.g.
+// "AA:BB:CC:DD:EE:FF" for /org/bluez/hci0/dev_AA_BB_CC_DD_EE_FF, or "".
+func addressOf(path dbus.ObjectPath) string {
+	s := string(path)
+	i := strings.LastIndex(s, "/dev_")
+	if i < 0 {
+		return ""
+	}
+	addr := strings.ReplaceAll(s[i+len("/dev_"):], "_", ":")
+	if !isValidMAC(addr) {
+		return ""
+	}
+	return addr
+}
@@ -0,0 +1,156 @@
+package bluetooth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestBluezLEPropsWatch(t *testing.T) {
+	bus := &fakeBus{objects: map[dbus.ObjectPath]map[string]map[string]dbus.Variant{
+		"/org/bluez/hci0/dev_C0_00_00_00_00_01": device(map[string]any{"TxPower": int16(-8)}),
+		"/org/bluez/hci1/dev_C0_00_00_00_00_02": device(map[string]any{"TxPower": int16(4)}),
+	}}
+	p := newBluezLEProps()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel() // Load the snapshot only
+	if err := p.watch(ctx, bus, "hci0"); err != nil {
+		t.Fatalf("watch: %v", err)
+	}
+
+	a := &Advertisement{}
+	p.complete("C0:00:00:00:00:01", a)
+	if !a.HasTxPower || a.TxPower != -8 {
+		t.Errorf("TX power = %d (%v), want -8", a.TxPower, a.HasTxPower)
+	}
+	a = &Advertisement{}
+	p.complete("C0:00:00:00:00:02", a)
+	if a.HasTxPower {
+		t.Error("TX power of a device on hci1 completed an hci0 result")
+	}
+}
+
+func TestBluezLEPropsTxPower(t *testing.T) {
+	const path = "/org/bluez/hci0/dev_C0_00_00_00_00_01"
+	tests := []struct {
+		name   string
+		sigs   []*dbus.Signal
+		adv    Advertisement
+		want   int8
+		wantOK bool
+	}{
+		{
+			name: "added",
+			sigs: []*dbus.Signal{interfacesAdded(path, map[string]any{"TxPower": int16(-12)})},
+			want: -12, wantOK: true,
+		},
+		{
+			name: "changed",
+			sigs: []*dbus.Signal{
+				interfacesAdded(path, map[string]any{"TxPower": int16(-12)}),
+				propertiesChanged(path, map[string]any{"TxPower": int16(0)}),
+			},
+			want: 0, wantOK: true,
+		},
+		{
+			name: "invalidated",
+			sigs: []*dbus.Signal{
+				interfacesAdded(path, map[string]any{"TxPower": int16(-12)}),
+				propertiesChanged(path, map[string]any{}, "TxPower"),
+			},
+		},
+		{
+			name: "advertised wins",
+			sigs: []*dbus.Signal{interfacesAdded(path, map[string]any{"TxPower": int16(-12)})},
+			adv:  Advertisement{TxPower: 3, HasTxPower: true},
+			want: 3, wantOK: true,
+		},
+		{
+			name: "not reported",
+			sigs: []*dbus.Signal{interfacesAdded(path, map[string]any{"RSSI": int16(-60)})},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newBluezLEProps()
+			for _, sig := range tt.sigs {
+				dev, props, invalidated, ok := deviceProperties(sig)
+				if !ok {
+					t.Fatalf("signal %s not recognized", sig.Name)
+				}
+				p.update(dev, props, invalidated)
+			}
+			a := tt.adv
+			p.complete("C0:00:00:00:00:01", &a)
+			if a.HasTxPower != tt.wantOK || a.TxPower != tt.want {
+				t.Errorf("TX power = %d (%v), want %d (%v)", a.TxPower, a.HasTxPower, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	// Without bluetoothd there is nothing to complete.
+	var none *bluezLEProps
+	a := &Advertisement{}
+	none.complete("C0:00:00:00:00:01", a)
+	if a.HasTxPower {
+		t.Error("nil bluezLEProps completed a TX power")
+	}
+}
+
+func TestBluezLEPropsAdvertisement(t *testing.T) {
+	const path = "/org/bluez/hci0/dev_C0_00_00_00_00_01"
+	p := newBluezLEProps()
+	for _, sig := range []*dbus.Signal{
+		interfacesAdded(path, map[string]any{"Appearance": uint16(0x03C1), "AdvertisingFlags": []byte{0x06}}),
+		propertiesChanged("/org/bluez/hci0/dev_C0_00_00_00_00_02", map[string]any{"Appearance": uint16(0x0040)}),
+	} {
+		dev, props, invalidated, _ := deviceProperties(sig)
+		p.update(dev, props, invalidated)
+	}
+
+	a := &Advertisement{}
+	p.complete("C0:00:00:00:00:01", a)
+	if !a.HasAppearance || a.Appearance != 0x03C1 {
+		t.Errorf("appearance = 0x%04X (%v), want 0x03C1", a.Appearance, a.HasAppearance)
+	}
+	if !a.HasFlags || a.Flags != 0x06 {
+		t.Errorf("flags = 0x%02X (%v), want 0x06", uint8(a.Flags), a.HasFlags)
+	}
+	if a.HasConnectable {
+		t.Error("connectable completed, but BlueZ does not report it")
+	}
+
+	a = &Advertisement{}
+	p.complete("C0:00:00:00:00:02", a)
+	if !a.HasAppearance || a.Appearance != 0x0040 || a.HasFlags {
+		t.Errorf("second device = appearance 0x%04X (%v), flags %v; want 0x0040 without flags",
+			a.Appearance, a.HasAppearance, a.HasFlags)
+	}
+
+	dev, props, invalidated, _ := deviceProperties(propertiesChanged(path, map[string]any{}, "Appearance", "AdvertisingFlags"))
+	p.update(dev, props, invalidated)
+	a = &Advertisement{}
+	p.complete("C0:00:00:00:00:01", a)
+	if a.HasAppearance || a.HasFlags {
+		t.Error("invalidated appearance or flags still completed")
+	}
+}
+
+func TestAddressOf(t *testing.T) {
+	tests := []struct {
+		path dbus.ObjectPath
+		want string
+	}{
+		{"/org/bluez/hci0/dev_AA_BB_CC_DD_EE_FF", "AA:BB:CC:DD:EE:FF"},
+		{"/org/bluez/hci1/dev_00_1a_7d_da_71_13", "00:1a:7d:da:71:13"},
+		{"/org/bluez/hci0", ""},
+		{"/org/bluez/hci0/dev_AA_BB_CC", ""},
+		{"/org/bluez/hci0/dev_AA_BB_CC_DD_EE_FF/service0010", ""},
+	}
+	for _, tt := range tests {
+		if got := addressOf(tt.path); got != tt.want {
+			t.Errorf("addressOf(%s) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
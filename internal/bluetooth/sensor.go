@@ -0,0 +1,245 @@
+package bluetooth
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+)
+
+// SensorFormat identifies the protocol of an environmental sensor frame.
+type SensorFormat int
+
+const (
+	SensorRuuvi SensorFormat = iota + 1
+	SensorBTHome
+	SensorMiBeacon
+	SensorGovee
+)
+
+func (f SensorFormat) String() string {
+	switch f {
+	case SensorRuuvi:
+		return "Ruuvi"
+	case SensorBTHome:
+		return "BTHome"
+	case SensorMiBeacon:
+		return "MiBeacon"
+	case SensorGovee:
+		return "Govee"
+	default:
+		return ""
+	}
+}
+
+// MarshalText encodes the format by name for JSON output.
+func (f SensorFormat) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+// UnmarshalText parses a format name written by MarshalText.
+func (f *SensorFormat) UnmarshalText(text []byte) error {
+	for _, c := range []SensorFormat{0, SensorRuuvi, SensorBTHome, SensorMiBeacon, SensorGovee} {
+		if c.String() == string(text) {
+			*f = c
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown sensor format %q", text)
+}
+
+// Quantity is a kind of sensor reading.
+type Quantity int
+
+const (
+	QuantityTemperature  Quantity = iota + 1 // °C
+	QuantityHumidity                         // % relative humidity
+	QuantityPressure                         // hPa
+	QuantityBattery                          // % charge
+	QuantityVoltage                          // V, battery or supply
+	QuantityAcceleration                     // g, magnitude
+	QuantityMovements                        // Movement counter
+	QuantityMotion                           // 1 while motion is detected, else 0
+	QuantityIlluminance                      // lux
+)
+
+// Quantities lists every quantity in display order.
+var Quantities = []Quantity{
+	QuantityTemperature, QuantityHumidity, QuantityPressure, QuantityIlluminance,
+	QuantityAcceleration, QuantityMovements, QuantityMotion, QuantityBattery, QuantityVoltage,
+}
+
+var quantityInfo = map[Quantity]struct{ key, label, unit, format string }{
+	QuantityTemperature:  {"temperature_c", "Temp", "°C", "%.2f"},
+	QuantityHumidity:     {"humidity_pct", "Humidity", "%", "%.1f"},
+	QuantityPressure:     {"pressure_hpa", "Pressure", "hPa", "%.1f"},
+	QuantityBattery:      {"battery_pct", "Battery", "%", "%.0f"},
+	QuantityVoltage:      {"voltage_v", "Voltage", "V", "%.3f"},
+	QuantityAcceleration: {"acceleration_g", "Accel", "g", "%.2f"},
+	QuantityMovements:    {"movements", "Movements", "", "%.0f"},
+	QuantityMotion:       {"motion", "Motion", "", "%.0f"},
+	QuantityIlluminance:  {"illuminance_lux", "Light", "lux", "%.0f"},
+}
+
+// String returns a short label, e.g. "Temp".
+func (q Quantity) String() string { return quantityInfo[q].label }
+
+// Format renders a value of the quantity with its unit.
+func (q Quantity) Format(v float64) string {
+	if q == QuantityMotion {
+		if v != 0 {
+			return "detected"
+		}
+		return "none"
+	}
+	s := fmt.Sprintf(quantityInfo[q].format, v)
+	if u := quantityInfo[q].unit; u != "" {
+		s += " " + u
+	}
+	return s
+}
+
+// MarshalText encodes the quantity as a JSON key including its unit.
+func (q Quantity) MarshalText() ([]byte, error) {
+	if _, ok := quantityInfo[q]; !ok {
+		return nil, fmt.Errorf("unknown quantity %d", int(q))
+	}
+	return []byte(quantityInfo[q].key), nil
+}
+
+// UnmarshalText parses a key written by MarshalText.
+func (q *Quantity) UnmarshalText(text []byte) error {
+	for c, info := range quantityInfo {
+		if info.key == string(text) {
+			*q = c
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown quantity %q", text)
+}
+
+// Sensor holds the readings of an environmental sensor beacon. Sensors
+// may spread their readings over several frames, so a Sensor accumulates
+// them via Merge.
+type Sensor struct {
+	Format   SensorFormat         `json:"format"`
+	Readings map[Quantity]float64 `json:"readings"`
+	Updated  time.Time            `json:"-"` // When the store last merged a frame
+}
+
+// Label returns a short identifier suitable as a fallback device name.
+func (s *Sensor) Label() string {
+	return s.Format.String() + " sensor"
+}
+
+// Merge copies the readings of a later frame into s.
+func (s *Sensor) Merge(other *Sensor) {
+	if other == nil {
+		return
+	}
+	if s.Format != other.Format || s.Readings == nil {
+		s.Format = other.Format
+		s.Readings = make(map[Quantity]float64, len(other.Readings))
+	}
+	for q, v := range other.Readings {
+		s.Readings[q] = v
+	}
+}
+
+// clone returns a deep copy of s.
+func (s *Sensor) clone() *Sensor {
+	cp := *s
+	cp.Readings = make(map[Quantity]float64, len(s.Readings))
+	for q, v := range s.Readings {
+		cp.Readings[q] = v
+	}
+	return &cp
+}
+
+// set records a reading, creating the map on first use.
+func (s *Sensor) set(q Quantity, v float64) {
+	if s.Readings == nil {
+		s.Readings = make(map[Quantity]float64)
+	}
+	s.Readings[q] = v
+}
+
+// Company IDs and constants of the manufacturer data sensor formats.
+const (
+	companyRuuvi       = 0x0499
+	ruuviRAWv2         = 0x05
+	ruuviRAWv2Length   = 24
+	ruuviBatteryBaseMV = 1600
+	companyGovee       = 0xEC88 // Not a SIG assignment; Govee thermometers use it
+	goveePackedLength  = 6      // H5072/H5075/H5101/H5102/H5177: packed temperature and humidity
+	goveeSplitLength   = 7      // H5074: separate little-endian fields
+)
+
+// ParseRuuvi decodes RuuviTag data format 5 (RAWv2) manufacturer data.
+// Returns nil for other formats.
+func ParseRuuvi(companyID uint16, data []byte) *Sensor {
+	// format(1) temp(2) humidity(2) pressure(2) accel x,y,z(6) power(2)
+	// movements(1) sequence(2) mac(6), big-endian
+	if companyID != companyRuuvi || len(data) < ruuviRAWv2Length || data[0] != ruuviRAWv2 {
+		return nil
+	}
+	s := &Sensor{Format: SensorRuuvi}
+	if t := int16(binary.BigEndian.Uint16(data[1:3])); t != math.MinInt16 {
+		s.set(QuantityTemperature, float64(t)*0.005)
+	}
+	if h := binary.BigEndian.Uint16(data[3:5]); h != math.MaxUint16 {
+		s.set(QuantityHumidity, float64(h)*0.0025)
+	}
+	if p := binary.BigEndian.Uint16(data[5:7]); p != math.MaxUint16 {
+		s.set(QuantityPressure, (float64(p)+50000)/100)
+	}
+	var sq float64
+	valid := true
+	for i := 7; i < 13; i += 2 {
+		a := int16(binary.BigEndian.Uint16(data[i : i+2]))
+		if a == math.MinInt16 {
+			valid = false
+			break
+		}
+		sq += float64(a) * float64(a)
+	}
+	if valid {
+		s.set(QuantityAcceleration, math.Sqrt(sq)/1000)
+	}
+	if power := binary.BigEndian.Uint16(data[13:15]); power>>5 != 0x7FF {
+		s.set(QuantityVoltage, float64(power>>5+ruuviBatteryBaseMV)/1000)
+	}
+	if m := data[15]; m != math.MaxUint8 {
+		s.set(QuantityMovements, float64(m))
+	}
+	return s
+}
+
+// ParseGovee decodes the manufacturer data of Govee thermo-hygrometers.
+func ParseGovee(companyID uint16, data []byte) *Sensor {
+	if companyID != companyGovee {
+		return nil
+	}
+	s := &Sensor{Format: SensorGovee}
+	switch len(data) {
+	case goveePackedLength:
+		// reserved(1) temp*10000+humidity*10 (3, sign in the top bit) battery(1) reserved(1)
+		v := uint32(data[1])<<16 | uint32(data[2])<<8 | uint32(data[3])
+		sign := 1.0
+		if v&0x800000 != 0 {
+			v &^= 0x800000
+			sign = -1
+		}
+		s.set(QuantityTemperature, sign*float64(v/1000)/10)
+		s.set(QuantityHumidity, float64(v%1000)/10)
+		s.set(QuantityBattery, float64(data[4]))
+	case goveeSplitLength:
+		// reserved(1) temp(2) humidity(2) battery(1) reserved(1), little-endian
+		s.set(QuantityTemperature, float64(int16(binary.LittleEndian.Uint16(data[1:3])))/100)
+		s.set(QuantityHumidity, float64(binary.LittleEndian.Uint16(data[3:5]))/100)
+		s.set(QuantityBattery, float64(data[5]))
+	default:
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,194 @@
+package bluetooth
+
+import (
+	"encoding/hex"
+	"math"
+	"strings"
+	"testing"
+)
+
+// unhex decodes a hex string, ignoring spaces.
+func unhex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(strings.ReplaceAll(s, " ", ""))
+	if err != nil {
+		t.Fatalf("bad test vector %q: %v", s, err)
+	}
+	return b
+}
+
+// checkReadings compares the readings of s with want, to within 1e-6.
+// A nil want expects s to be nil.
+func checkReadings(t *testing.T, s *Sensor, format SensorFormat, want map[Quantity]float64) {
+	t.Helper()
+	if want == nil {
+		if s != nil {
+			t.Errorf("decoded %v, want nil", s.Readings)
+		}
+		return
+	}
+	if s == nil {
+		t.Fatal("decoded nil")
+	}
+	if s.Format != format {
+		t.Errorf("format = %v, want %v", s.Format, format)
+	}
+	if len(s.Readings) != len(want) {
+		t.Errorf("readings = %v, want %v", s.Readings, want)
+	}
+	for q, w := range want {
+		if got, ok := s.Readings[q]; !ok || math.Abs(got-w) > 1e-6 {
+			t.Errorf("%v = %v (present %v), want %v", q, got, ok, w)
+		}
+	}
+}
+
+func TestParseRuuvi(t *testing.T) {
+	// Test vectors of the RuuviTag data format 5 specification
+	tests := []struct {
+		name string
+		data string
+		want map[Quantity]float64
+	}{
+		{"valid", "0512FC5394C37C0004FFFC040CAC364200CDCBB8334C884F", map[Quantity]float64{
+			QuantityTemperature:  24.3,
+			QuantityHumidity:     53.49,
+			QuantityPressure:     1000.44,
+			QuantityAcceleration: math.Sqrt(4*4+4*4+1036*1036) / 1000,
+			QuantityVoltage:      2.977,
+			QuantityMovements:    66,
+		}},
+		{"maximum", "057FFFFFFEFFFE7FFF7FFF7FFFFFDEFEFFFECBB8334C884F", map[Quantity]float64{
+			QuantityTemperature:  163.835,
+			QuantityHumidity:     163.835,
+			QuantityPressure:     1155.34,
+			QuantityAcceleration: math.Sqrt(3) * 32.767,
+			QuantityVoltage:      3.646,
+			QuantityMovements:    254,
+		}},
+		{"minimum", "058001000000008001800180010000000000CBB8334C884F", map[Quantity]float64{
+			QuantityTemperature:  -163.835,
+			QuantityHumidity:     0,
+			QuantityPressure:     500,
+			QuantityAcceleration: math.Sqrt(3) * 32.767,
+			QuantityVoltage:      1.6,
+			QuantityMovements:    0,
+		}},
+		{"invalid values", "058000FFFFFFFF800080008000FFFFFFFFFFFFFFFFFFFFFF", map[Quantity]float64{}},
+		{"format 3", "03291A1ECE1EFC18F94202CA0B53", nil},
+		{"truncated", "0512FC5394C37C0004FFFC040CAC364200CDCBB8334C88", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkReadings(t, ParseRuuvi(companyRuuvi, unhex(t, tt.data)), SensorRuuvi, tt.want)
+		})
+	}
+
+	if s := ParseRuuvi(0x004C, unhex(t, tests[0].data)); s != nil {
+		t.Error("decoded a frame of another company")
+	}
+}
+
+func TestParseBTHome(t *testing.T) {
+	// Object examples of the BTHome v2 format specification, after the
+	// device information byte 0x40 (v2, unencrypted)
+	tests := []struct {
+		name string
+		data string
+		want map[Quantity]float64
+	}{
+		{"temperature", "40 02CA09", map[Quantity]float64{QuantityTemperature: 25.06}},
+		{"humidity", "40 03BF13", map[Quantity]float64{QuantityHumidity: 50.55}},
+		{"battery", "40 0161", map[Quantity]float64{QuantityBattery: 97}},
+		{"pressure", "40 04138A01", map[Quantity]float64{QuantityPressure: 1008.83}},
+		{"illuminance", "40 05138A14", map[Quantity]float64{QuantityIlluminance: 13460.67}},
+		{"voltage mV", "40 0C020C", map[Quantity]float64{QuantityVoltage: 3.074}},
+		{"temperature 0.1", "40 451101", map[Quantity]float64{QuantityTemperature: 27.3}},
+		{"temperature sint8", "40 57EA", map[Quantity]float64{QuantityTemperature: -22}},
+		{"temperature 0.35", "40 58FF", map[Quantity]float64{QuantityTemperature: -0.35}},
+		{"acceleration", "40 518756", map[Quantity]float64{QuantityAcceleration: 22.151 / standardGravity}},
+		{"motion", "40 2101", map[Quantity]float64{QuantityMotion: 1}},
+		{"several objects", "40 0009 0164 02CA09 03BF13", map[Quantity]float64{
+			QuantityBattery: 100, QuantityTemperature: 25.06, QuantityHumidity: 50.55,
+		}},
+		{"text", "40 5303414243 02CA09", map[Quantity]float64{QuantityTemperature: 25.06}},
+		{"stops at unknown object", "40 02CA09 F0 03BF13", map[Quantity]float64{QuantityTemperature: 25.06}},
+		{"encrypted", "41 02CA09", nil},
+		{"version 1", "20 02CA09", nil},
+		{"no readings", "40 0009", nil},
+		{"empty", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkReadings(t, ParseBTHome(unhex(t, tt.data)), SensorBTHome, tt.want)
+		})
+	}
+}
+
+func TestParseMiBeacon(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want map[Quantity]float64
+	}{
+		// LYWSDCGQ: frame control 0x2050 (MAC and object present), product
+		// 0x01AA, counter, reversed MAC, then object 0x100D
+		{"temperature and humidity", "5020 AA01 DA 219335342D58 0D1004 FE004802", map[Quantity]float64{
+			QuantityTemperature: 25.4, QuantityHumidity: 58.4,
+		}},
+		{"temperature", "5020 AA01 DA 219335342D58 041002 E1FF", map[Quantity]float64{QuantityTemperature: -3.1}},
+		{"humidity", "5020 AA01 DA 219335342D58 061002 4802", map[Quantity]float64{QuantityHumidity: 58.4}},
+		{"battery", "5020 AA01 DA 219335342D58 0A1001 5D", map[Quantity]float64{QuantityBattery: 93}},
+		{"motion with light", "5020 AA01 DA 219335342D58 0F0003 640000", map[Quantity]float64{
+			QuantityMotion: 1, QuantityIlluminance: 100,
+		}},
+		{"no motion", "5020 AA01 DA 219335342D58 171004 78000000", map[Quantity]float64{QuantityMotion: 0}},
+		// Capability byte with the I/O capability flag: two more bytes
+		{"capability", "7020 AA01 DA 219335342D58 20 0000 0A1001 5D", map[Quantity]float64{QuantityBattery: 93}},
+		{"without MAC", "4020 AA01 DA 0A1001 5D", map[Quantity]float64{QuantityBattery: 93}},
+		{"encrypted", "5830 AA01 DA 219335342D58 0A1001 5D", nil},
+		{"no object", "1020 AA01 DA 219335342D58", nil},
+		{"truncated MAC", "5020 AA01 DA 2193", nil},
+		{"unknown object", "5020 AA01 DA 219335342D58 FFFF01 00", nil},
+		{"short", "5020", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkReadings(t, ParseMiBeacon(unhex(t, tt.data)), SensorMiBeacon, tt.want)
+		})
+	}
+}
+
+func TestParseGovee(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want map[Quantity]float64
+	}{
+		// H5075: 213894 packs 21.3 °C and 89.4 %
+		{"packed", "00 034386 64 00", map[Quantity]float64{
+			QuantityTemperature: 21.3, QuantityHumidity: 89.4, QuantityBattery: 100,
+		}},
+		// Top bit set: 10505 packs -1.0 °C and 50.5 %
+		{"packed negative", "00 802909 37 00", map[Quantity]float64{
+			QuantityTemperature: -1.0, QuantityHumidity: 50.5, QuantityBattery: 55,
+		}},
+		// H5074: 20.04 °C, 46.01 %
+		{"split", "00 D407 F911 64 02", map[Quantity]float64{
+			QuantityTemperature: 20.04, QuantityHumidity: 46.01, QuantityBattery: 100,
+		}},
+		{"split negative", "00 38FF F911 64 02", map[Quantity]float64{
+			QuantityTemperature: -2.00, QuantityHumidity: 46.01, QuantityBattery: 100,
+		}},
+		{"other length", "00 034386 64", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkReadings(t, ParseGovee(companyGovee, unhex(t, tt.data)), SensorGovee, tt.want)
+		})
+	}
+
+	if s := ParseGovee(companyRuuvi, unhex(t, "00 034386 64 00")); s != nil {
+		t.Error("decoded a frame of another company")
+	}
+}
@@ -0,0 +1,36 @@
+package bluetooth
+
+import "strconv"
+
+// LookupService returns the name of a 16-bit service UUID from the SIG
+// assigned numbers, covering GATT services and member UUIDs.
+// See: https://www.bluetooth.com/specifications/assigned-numbers/
+func LookupService(uuid uint16) string {
+	return serviceNames[uuid]
+}
+
+// lookupServiceUUID looks up a UUID in the short form used by ServiceUUID.
+func lookupServiceUUID(uuid string) string {
+	if len(uuid) != 4 {
+		return ""
+	}
+	v, err := strconv.ParseUint(uuid, 16, 16)
+	if err != nil {
+		return ""
+	}
+	return LookupService(uint16(v))
+}
+
+// LookupAppearance returns the name of a GAP Appearance value: the
+// subcategory if known, else the category.
+func LookupAppearance(appearance uint16) string {
+	if name, ok := appearanceNames[appearance]; ok {
+		return name
+	}
+	return appearanceNames[appearance&^0x3F]
+}
+
+// LookupADType returns the name of a GAP AD type, e.g. "Flags".
+func LookupADType(typ byte) string {
+	return adTypeNames[typ]
+}
@@ -0,0 +1,126 @@
+package bluetooth
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Session files are JSON Lines: a SessionHeader followed by one
+// SessionEntry per recorded DeviceDiscoveredMsg.
+const (
+	sessionFormat  = "ble-radar-session"
+	sessionVersion = 1
+)
+
+// SessionHeader is the first line of a session file.
+type SessionHeader struct {
+	Format  string    `json:"format"`
+	Version int       `json:"version"`
+	Started time.Time `json:"started"`
+}
+
+// SessionEntry is a single recorded discovery.
+type SessionEntry struct {
+	Time time.Time           `json:"time"`
+	Msg  DeviceDiscoveredMsg `json:"msg"`
+}
+
+// Recorder writes every DeviceDiscoveredMsg it sees to a session file.
+// It is safe for concurrent use.
+type Recorder struct {
+	mu  sync.Mutex
+	w   *bufio.Writer
+	enc *json.Encoder
+	err error
+}
+
+// NewRecorder writes a session header to w and returns a recorder for it.
+func NewRecorder(w io.Writer) (*Recorder, error) {
+	bw := bufio.NewWriter(w)
+	r := &Recorder{w: bw, enc: json.NewEncoder(bw)}
+	hdr := SessionHeader{Format: sessionFormat, Version: sessionVersion, Started: time.Now()}
+	if err := r.enc.Encode(hdr); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Record appends msg to the session, at its recorded time when replayed.
+// Errors are sticky and reported by Close.
+func (r *Recorder) Record(msg DeviceDiscoveredMsg) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return
+	}
+	t := msg.At
+	if t.IsZero() {
+		t = time.Now()
+	}
+	r.err = r.enc.Encode(SessionEntry{Time: t, Msg: msg})
+}
+
+// Wrap returns a Sink that records discoveries before forwarding every
+// message to next.
+func (r *Recorder) Wrap(next Sink) Sink {
+	return recordingSink{rec: r, next: next}
+}
+
+// Close flushes buffered entries. It does not close the underlying writer.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.w.Flush(); err != nil && r.err == nil {
+		r.err = err
+	}
+	return r.err
+}
+
+type recordingSink struct {
+	rec  *Recorder
+	next Sink
+}
+
+func (s recordingSink) Send(msg tea.Msg) {
+	if d, ok := msg.(DeviceDiscoveredMsg); ok {
+		s.rec.Record(d)
+	}
+	s.next.Send(msg)
+}
+
+// ReadSession parses a session file produced by Recorder.
+func ReadSession(r io.Reader) ([]SessionEntry, error) {
+	dec := json.NewDecoder(r)
+
+	var hdr SessionHeader
+	if err := dec.Decode(&hdr); err != nil {
+		return nil, fmt.Errorf("reading session header: %w", err)
+	}
+	if hdr.Format != sessionFormat {
+		return nil, fmt.Errorf("not a session file (format %q)", hdr.Format)
+	}
+	if hdr.Version > sessionVersion {
+		return nil, fmt.Errorf("unsupported session version %d", hdr.Version)
+	}
+
+	var entries []SessionEntry
+	for {
+		var e SessionEntry
+		err := dec.Decode(&e)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			// A truncated last line means the capture was interrupted;
+			// keep everything before it.
+			return entries, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading session entry %d: %w", len(entries)+1, err)
+		}
+		entries = append(entries, e)
+	}
+}
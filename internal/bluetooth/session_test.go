@@ -0,0 +1,118 @@
+package bluetooth
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// recordSession records msgs with a Recorder and returns the file contents.
+func recordSession(t *testing.T, msgs ...DeviceDiscoveredMsg) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	rec, err := NewRecorder(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range msgs {
+		rec.Record(m)
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestSessionRoundTrip(t *testing.T) {
+	t0 := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	msgs := []DeviceDiscoveredMsg{
+		{
+			MAC: "4A:11:22:33:44:55", Name: "tag", RSSI: -61, Type: DeviceTypeBLE,
+			AddressType: AddressResolvable, Fingerprint: "F1", Adapter: "hci0",
+			TxPower: -8, HasTxPower: true,
+			Beacon: &Beacon{Format: BeaconIBeacon, UUID: "FDA50693-A4E2-4FB1-AFCF-C6EB07647825", Major: 10, Minor: 7, MeasuredPower: -59, HasMeasuredPower: true},
+			At:     t0,
+		},
+		{MAC: "00:11:22:33:44:55", RSSI: -70, Type: DeviceTypeClassic, ClassOfDevice: 0x240418, At: t0.Add(1500 * time.Millisecond)},
+		{MAC: "AA:BB:CC:DD:EE:FF", Name: "wifi", RSSI: -50, Type: DeviceTypeWiFi, Frequency: 2437, Channel: 6, At: t0.Add(2 * time.Second)},
+	}
+	data := recordSession(t, msgs...)
+
+	entries, err := ReadSession(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(msgs) {
+		t.Fatalf("%d entries, want %d", len(entries), len(msgs))
+	}
+	for i, e := range entries {
+		want := msgs[i]
+		if !e.Time.Equal(want.At) {
+			t.Errorf("entry %d time = %v, want %v", i, e.Time, want.At)
+		}
+		// At is carried by the entry time, not the message.
+		want.At = time.Time{}
+		if !reflect.DeepEqual(e.Msg, want) {
+			t.Errorf("entry %d = %+v, want %+v", i, e.Msg, want)
+		}
+	}
+
+	// Live messages are stamped with the wall clock.
+	before := time.Now()
+	entries, err = ReadSession(bytes.NewReader(recordSession(t, DeviceDiscoveredMsg{MAC: "00:11:22:33:44:55"})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Time.Before(before) || entries[0].Time.After(time.Now()) {
+		t.Errorf("live entry = %+v, want stamped now", entries)
+	}
+}
+
+func TestReadSession(t *testing.T) {
+	t0 := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	full := string(recordSession(t,
+		DeviceDiscoveredMsg{MAC: "00:00:00:00:00:01", At: t0},
+		DeviceDiscoveredMsg{MAC: "00:00:00:00:00:02", At: t0.Add(time.Second)},
+	))
+	lines := strings.SplitAfter(full, "\n")
+	header, first, second := lines[0], lines[1], lines[2]
+
+	tests := []struct {
+		name    string
+		data    string
+		want    int    // Entries read
+		wantErr string // Substring of the error
+	}{
+		{name: "complete", data: full, want: 2},
+		{name: "header only", data: header, want: 0},
+		// An interrupted capture ends mid-line: io.ErrUnexpectedEOF
+		{name: "truncated tail", data: header + first + second[:len(second)/2], want: 1},
+		{name: "truncated after header", data: header + first[:10], want: 0},
+		{name: "empty", data: "", wantErr: "reading session header"},
+		{name: "not a session", data: `{"format":"other","version":1}` + "\n", wantErr: `not a session file (format "other")`},
+		{name: "newer version", data: `{"format":"ble-radar-session","version":2}` + "\n", wantErr: "unsupported session version 2"},
+		{name: "corrupt entry", data: header + first + "{\"time\": 12}\n" + second, wantErr: "reading session entry 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := ReadSession(strings.NewReader(tt.data))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ReadSession error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != tt.want {
+				t.Fatalf("%d entries, want %d", len(entries), tt.want)
+			}
+			if tt.want > 0 && entries[0].Msg.MAC != "00:00:00:00:00:01" {
+				t.Errorf("first entry = %+v", entries[0])
+			}
+		})
+	}
+}
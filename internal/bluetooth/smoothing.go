@@ -0,0 +1,108 @@
+package bluetooth
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Smoother filters the RSSI samples of a single device. Each device in the
+// store gets its own instance.
+type Smoother interface {
+	// Update feeds a raw sample and returns the filtered value.
+	Update(rssi float64) float64
+}
+
+// SmootherFactory creates a fresh Smoother for a newly seen device.
+type SmootherFactory func() Smoother
+
+// SmoothingParams holds the tunables for every smoothing method.
+type SmoothingParams struct {
+	Alpha   float64 // EMA weight of the new sample (0, 1]
+	KalmanQ float64 // Kalman process noise: how fast the true RSSI may drift
+	KalmanR float64 // Kalman measurement noise: variance of a single reading
+	MedianN int     // Median window size in samples
+}
+
+// SmoothingMethods lists the names accepted by NewSmootherFactory.
+var SmoothingMethods = []string{"ema", "kalman", "median"}
+
+// NewSmootherFactory returns the factory for the named method.
+func NewSmootherFactory(method string, p SmoothingParams) (SmootherFactory, error) {
+	switch strings.ToLower(method) {
+	case "", "ema":
+		if p.Alpha <= 0 || p.Alpha > 1 {
+			return nil, fmt.Errorf("ema alpha must be in (0, 1], got %g", p.Alpha)
+		}
+		return func() Smoother { return &emaSmoother{alpha: p.Alpha} }, nil
+	case "kalman":
+		if p.KalmanQ <= 0 || p.KalmanR <= 0 {
+			return nil, fmt.Errorf("kalman noise values must be positive (q=%g, r=%g)", p.KalmanQ, p.KalmanR)
+		}
+		return func() Smoother { return &kalmanSmoother{q: p.KalmanQ, r: p.KalmanR} }, nil
+	case "median":
+		if p.MedianN < 1 {
+			return nil, fmt.Errorf("median window must be at least 1, got %d", p.MedianN)
+		}
+		return func() Smoother { return &medianSmoother{buf: make([]float64, 0, p.MedianN), n: p.MedianN} }, nil
+	}
+	return nil, fmt.Errorf("unknown smoothing method %q (want %s)", method, strings.Join(SmoothingMethods, ", "))
+}
+
+// emaSmoother is an exponential moving average.
+type emaSmoother struct {
+	alpha  float64
+	value  float64
+	primed bool
+}
+
+func (s *emaSmoother) Update(rssi float64) float64 {
+	if !s.primed {
+		s.value, s.primed = rssi, true
+		return s.value
+	}
+	s.value = s.value*(1-s.alpha) + rssi*s.alpha
+	return s.value
+}
+
+// kalmanSmoother is a 1-D Kalman filter with a constant-value model.
+type kalmanSmoother struct {
+	q, r   float64 // Process and measurement noise
+	x, p   float64 // Estimate and its variance
+	primed bool
+}
+
+func (s *kalmanSmoother) Update(rssi float64) float64 {
+	if !s.primed {
+		s.x, s.p, s.primed = rssi, s.r, true
+		return s.x
+	}
+	s.p += s.q
+	k := s.p / (s.p + s.r)
+	s.x += k * (rssi - s.x)
+	s.p *= 1 - k
+	return s.x
+}
+
+// medianSmoother returns the median of the last n samples.
+type medianSmoother struct {
+	buf []float64
+	n   int
+	pos int
+}
+
+func (s *medianSmoother) Update(rssi float64) float64 {
+	if len(s.buf) < s.n {
+		s.buf = append(s.buf, rssi)
+	} else {
+		s.buf[s.pos] = rssi
+		s.pos = (s.pos + 1) % s.n
+	}
+	sorted := append([]float64(nil), s.buf...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,146 @@
+package bluetooth
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+var testSmoothing = SmoothingParams{Alpha: 0.3, KalmanQ: 0.01, KalmanR: 4, MedianN: 5}
+
+// feed passes samples through s and returns every output.
+func feed(s Smoother, samples ...float64) []float64 {
+	out := make([]float64, len(samples))
+	for i, v := range samples {
+		out[i] = s.Update(v)
+	}
+	return out
+}
+
+func TestSmoothers(t *testing.T) {
+	for _, method := range SmoothingMethods {
+		t.Run(method, func(t *testing.T) {
+			f, err := NewSmootherFactory(method, testSmoothing)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The first sample passes through unchanged.
+			if got := f().Update(-73); got != -73 {
+				t.Errorf("first sample = %v, want -73", got)
+			}
+
+			// A step to a constant converges to it.
+			s := f()
+			s.Update(-90)
+			var last float64
+			for i := 0; i < 200; i++ {
+				last = s.Update(-60)
+			}
+			if math.Abs(last+60) > 0.1 {
+				t.Errorf("after 200 samples of -60 = %v", last)
+			}
+
+			// Each device gets its own state.
+			if got := f().Update(-40); got != -40 {
+				t.Errorf("fresh smoother first sample = %v, want -40", got)
+			}
+		})
+	}
+}
+
+func TestEMASmoother(t *testing.T) {
+	f, err := NewSmootherFactory("EMA", SmoothingParams{Alpha: 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := feed(f(), -60, -70, -80, -80)
+	want := []float64{-60, -65, -72.5, -76.25}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-9 {
+			t.Errorf("outputs = %v, want %v", got, want)
+			break
+		}
+	}
+
+	// Alpha 1 follows the input.
+	f, _ = NewSmootherFactory("ema", SmoothingParams{Alpha: 1})
+	if got := feed(f(), -60, -70); got[1] != -70 {
+		t.Errorf("alpha 1: %v, want -70", got[1])
+	}
+}
+
+func TestKalmanSmoother(t *testing.T) {
+	f, err := NewSmootherFactory("kalman", SmoothingParams{KalmanQ: 1, KalmanR: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := f()
+	s.Update(-60)
+	// Variance r after the first sample, r+q before the second: gain 5/9.
+	if got, want := s.Update(-69), -60-9*5.0/9; math.Abs(got-want) > 1e-9 {
+		t.Errorf("second sample = %v, want %v", got, want)
+	}
+
+	// Noisy readings around -70 settle closer than the noise.
+	s = f()
+	noise := []float64{4, -3, 5, -6, 2, -1, 3, -4, 6, -5}
+	var sum float64
+	for i := 0; i < 100; i++ {
+		v := s.Update(-70 + noise[i%len(noise)])
+		if i >= 50 {
+			sum += math.Abs(v + 70)
+		}
+	}
+	if mean := sum / 50; mean > 2 {
+		t.Errorf("mean error %v dB, want under 2", mean)
+	}
+}
+
+func TestMedianSmoother(t *testing.T) {
+	f, err := NewSmootherFactory("median", SmoothingParams{MedianN: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		samples []float64
+		want    float64 // Last output
+	}{
+		{"even count averages the middle", []float64{-60, -70}, -65},
+		{"single spike rejected", []float64{-60, -61, -20, -62, -59}, -60},
+		{"two spikes rejected", []float64{-60, -20, -61, -25, -62}, -60},
+		{"dropout rejected", []float64{-60, -61, -99, -59, -60}, -60},
+		// The oldest samples leave the window.
+		{"window slides", []float64{-20, -20, -20, -60, -61, -62, -60, -59}, -60},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := feed(f(), tt.samples...)
+			if last := got[len(got)-1]; last != tt.want {
+				t.Errorf("outputs %v, want last %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewSmootherFactoryErrors(t *testing.T) {
+	tests := []struct {
+		method  string
+		params  SmoothingParams
+		wantErr string
+	}{
+		{"ema", SmoothingParams{Alpha: 0}, "ema alpha must be in (0, 1]"},
+		{"", SmoothingParams{Alpha: 1.5}, "ema alpha must be in (0, 1]"},
+		{"kalman", SmoothingParams{KalmanQ: 0, KalmanR: 4}, "kalman noise values must be positive"},
+		{"kalman", SmoothingParams{KalmanQ: 1, KalmanR: -1}, "kalman noise values must be positive"},
+		{"median", SmoothingParams{MedianN: 0}, "median window must be at least 1"},
+		{"mean", testSmoothing, `unknown smoothing method "mean"`},
+	}
+	for _, tt := range tests {
+		f, err := NewSmootherFactory(tt.method, tt.params)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) || f != nil {
+			t.Errorf("NewSmootherFactory(%q, %+v) error = %v, want %q", tt.method, tt.params, err, tt.wantErr)
+		}
+	}
+}
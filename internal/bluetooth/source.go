@@ -0,0 +1,156 @@
+package bluetooth
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Sink receives messages produced by a Scanner. *tea.Program satisfies it,
+// so scanners can feed the UI directly or any other consumer.
+type Sink interface {
+	Send(msg tea.Msg)
+}
+
+// ContextSink delivers on ch until ctx is done and drops messages after
+// that, so scanners never block on a reader that has stopped.
+func ContextSink(ctx context.Context, ch chan<- tea.Msg) Sink {
+	return contextSink{ctx: ctx, ch: ch}
+}
+
+type contextSink struct {
+	ctx context.Context
+	ch  chan<- tea.Msg
+}
+
+// Send implements Sink.
+func (s contextSink) Send(msg tea.Msg) {
+	select {
+	case s.ch <- msg:
+	case <-s.ctx.Done():
+	}
+}
+
+// Capability describes what kind of data a scanner can produce.
+type Capability uint8
+
+const (
+	CapBLE       Capability = 1 << iota // Bluetooth Low Energy advertisements
+	CapClassic                          // Classic Bluetooth inquiry results
+	CapWiFi                             // WiFi access points
+	CapRSSI                             // Real (measured) signal strength
+	CapSynthetic                        // Generated or replayed data, no hardware
+)
+
+// Has reports whether all bits in other are set.
+func (c Capability) Has(other Capability) bool {
+	return c&other == other
+}
+
+func (c Capability) String() string {
+	var parts []string
+	names := []struct {
+		bit  Capability
+		name string
+	}{
+		{CapBLE, "ble"},
+		{CapClassic, "classic"},
+		{CapWiFi, "wifi"},
+		{CapRSSI, "rssi"},
+		{CapSynthetic, "synthetic"},
+	}
+	for _, n := range names {
+		if c.Has(n.bit) {
+			parts = append(parts, n.name)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// ScannerState is the lifecycle state of a scanner.
+type ScannerState int
+
+const (
+	StateStopped ScannerState = iota
+	StateRunning
+	StateFailed
+)
+
+func (s ScannerState) String() string {
+	switch s {
+	case StateRunning:
+		return "running"
+	case StateFailed:
+		return "failed"
+	default:
+		return "stopped"
+	}
+}
+
+// Status is a point-in-time health report for a scanner.
+type Status struct {
+	State      ScannerState
+	Err        error     // Last error, if any
+	Results    int       // Messages delivered since Start
+	LastResult time.Time // When the last message was delivered
+}
+
+// Scanner is a source of DeviceDiscoveredMsg values.
+//
+// Start must not block: scanners run in their own goroutines and deliver
+// results to sink until ctx is cancelled or Stop is called.
+type Scanner interface {
+	Name() string
+	Capabilities() Capability
+	Start(ctx context.Context, sink Sink) error
+	Stop()
+	Status() Status
+}
+
+// health tracks Status for a scanner. Embed it and call its methods from
+// the scanner goroutine; Status() is safe to call from anywhere.
+type health struct {
+	mu     sync.Mutex
+	status Status
+}
+
+func (h *health) set(state ScannerState, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.status.State = state
+	if err != nil {
+		h.status.Err = err
+	}
+}
+
+func (h *health) reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.status = Status{State: StateRunning}
+}
+
+func (h *health) observe() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.status.Results++
+	h.status.LastResult = time.Now()
+}
+
+// Status returns a copy of the current health report.
+func (h *health) Status() Status {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.status
+}
+
+// emit delivers msg to sink and records it in h.
+func (h *health) emit(sink Sink, msg tea.Msg) {
+	if sink == nil {
+		return
+	}
+	h.observe()
+	sink.Send(msg)
+}
@@ -1,38 +1,227 @@
 package bluetooth
 
 import (
+	"math"
 	"sort"
 	"sync"
 	"time"
-
-	"ble-radar.klederson.com/internal/config"
 )
 
 // DeviceStore is a thread-safe store for discovered devices.
 type DeviceStore struct {
-	mu      sync.RWMutex
-	devices map[string]*Device
+	mu        sync.RWMutex
+	devices   map[string]*Device
+	smoothers map[string]Smoother
+	newSmooth SmootherFactory
+
+	defaults   PathLoss            // Configured model, used without calibration
+	pathLoss   PathLoss            // Global model
+	calibrated bool                // pathLoss comes from a calibration profile
+	perDevice  map[string]PathLoss // Per-MAC calibration profiles
+
+	localizer Localizer          // Optional bearing estimation from several adapters
+	pinned    map[string]float64 // MAC -> bearing fixed by a fox hunt
+
+	aliases map[string]string // Rotated address -> MAC of the device it was linked to
+
+	identities *IdentityResolver // Optional IRKs of known devices
+	byIdentity map[string]string // Identity name -> MAC of its device
+
+	vendors VendorLookup // Optional OUI database
+
+	clock time.Time // Recorded time of the latest replayed message, zero when live
+}
+
+// VendorLookup maps a MAC address to the organization its OUI is assigned
+// to, or "" if unknown or not IEEE-assigned.
+type VendorLookup interface {
+	Lookup(mac string) string
 }
 
-// NewDeviceStore creates a new empty DeviceStore.
-func NewDeviceStore() *DeviceStore {
+// adapterStale is how long an adapter's reading stays eligible as the best
+// one after that adapter last heard the device.
+const adapterStale = 10 * time.Second
+
+// Limits of the heuristic that links a new private address to a device
+// that just stopped using another one with the same advertisement shape.
+const (
+	linkSettle      = 2 * time.Second  // New address heard this long before linking
+	linkWindow      = 15 * time.Second // Longest silence bridged by a rotation
+	linkOverlap     = time.Second      // Old address may be heard this long after the new one
+	linkMaxRSSIJump = 12.0             // dB; the device has not moved far
+	maxAddresses    = 32               // Linked addresses remembered per device
+)
+
+// NewDeviceStore creates a new empty DeviceStore estimating distance with
+// model and filtering RSSI with smoothing.
+func NewDeviceStore(model PathLoss, smoothing SmootherFactory) *DeviceStore {
 	return &DeviceStore{
-		devices: make(map[string]*Device),
+		devices:    make(map[string]*Device),
+		smoothers:  make(map[string]Smoother),
+		newSmooth:  smoothing,
+		defaults:   model,
+		pathLoss:   model,
+		aliases:    make(map[string]string),
+		byIdentity: make(map[string]string),
+	}
+}
+
+// SetCalibration installs calibrated path loss models. global may be nil to
+// keep the defaults; perDevice maps MAC addresses to their own profile.
+// Distances of tracked devices are recomputed immediately.
+func (s *DeviceStore) SetCalibration(global *PathLoss, perDevice map[string]PathLoss) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pathLoss, s.calibrated = s.defaults, false
+	if global != nil {
+		s.pathLoss, s.calibrated = *global, true
+	}
+	s.perDevice = perDevice
+	for _, d := range s.devices {
+		s.updateDistance(d)
+	}
+}
+
+// SetLocalizer enables position estimation for devices heard by several
+// adapters. Devices it cannot locate keep their MAC-derived angle.
+func (s *DeviceStore) SetLocalizer(l Localizer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.localizer = l
+	for _, d := range s.devices {
+		s.updateDistance(d)
+	}
+}
+
+// PinBearing fixes the angle of a device, e.g. after a fox hunt. It wins
+// over both the localizer and the MAC hash until UnpinBearing is called.
+func (s *DeviceStore) PinBearing(mac string, angle float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pinned == nil {
+		s.pinned = make(map[string]float64)
+	}
+	s.pinned[mac] = angle
+	if d, ok := s.devices[mac]; ok {
+		s.updateDistance(d)
+	}
+}
+
+// UnpinBearing returns a device to its estimated or hashed angle.
+func (s *DeviceStore) UnpinBearing(mac string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.pinned, mac)
+	if d, ok := s.devices[mac]; ok {
+		s.updateDistance(d)
+	}
+}
+
+// SetIdentities installs the IRKs of known devices. Resolvable private
+// addresses heard from then on are resolved, and every address of one
+// identity is folded into a single device. Tracked devices are resolved
+// immediately but not merged.
+func (s *DeviceStore) SetIdentities(r *IdentityResolver) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.identities = r
+	for mac, d := range s.devices {
+		if d.Identity != "" {
+			continue
+		}
+		if name, ok := r.Resolve(d.Address); ok {
+			d.Identity = name
+			if _, taken := s.byIdentity[name]; !taken {
+				s.byIdentity[name] = mac
+			}
+		}
+	}
+}
+
+// SetVendors installs the OUI database used to fill Device.Vendor, and
+// looks up the vendors of tracked devices immediately.
+func (s *DeviceStore) SetVendors(v VendorLookup) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.vendors = v
+	for _, d := range s.devices {
+		if d.Vendor == "" {
+			d.Vendor = s.vendor(d.Type, d.Address, d.AddressType)
+		}
 	}
 }
 
-// Upsert adds or updates a device. If the device already exists, RSSI is
-// smoothed using EMA and the angle is preserved for position consistency.
-func (s *DeviceStore) Upsert(mac, name string, rssi float64, dtype DeviceType, freq, channel int) {
+// vendor looks up the OUI vendor of an address. Random BLE addresses carry
+// no OUI, so only public ones are looked up. Callers hold s.mu.
+func (s *DeviceStore) vendor(typ DeviceType, addr string, at AddressType) string {
+	if s.vendors == nil || (typ == DeviceTypeBLE && at != AddressPublic) {
+		return ""
+	}
+	return s.vendors.Lookup(addr)
+}
+
+// SetSmoothing replaces the RSSI smoothing strategy. Devices already in the
+// store restart their filter from the next sample.
+func (s *DeviceStore) SetSmoothing(f SmootherFactory) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.newSmooth = f
+	s.smoothers = make(map[string]Smoother)
+}
 
-	now := time.Now()
+// Upsert adds or updates a device from a discovery message. RSSI is passed
+// through the device's smoother and the angle is preserved for position
+// consistency. Messages tagged with an adapter are smoothed per adapter and
+// the device reports the strongest recent reading. A new private address
+// may be merged into the device that rotated to it, see relink, or into
+// the known device whose IRK resolves it.
+func (s *DeviceStore) Upsert(msg DeviceDiscoveredMsg) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if msg.At.After(s.clock) {
+		s.clock = msg.At
+	}
+	now := s.now()
+	mac, name, rssi := msg.MAC, msg.Name, float64(msg.RSSI)
+	if key, ok := s.aliases[mac]; ok {
+		mac = key
+	}
+	identity := ""
+	if _, ok := s.devices[mac]; !ok && msg.AddressType == AddressResolvable {
+		identity, _ = s.identities.Resolve(msg.MAC)
+	}
+	if known, ok := s.devices[s.byIdentity[identity]]; ok && identity != "" {
+		s.addAddress(known, msg.MAC)
+		mac = known.MAC
+	}
+	freq, channel := msg.Frequency, msg.Channel
+
+	filtered := s.smooth(smootherKey(mac, msg.Adapter), rssi)
 
 	if existing, ok := s.devices[mac]; ok {
-		// Update existing device with EMA smoothing
-		existing.RSSI = existing.RSSI*(1-config.SmoothingAlpha) + rssi*config.SmoothingAlpha
-		existing.Distance = RSSIToDistance(existing.RSSI, config.MeasuredPower, config.PathLossExp)
+		if msg.Fingerprint != "" {
+			// Only advertisements tell the interval, not name lookups.
+			existing.updateInterval(msg.Adapter, now)
+			existing.Fingerprint = msg.Fingerprint
+		}
+		existing.Address = msg.MAC
+		if msg.AddressType != AddressUnknown {
+			existing.AddressType = msg.AddressType
+		}
+		if existing.Vendor == "" {
+			existing.Vendor = s.vendor(existing.Type, msg.MAC, existing.AddressType)
+		}
+		switch {
+		case msg.Adapter != "":
+			existing.setAdapterReading(msg.Adapter, filtered, rssi, now)
+		case existing.Adapters == nil:
+			existing.RSSI = filtered
+			existing.RawRSSI = rssi
+		}
+		// Adapter-less messages (e.g. name resolution) do not override
+		// per-adapter readings.
 		existing.LastSeen = now
 		if name != "" {
 			existing.Name = name
@@ -41,38 +230,414 @@ func (s *DeviceStore) Upsert(mac, name string, rssi float64, dtype DeviceType, f
 			existing.Frequency = freq
 			existing.Channel = channel
 		}
+		if msg.Beacon != nil {
+			if existing.Beacon == nil {
+				existing.Beacon = &Beacon{}
+			}
+			existing.Beacon.Merge(msg.Beacon)
+		}
+		if msg.Tracker != nil {
+			t := *msg.Tracker
+			existing.Tracker = &t
+		}
+		if msg.Continuity != nil {
+			if existing.Continuity == nil {
+				existing.Continuity = &Continuity{}
+			}
+			existing.Continuity.Merge(msg.Continuity)
+		}
+		if msg.Microsoft != nil {
+			m := *msg.Microsoft
+			existing.Microsoft = &m
+		}
+		if msg.FastPair != nil {
+			existing.FastPair = existing.FastPair.merge(msg.FastPair)
+		}
+		if msg.Platform != "" {
+			existing.Platform = msg.Platform
+		}
+		if msg.ClassOfDevice != 0 {
+			existing.ClassOfDevice = msg.ClassOfDevice
+		}
+		if msg.Sensor != nil {
+			if existing.Sensor == nil {
+				existing.Sensor = &Sensor{}
+			}
+			existing.Sensor.Merge(msg.Sensor)
+			existing.Sensor.Updated = now
+		}
+		if msg.Advertisement != nil {
+			if existing.Advertisement == nil {
+				existing.Advertisement = &Advertisement{}
+			}
+			existing.Advertisement.Merge(msg.Advertisement)
+		}
+		if msg.HasTxPower {
+			existing.TxPower, existing.HasTxPower = msg.TxPower, true
+		}
+		s.updateDistance(existing)
+		s.relink(existing, now)
 		return
 	}
 
 	// New device
 	angle := MacToAngle(mac)
-	dist := RSSIToDistance(rssi, config.MeasuredPower, config.PathLossExp)
 
-	s.devices[mac] = &Device{
-		MAC:       mac,
-		Name:      name,
-		RSSI:      rssi,
-		Type:      dtype,
-		LastSeen:  now,
-		Angle:     angle,
-		Distance:  dist,
-		Elevation: MacToElevation(mac),
-		Frequency: freq,
-		Channel:   channel,
+	d := &Device{
+		MAC:         mac,
+		Name:        name,
+		RSSI:        filtered,
+		RawRSSI:     rssi,
+		Type:        msg.Type,
+		Address:     mac,
+		Addresses:   []string{mac},
+		AddressType: msg.AddressType,
+		Vendor:      s.vendor(msg.Type, msg.MAC, msg.AddressType),
+		Fingerprint: msg.Fingerprint,
+		FirstSeen:   now,
+		LastSeen:    now,
+		Angle:       angle,
+		Elevation:   MacToElevation(mac),
+		Frequency:   freq,
+		Channel:     channel,
+		TxPower:     msg.TxPower,
+		HasTxPower:  msg.HasTxPower,
+		Identity:    identity,
+	}
+	if identity != "" {
+		s.byIdentity[identity] = mac
+	}
+	if msg.Beacon != nil {
+		b := *msg.Beacon
+		d.Beacon = &b
 	}
+	if msg.Tracker != nil {
+		t := *msg.Tracker
+		d.Tracker = &t
+	}
+	if msg.Continuity != nil {
+		d.Continuity = msg.Continuity.clone()
+	}
+	if msg.Microsoft != nil {
+		m := *msg.Microsoft
+		d.Microsoft = &m
+	}
+	if msg.FastPair != nil {
+		f := *msg.FastPair
+		d.FastPair = &f
+	}
+	d.Platform = msg.Platform
+	d.ClassOfDevice = msg.ClassOfDevice
+	if msg.Sensor != nil {
+		d.Sensor = msg.Sensor.clone()
+		d.Sensor.Updated = now
+	}
+	if msg.Advertisement != nil {
+		d.Advertisement = msg.Advertisement.clone()
+	}
+	if msg.Adapter != "" {
+		d.setAdapterReading(msg.Adapter, filtered, rssi, now)
+	}
+	s.updateDistance(d)
+	s.devices[mac] = d
 }
 
-// Evict removes devices not seen within the timeout duration.
-// Returns the number of evicted devices.
+// relink merges d into the device it most likely is after an address
+// rotation: one with the same advertisement fingerprint whose private
+// address fell silent just as d's appeared, at a similar signal level.
+// The check runs once, when d has been heard for linkSettle, so that an
+// address still in use shows up as such. Callers hold s.mu.
+func (s *DeviceStore) relink(d *Device, now time.Time) {
+	if d.linkChecked || now.Sub(d.FirstSeen) < linkSettle {
+		return
+	}
+	d.linkChecked = true
+	if !d.AddressType.Rotating() || d.Fingerprint == "" || len(d.Addresses) > 1 || d.Identity != "" {
+		return
+	}
+
+	var best *Device
+	bestScore := math.Inf(1)
+	for _, c := range s.devices {
+		if c == d || c.Fingerprint != d.Fingerprint || !c.AddressType.Rotating() || !c.FirstSeen.Before(d.FirstSeen) {
+			continue
+		}
+		// A known device's key would have resolved a resolvable address.
+		if c.Identity != "" && d.AddressType == AddressResolvable {
+			continue
+		}
+		// The old address stopped when the new one started.
+		gap := d.FirstSeen.Sub(c.LastSeen)
+		if gap < -linkOverlap || gap > linkWindow {
+			continue
+		}
+		jump := math.Abs(c.RawRSSI - d.RawRSSI)
+		if jump > linkMaxRSSIJump {
+			continue
+		}
+		if score := jump + math.Abs(gap.Seconds()); score < bestScore {
+			best, bestScore = c, score
+		}
+	}
+	if best != nil {
+		s.merge(best, d)
+	}
+}
+
+// merge folds device d, a later address of into, into it and removes d.
+// Callers hold s.mu.
+func (s *DeviceStore) merge(into, d *Device) {
+	delete(s.devices, d.MAC)
+	s.addAddress(into, d.MAC)
+	into.Address, into.AddressType = d.Address, d.AddressType
+	into.LastSeen = d.LastSeen
+	into.RSSI, into.RawRSSI = d.RSSI, d.RawRSSI
+	if d.Name != "" {
+		into.Name = d.Name
+	}
+	if d.Beacon != nil {
+		into.Beacon = d.Beacon
+	}
+	if d.Tracker != nil {
+		into.Tracker = d.Tracker
+	}
+	if d.Continuity != nil {
+		if into.Continuity == nil {
+			into.Continuity = &Continuity{}
+		}
+		into.Continuity.Merge(d.Continuity)
+	}
+	if d.Microsoft != nil {
+		into.Microsoft = d.Microsoft
+	}
+	if d.FastPair != nil {
+		into.FastPair = into.FastPair.merge(d.FastPair)
+	}
+	if d.Platform != "" {
+		into.Platform = d.Platform
+	}
+	if d.Vendor != "" {
+		into.Vendor = d.Vendor
+	}
+	if d.ClassOfDevice != 0 {
+		into.ClassOfDevice = d.ClassOfDevice
+	}
+	if d.Sensor != nil {
+		if into.Sensor == nil {
+			into.Sensor = &Sensor{}
+		}
+		into.Sensor.Merge(d.Sensor)
+		into.Sensor.Updated = d.Sensor.Updated
+	}
+	if d.Advertisement != nil {
+		if into.Advertisement == nil {
+			into.Advertisement = &Advertisement{}
+		}
+		into.Advertisement.Merge(d.Advertisement)
+	}
+	if d.HasTxPower {
+		into.TxPower, into.HasTxPower = d.TxPower, true
+	}
+	for id, r := range d.Adapters {
+		if into.Adapters == nil {
+			into.Adapters = make(map[string]AdapterReading)
+		}
+		into.Adapters[id] = r
+		s.smoothers[smootherKey(into.MAC, id)] = s.smoothers[smootherKey(d.MAC, id)]
+		delete(s.smoothers, smootherKey(d.MAC, id))
+	}
+	if d.BestAdapter != "" {
+		into.BestAdapter = d.BestAdapter
+	}
+	if sm, ok := s.smoothers[d.MAC]; ok {
+		s.smoothers[into.MAC] = sm
+		delete(s.smoothers, d.MAC)
+	}
+	s.updateDistance(into)
+}
+
+// addAddress links addr to device d so that messages from it update d,
+// forgetting the oldest links beyond maxAddresses. Callers hold s.mu.
+func (s *DeviceStore) addAddress(d *Device, addr string) {
+	d.Addresses = append(d.Addresses, addr)
+	s.aliases[addr] = d.MAC
+	if n := len(d.Addresses) - maxAddresses; n > 0 {
+		for _, old := range d.Addresses[1 : n+1] {
+			delete(s.aliases, old)
+		}
+		// Keep the first address: it is the store key.
+		d.Addresses = append(d.Addresses[:1], d.Addresses[n+1:]...)
+	}
+}
+
+// updateInterval folds the time since the device was last heard by adapter
+// into its advertising interval estimate.
+func (d *Device) updateInterval(adapter string, now time.Time) {
+	prev := d.LastSeen
+	if adapter != "" {
+		prev = d.Adapters[adapter].LastSeen
+	}
+	if prev.IsZero() {
+		return
+	}
+	gap := now.Sub(prev)
+	if d.AdvInterval == 0 {
+		d.AdvInterval = gap
+		return
+	}
+	d.AdvInterval = (3*d.AdvInterval + gap) / 4
+}
+
+// smooth feeds rssi to the smoother stored under key, creating it if needed.
+// Callers hold s.mu.
+func (s *DeviceStore) smooth(key string, rssi float64) float64 {
+	sm, ok := s.smoothers[key]
+	if !ok {
+		sm = s.newSmooth()
+		s.smoothers[key] = sm
+	}
+	return sm.Update(rssi)
+}
+
+// smootherKey separates the filter state of each adapter hearing a device.
+func smootherKey(mac, adapter string) string {
+	if adapter == "" {
+		return mac
+	}
+	return mac + "@" + adapter
+}
+
+// setAdapterReading records a reading from one adapter and sets RSSI to the
+// strongest reading among adapters that heard the device recently.
+func (d *Device) setAdapterReading(adapter string, rssi, raw float64, now time.Time) {
+	if d.Adapters == nil {
+		d.Adapters = make(map[string]AdapterReading)
+	}
+	d.Adapters[adapter] = AdapterReading{RSSI: rssi, RawRSSI: raw, LastSeen: now}
+
+	best := ""
+	for id, r := range d.Adapters {
+		if now.Sub(r.LastSeen) > adapterStale {
+			continue
+		}
+		if best == "" || r.RSSI > d.Adapters[best].RSSI || (r.RSSI == d.Adapters[best].RSSI && id < best) {
+			best = id
+		}
+	}
+	d.BestAdapter = best
+	d.RSSI = d.Adapters[best].RSSI
+	d.RawRSSI = d.Adapters[best].RawRSSI
+}
+
+// updateDistance recomputes the distance estimate from the smoothed RSSI.
+// A per-device calibration wins; otherwise the device's own advertised
+// reference is combined with the global model. A pinned bearing wins over
+// the angle; otherwise, if a localizer can place the device, its bearing
+// and range replace the hashed angle. Callers hold s.mu.
+func (s *DeviceStore) updateDistance(d *Device) {
+	if p, ok := s.perDevice[d.MAC]; ok {
+		d.RefPower, d.RefPowerSource, d.PathLossExp = p.MeasuredPower, PowerCalibrated, p.Exponent
+	} else {
+		d.RefPower, d.RefPowerSource = d.ReferencePower(s.pathLoss.MeasuredPower)
+		if d.RefPowerSource == PowerDefault && s.calibrated {
+			d.RefPowerSource = PowerCalibrated
+		}
+		d.PathLossExp = s.pathLoss.Exponent
+	}
+	d.Distance = RSSIToDistance(d.RSSI, d.RefPower, d.PathLossExp)
+
+	if a, ok := s.pinned[d.MAC]; ok {
+		d.Angle, d.Pinned = a, true
+		d.Position, d.Confidence, d.Located = Point{}, 0, false
+		return
+	}
+	if d.Pinned {
+		d.Angle, d.Pinned = MacToAngle(d.MAC), false
+	}
+
+	if s.localizer == nil {
+		return
+	}
+	if loc, ok := s.localizer.Locate(d); ok {
+		d.Angle, d.Distance = loc.Angle, loc.Distance
+		d.Position, d.Confidence, d.Located = loc.Position, loc.Confidence, true
+		return
+	}
+	if d.Located {
+		d.Angle = MacToAngle(d.MAC)
+		d.Position, d.Confidence, d.Located = Point{}, 0, false
+	}
+}
+
+// Get returns a copy of the device with the given MAC or linked address.
+func (s *DeviceStore) Get(mac string) (*Device, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if key, ok := s.aliases[mac]; ok {
+		mac = key
+	}
+	d, ok := s.devices[mac]
+	if !ok {
+		return nil, false
+	}
+	return d.clone(), true
+}
+
+// Now returns the store's clock: the wall clock, or the recorded time of the
+// latest message once replayed messages arrive.
+func (s *DeviceStore) Now() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.now()
+}
+
+// now implements Now. Callers hold s.mu.
+func (s *DeviceStore) now() time.Time {
+	if s.clock.IsZero() {
+		return time.Now()
+	}
+	return s.clock
+}
+
+// Advance moves the clock to t, the recorded time of a replayed message,
+// and evicts devices not seen within timeout of it. Calling it before each
+// replayed Upsert evicts exactly as the live capture would have, whatever
+// the replay speed; periodic Evict calls in between change nothing.
+func (s *DeviceStore) Advance(t time.Time, timeout time.Duration) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t.After(s.clock) {
+		s.clock = t
+	}
+	return s.evict(timeout)
+}
+
+// Evict removes devices not seen within the timeout duration of the
+// store's clock. Returns the number of evicted devices.
 func (s *DeviceStore) Evict(timeout time.Duration) int {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	return s.evict(timeout)
+}
 
-	cutoff := time.Now().Add(-timeout)
+// evict implements Evict. Callers hold s.mu.
+func (s *DeviceStore) evict(timeout time.Duration) int {
+	cutoff := s.now().Add(-timeout)
 	count := 0
 	for mac, dev := range s.devices {
 		if dev.LastSeen.Before(cutoff) {
 			delete(s.devices, mac)
+			delete(s.smoothers, mac)
+			for id := range dev.Adapters {
+				delete(s.smoothers, smootherKey(mac, id))
+			}
+			for _, addr := range dev.Addresses {
+				delete(s.aliases, addr)
+			}
+			if s.byIdentity[dev.Identity] == mac {
+				delete(s.byIdentity, dev.Identity)
+			}
 			count++
 		}
 	}
@@ -87,8 +652,7 @@ func (s *DeviceStore) Snapshot() []*Device {
 	result := make([]*Device, 0, len(s.devices))
 	for _, d := range s.devices {
 		// Copy device to avoid data races
-		cp := *d
-		result = append(result, &cp)
+		result = append(result, d.clone())
 	}
 
 	sort.Slice(result, func(i, j int) bool {
@@ -0,0 +1,189 @@
+package bluetooth
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+const (
+	oldAddr = "4A:00:00:00:00:01"
+	newAddr = "5B:00:00:00:00:02"
+)
+
+// newTestStore returns a store with the default path loss model.
+func newTestStore() *DeviceStore {
+	return NewDeviceStore(PathLoss{MeasuredPower: -59, Exponent: 2}, func() Smoother { return &emaSmoother{alpha: 0.5} })
+}
+
+// heardAt returns an advertisement from a resolvable private address with
+// fingerprint "F", received ms milliseconds after t0.
+func heardAt(t0 time.Time, mac string, ms int, rssi int16) DeviceDiscoveredMsg {
+	return DeviceDiscoveredMsg{
+		MAC: mac, RSSI: rssi, Type: DeviceTypeBLE, AddressType: AddressResolvable, Fingerprint: "F",
+		At: t0.Add(time.Duration(ms) * time.Millisecond),
+	}
+}
+
+// addresses returns the addresses of every stored device by store key.
+func addresses(s *DeviceStore) map[string][]string {
+	out := make(map[string][]string)
+	for _, d := range s.Snapshot() {
+		out[d.MAC] = d.Addresses
+	}
+	return out
+}
+
+func TestRelink(t *testing.T) {
+	t0 := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	old := func(ms int) DeviceDiscoveredMsg { return heardAt(t0, oldAddr, ms, -60) }
+	rotated := func(ms int) DeviceDiscoveredMsg { return heardAt(t0, newAddr, ms, -62) }
+	with := func(msg DeviceDiscoveredMsg, change func(*DeviceDiscoveredMsg)) DeviceDiscoveredMsg {
+		change(&msg)
+		return msg
+	}
+	rssi := func(v int16) func(*DeviceDiscoveredMsg) { return func(m *DeviceDiscoveredMsg) { m.RSSI = v } }
+	fingerprint := func(f string) func(*DeviceDiscoveredMsg) { return func(m *DeviceDiscoveredMsg) { m.Fingerprint = f } }
+	merged := map[string][]string{oldAddr: {oldAddr, newAddr}}
+	apart := map[string][]string{oldAddr: {oldAddr}, newAddr: {newAddr}}
+
+	tests := []struct {
+		name string
+		msgs []DeviceDiscoveredMsg
+		want map[string][]string
+	}{
+		{
+			name: "rotation inside the window",
+			msgs: []DeviceDiscoveredMsg{old(0), old(1000), rotated(3000), rotated(5500)},
+			want: merged,
+		},
+		{
+			name: "silence of the whole window",
+			msgs: []DeviceDiscoveredMsg{old(0), old(1000), rotated(16000), rotated(18000)},
+			want: merged,
+		},
+		{
+			name: "rotation outside the window",
+			msgs: []DeviceDiscoveredMsg{old(0), old(1000), rotated(16500), rotated(18500)},
+			want: apart,
+		},
+		{
+			name: "old address heard briefly after",
+			msgs: []DeviceDiscoveredMsg{old(0), rotated(3000), old(3900), rotated(5500)},
+			want: merged,
+		},
+		{
+			name: "old address still in use",
+			msgs: []DeviceDiscoveredMsg{old(0), rotated(3000), old(4500), rotated(5500)},
+			want: apart,
+		},
+		{
+			name: "RSSI jump at the limit",
+			msgs: []DeviceDiscoveredMsg{old(0), with(rotated(3000), rssi(-72)), with(rotated(5500), rssi(-72))},
+			want: merged,
+		},
+		{
+			name: "RSSI jump above the limit",
+			msgs: []DeviceDiscoveredMsg{old(0), with(rotated(3000), rssi(-73)), with(rotated(5500), rssi(-73))},
+			want: apart,
+		},
+		{
+			// The settle check compares the latest raw reading, not the first.
+			name: "RSSI settles close",
+			msgs: []DeviceDiscoveredMsg{old(0), with(rotated(3000), rssi(-80)), rotated(5500)},
+			want: merged,
+		},
+		{
+			name: "not settled yet",
+			msgs: []DeviceDiscoveredMsg{old(0), rotated(3000), rotated(4500)},
+			want: apart,
+		},
+		{
+			name: "other fingerprint",
+			msgs: []DeviceDiscoveredMsg{old(0), with(rotated(3000), fingerprint("G")), with(rotated(5500), fingerprint("G"))},
+			want: apart,
+		},
+		{
+			name: "public addresses do not rotate",
+			msgs: []DeviceDiscoveredMsg{
+				with(old(0), func(m *DeviceDiscoveredMsg) { m.AddressType = AddressPublic }), rotated(3000), rotated(5500),
+			},
+			want: apart,
+		},
+		{
+			// A fingerprint that only matches after the settle check is
+			// not linked later.
+			name: "checked once",
+			msgs: []DeviceDiscoveredMsg{
+				old(0), with(rotated(3000), fingerprint("G")), with(rotated(5500), fingerprint("G")), rotated(6000),
+			},
+			want: apart,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestStore()
+			for _, msg := range tt.msgs {
+				s.Upsert(msg)
+			}
+			if got := addresses(s); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("devices = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRelinkClosestCandidate(t *testing.T) {
+	// Two devices share the fingerprint; the new address continues the
+	// one at the closer signal level.
+	t0 := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	s := newTestStore()
+	s.Upsert(heardAt(t0, oldAddr, 0, -50))
+	s.Upsert(heardAt(t0, "4A:00:00:00:00:03", 0, -70))
+	s.Upsert(heardAt(t0, newAddr, 2000, -68))
+	s.Upsert(heardAt(t0, newAddr, 4000, -68))
+	want := map[string][]string{oldAddr: {oldAddr}, "4A:00:00:00:00:03": {"4A:00:00:00:00:03", newAddr}}
+	if got := addresses(s); !reflect.DeepEqual(got, want) {
+		t.Errorf("devices = %v, want %v", got, want)
+	}
+}
+
+func TestGetAlias(t *testing.T) {
+	t0 := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	s := newTestStore()
+	for _, msg := range []DeviceDiscoveredMsg{
+		heardAt(t0, oldAddr, 0, -60), heardAt(t0, newAddr, 3000, -62), heardAt(t0, newAddr, 5500, -62),
+	} {
+		s.Upsert(msg)
+	}
+
+	for _, addr := range []string{oldAddr, newAddr} {
+		d, ok := s.Get(addr)
+		if !ok {
+			t.Fatalf("Get(%s) found nothing", addr)
+		}
+		if d.MAC != oldAddr || d.Address != newAddr || !d.FirstSeen.Equal(t0) {
+			t.Errorf("Get(%s) = MAC %s, address %s, first seen %v", addr, d.MAC, d.Address, d.FirstSeen)
+		}
+	}
+
+	// Later messages from the new address update the merged device.
+	s.Upsert(heardAt(t0, newAddr, 7000, -64))
+	if n := s.Count(); n != 1 {
+		t.Errorf("%d devices after a message from the linked address", n)
+	}
+	if d, _ := s.Get(oldAddr); !d.LastSeen.Equal(t0.Add(7 * time.Second)) {
+		t.Errorf("last seen %v, want the linked address's message", d.LastSeen)
+	}
+
+	// Eviction forgets the device under every address.
+	s.Advance(t0.Add(time.Minute), 10*time.Second)
+	for _, addr := range []string{oldAddr, newAddr} {
+		if _, ok := s.Get(addr); ok {
+			t.Errorf("Get(%s) found an evicted device", addr)
+		}
+	}
+	if _, ok := s.Get("00:11:22:33:44:55"); ok {
+		t.Error("Get found an unknown address")
+	}
+}
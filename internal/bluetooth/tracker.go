@@ -0,0 +1,172 @@
+package bluetooth
+
+import (
+	"encoding/hex"
+	"fmt"
+)
+
+// TrackerKind identifies the finder network a tracking tag belongs to.
+type TrackerKind int
+
+const (
+	TrackerFindMy TrackerKind = iota + 1
+	TrackerTile
+	TrackerSmartTag
+)
+
+func (k TrackerKind) String() string {
+	switch k {
+	case TrackerFindMy:
+		return "Find My"
+	case TrackerTile:
+		return "Tile"
+	case TrackerSmartTag:
+		return "SmartTag"
+	default:
+		return ""
+	}
+}
+
+// MarshalText encodes the kind by name for JSON output.
+func (k TrackerKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText parses a kind name written by MarshalText.
+func (k *TrackerKind) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "Find My":
+		*k = TrackerFindMy
+	case "Tile":
+		*k = TrackerTile
+	case "SmartTag":
+		*k = TrackerSmartTag
+	case "":
+		*k = 0
+	default:
+		return fmt.Errorf("unknown tracker kind %q", text)
+	}
+	return nil
+}
+
+// Company IDs, service UUIDs and frame constants of the tracker networks.
+const (
+	companyTile            = 0x02FF
+	serviceTile            = 0xFEED
+	serviceTileLegacy      = 0xFEEC
+	serviceSmartTag        = 0xFD5A
+	findMyType             = 0x12
+	findMySeparatedLength  = 0x19 // Full public key: away from the owner
+	findMyNearbyLength     = 0x02 // Owner device close by
+	findMyModelMask        = 0x30
+	findMyBatteryShift     = 6
+	smartTagStateMask      = 0x07
+	smartTagPrivacyIDStart = 4
+	smartTagPrivacyIDEnd   = 12
+)
+
+// findMyModels maps the device type bits of the Find My status byte.
+var findMyModels = map[byte]string{
+	0x00: "Apple device",
+	0x10: "AirTag",
+	0x20: "Find My accessory",
+	0x30: "AirPods",
+}
+
+var trackerBatteryLevels = []string{"full", "medium", "low", "critical"}
+
+// smartTagStates names the advertisement states of a SmartTag. The later
+// offline states and lost mode mean the tag has been away from its owner.
+var smartTagStates = map[byte]struct {
+	name      string
+	separated bool
+}{
+	1: {"premature offline", false},
+	2: {"offline", false},
+	3: {"overmature offline", true},
+	4: {"lost", true},
+	5: {"connected", false},
+}
+
+// Tracker holds the decoded frame of a Find My, Tile or SmartTag tag.
+type Tracker struct {
+	Kind      TrackerKind `json:"kind"`
+	Model     string      `json:"model,omitempty"`     // E.g. "AirTag"; empty if the frame does not say
+	State     string      `json:"state,omitempty"`     // Network specific advertising state
+	Separated bool        `json:"separated,omitempty"` // Away from its owner, the state stalking tags are in
+	Battery   string      `json:"battery,omitempty"`   // full, medium, low or critical, if advertised
+	Key       string      `json:"key,omitempty"`       // Rotating identifier from the frame, hex
+}
+
+// Label returns a short identifier suitable as a fallback device name.
+func (t *Tracker) Label() string {
+	if t.Model != "" {
+		return t.Model
+	}
+	return t.Kind.String()
+}
+
+// ParseFindMy decodes Apple manufacturer data carrying an offline finding
+// (Find My) frame. Returns nil if the data is not one.
+func ParseFindMy(companyID uint16, data []byte) *Tracker {
+	// type(1) len(1) status(1) [key(22) key_bits(1) hint(1)]
+	if companyID != companyApple || len(data) < 3 || data[0] != findMyType {
+		return nil
+	}
+	status := data[2]
+	t := &Tracker{
+		Kind:    TrackerFindMy,
+		Model:   findMyModels[status&findMyModelMask],
+		Battery: trackerBatteryLevels[status>>findMyBatteryShift],
+	}
+	switch data[1] {
+	case findMySeparatedLength:
+		if len(data) < 2+findMySeparatedLength {
+			return nil
+		}
+		t.State, t.Separated = "separated", true
+		t.Key = hex.EncodeToString(data[3:9])
+	case findMyNearbyLength:
+		t.State = "near owner"
+	default:
+		return nil
+	}
+	return t
+}
+
+// ParseTile recognizes Tile by its service UUID. The service data, when
+// present, carries the tag's identifier. Returns nil for other services.
+func ParseTile(serviceUUID uint16, serviceData []byte) *Tracker {
+	if serviceUUID != serviceTile && serviceUUID != serviceTileLegacy {
+		return nil
+	}
+	t := tileTracker()
+	if len(serviceData) > 0 {
+		t.Key = hex.EncodeToString(serviceData)
+	}
+	return t
+}
+
+// tileTracker returns a Tile tag without an identifier, for tags known only
+// by their company ID or advertised service.
+func tileTracker() *Tracker {
+	return &Tracker{Kind: TrackerTile, Model: "Tile"}
+}
+
+// ParseSmartTag decodes the service data of Samsung's offline finding
+// service (0xFD5A). Returns nil for truncated frames.
+func ParseSmartTag(data []byte) *Tracker {
+	// state(1) aging(3) privacy_id(8) ...
+	if len(data) < smartTagPrivacyIDEnd {
+		return nil
+	}
+	t := &Tracker{
+		Kind:  TrackerSmartTag,
+		Model: "SmartTag",
+		Key:   hex.EncodeToString(data[smartTagPrivacyIDStart:smartTagPrivacyIDEnd]),
+	}
+	if st, ok := smartTagStates[data[0]&smartTagStateMask]; ok {
+		t.State, t.Separated = st.name, st.separated
+	}
+	return t
+}
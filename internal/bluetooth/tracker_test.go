@@ -0,0 +1,131 @@
+package bluetooth
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// findMyKey is the 22 public key bytes, key bits and hint of a separated
+// Find My frame.
+const findMyKey = "A1B2C3D4E5F6 0718293A4B5C6D7E8F90A1B2C3D4E5F6 02 7F"
+
+func TestParseFindMy(t *testing.T) {
+	tests := []struct {
+		name string
+		data string // Apple manufacturer data after the company ID
+		want *Tracker
+	}{
+		{
+			name: "separated airtag",
+			data: "12 19 10 " + findMyKey,
+			want: &Tracker{Kind: TrackerFindMy, Model: "AirTag", State: "separated", Separated: true, Battery: "full", Key: "a1b2c3d4e5f6"},
+		},
+		{
+			name: "separated airtag low battery",
+			data: "12 19 90 " + findMyKey,
+			want: &Tracker{Kind: TrackerFindMy, Model: "AirTag", State: "separated", Separated: true, Battery: "low", Key: "a1b2c3d4e5f6"},
+		},
+		{
+			name: "separated airpods critical battery",
+			data: "12 19 F0 " + findMyKey,
+			want: &Tracker{Kind: TrackerFindMy, Model: "AirPods", State: "separated", Separated: true, Battery: "critical", Key: "a1b2c3d4e5f6"},
+		},
+		{
+			name: "near owner accessory",
+			data: "12 02 64 01",
+			want: &Tracker{Kind: TrackerFindMy, Model: "Find My accessory", State: "near owner", Battery: "medium"},
+		},
+		{
+			name: "near owner apple device",
+			data: "12 02 00 00",
+			want: &Tracker{Kind: TrackerFindMy, Model: "Apple device", State: "near owner", Battery: "full"},
+		},
+		{name: "separated without hint", data: "12 19 10 " + strings.TrimSuffix(findMyKey, " 7F")},
+		{name: "separated truncated key", data: "12 19 10 A1B2C3D4E5F6"},
+		{name: "unknown length", data: "12 05 10 A1B2C3D4"},
+		{name: "status missing", data: "12 02"},
+		{name: "continuity", data: "10 05 1B 1C 4B7E3A"},
+		{name: "empty", data: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseFindMy(companyApple, unhex(t, tt.data))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFindMy = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if tr := ParseFindMy(companyMicrosoft, unhex(t, tests[0].data)); tr != nil {
+		t.Error("decoded manufacturer data of another company")
+	}
+}
+
+func TestParseSmartTag(t *testing.T) {
+	tests := []struct {
+		name string
+		data string // Service data of 0xFD5A
+		want *Tracker
+	}{
+		{
+			name: "lost",
+			data: "04 000A1B 0102030405060708 00 1122",
+			want: &Tracker{Kind: TrackerSmartTag, Model: "SmartTag", State: "lost", Separated: true, Key: "0102030405060708"},
+		},
+		{
+			name: "overmature offline",
+			data: "03 000A1B 0102030405060708",
+			want: &Tracker{Kind: TrackerSmartTag, Model: "SmartTag", State: "overmature offline", Separated: true, Key: "0102030405060708"},
+		},
+		{
+			name: "offline",
+			data: "02 000000 F0E1D2C3B4A59687",
+			want: &Tracker{Kind: TrackerSmartTag, Model: "SmartTag", State: "offline", Key: "f0e1d2c3b4a59687"},
+		},
+		{
+			// Only the low three bits carry the state
+			name: "connected with flags",
+			data: "D5 000000 F0E1D2C3B4A59687",
+			want: &Tracker{Kind: TrackerSmartTag, Model: "SmartTag", State: "connected", Key: "f0e1d2c3b4a59687"},
+		},
+		{
+			name: "unknown state",
+			data: "07 000000 F0E1D2C3B4A59687",
+			want: &Tracker{Kind: TrackerSmartTag, Model: "SmartTag", Key: "f0e1d2c3b4a59687"},
+		},
+		{name: "truncated privacy id", data: "04 000A1B 01020304050607"},
+		{name: "empty", data: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseSmartTag(unhex(t, tt.data))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSmartTag = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTile(t *testing.T) {
+	tests := []struct {
+		name    string
+		service uint16
+		data    string
+		want    *Tracker
+	}{
+		{"service data", serviceTile, "0200 3C4F9A21D7E8B605", &Tracker{Kind: TrackerTile, Model: "Tile", Key: "02003c4f9a21d7e8b605"}},
+		{"legacy service", serviceTileLegacy, "01", &Tracker{Kind: TrackerTile, Model: "Tile", Key: "01"}},
+		{"no service data", serviceTile, "", &Tracker{Kind: TrackerTile, Model: "Tile"}},
+		{"other service", serviceSmartTag, "0200 3C4F9A21D7E8B605", nil},
+		{"eddystone", 0xFEAA, "10 EB 00 61", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseTile(tt.service, unhex(t, tt.data))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTile(%#04x) = %+v, want %+v", tt.service, got, tt.want)
+			}
+		})
+	}
+}
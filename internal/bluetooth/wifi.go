@@ -7,16 +7,26 @@ import (
 	"strconv"
 	"strings"
 	"time"
-
-	tea "github.com/charmbracelet/bubbletea"
 )
 
+func init() {
+	Register(Registration{
+		Name:        "wifi",
+		Description: "WiFi access points via nmcli or iw",
+		Default:     true,
+		Available:   WiFiScannerAvailable,
+		New: func(opts ScannerOptions) (Scanner, error) {
+			return NewWiFiScanner("", opts.WiFiInterval), nil
+		},
+	})
+}
+
 // WiFiScanner discovers nearby WiFi access points.
 // Prefers nmcli (no root needed), falls back to iw (needs root).
 type WiFiScanner struct {
-	program  *tea.Program
+	health
+	sink     Sink
 	iface    string
-	running  bool
 	cancel   context.CancelFunc
 	interval time.Duration
 	useNmcli bool
@@ -35,21 +45,28 @@ func NewWiFiScanner(iface string, interval time.Duration) *WiFiScanner {
 	}
 }
 
+// Name implements Scanner.
+func (s *WiFiScanner) Name() string { return "wifi" }
+
+// Capabilities implements Scanner.
+func (s *WiFiScanner) Capabilities() Capability { return CapWiFi | CapRSSI }
+
 // Start begins periodic WiFi scans in a goroutine.
-func (s *WiFiScanner) Start(p *tea.Program) error {
-	s.program = p
-	s.running = true
+func (s *WiFiScanner) Start(ctx context.Context, sink Sink) error {
+	s.sink = sink
 
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(ctx)
 	s.cancel = cancel
+	s.reset()
 
 	go s.loop(ctx)
 	return nil
 }
 
 func (s *WiFiScanner) loop(ctx context.Context) {
+	defer s.set(StateStopped, nil)
 	for {
-		if !s.running {
+		if ctx.Err() != nil {
 			return
 		}
 		s.scan()
@@ -69,9 +86,7 @@ func (s *WiFiScanner) scan() {
 		msgs = s.scanIW()
 	}
 	for _, msg := range msgs {
-		if s.program != nil {
-			s.program.Send(msg)
-		}
+		s.emit(s.sink, msg)
 	}
 }
 
@@ -236,7 +251,6 @@ func parseIWScan(output string) []DeviceDiscoveredMsg {
 
 // Stop halts the WiFi scanner.
 func (s *WiFiScanner) Stop() {
-	s.running = false
 	if s.cancel != nil {
 		s.cancel()
 	}
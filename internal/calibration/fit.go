@@ -0,0 +1,90 @@
+// Package calibration fits the log-distance path loss model to RSSI samples
+// taken at known distances and persists the resulting profiles.
+package calibration
+
+import (
+	"errors"
+	"math"
+
+	"ble-radar.klederson.com/internal/bluetooth"
+)
+
+// Point is a set of raw RSSI samples taken at one known distance.
+type Point struct {
+	Distance float64   // Meters
+	Samples  []float64 // dBm
+}
+
+// Mean returns the average RSSI of the point's samples.
+func (p Point) Mean() float64 {
+	if len(p.Samples) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range p.Samples {
+		sum += v
+	}
+	return sum / float64(len(p.Samples))
+}
+
+// Result is the outcome of a least-squares fit.
+type Result struct {
+	Model bluetooth.PathLoss
+	RMSE  float64 // Root-mean-square residual in dB
+	N     int     // Samples used
+}
+
+// Fit estimates MeasuredPower and the path loss exponent from points by
+// ordinary least squares on rssi = P - 10·n·log10(d). At least two distinct
+// distances are required.
+func Fit(points []Point) (Result, error) {
+	var xs, ys []float64
+	distances := make(map[float64]bool)
+	for _, p := range points {
+		if p.Distance <= 0 {
+			return Result{}, errors.New("distances must be positive")
+		}
+		for _, rssi := range p.Samples {
+			xs = append(xs, -10*math.Log10(p.Distance))
+			ys = append(ys, rssi)
+		}
+		if len(p.Samples) > 0 {
+			distances[p.Distance] = true
+		}
+	}
+	if len(distances) < 2 {
+		return Result{}, errors.New("need samples at two or more distinct distances")
+	}
+
+	n := float64(len(xs))
+	var mx, my float64
+	for i := range xs {
+		mx += xs[i]
+		my += ys[i]
+	}
+	mx /= n
+	my /= n
+
+	var sxy, sxx float64
+	for i := range xs {
+		sxy += (xs[i] - mx) * (ys[i] - my)
+		sxx += (xs[i] - mx) * (xs[i] - mx)
+	}
+	exp := sxy / sxx
+	power := my - exp*mx
+	if exp <= 0 {
+		return Result{}, errors.New("signal does not weaken with distance; check device placement")
+	}
+
+	var sse float64
+	for i := range xs {
+		r := ys[i] - (power + exp*xs[i])
+		sse += r * r
+	}
+
+	return Result{
+		Model: bluetooth.PathLoss{MeasuredPower: power, Exponent: exp},
+		RMSE:  math.Sqrt(sse / n),
+		N:     len(xs),
+	}, nil
+}
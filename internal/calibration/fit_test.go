@@ -0,0 +1,105 @@
+package calibration
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// synthetic returns points at distances whose samples follow the model
+// exactly, each spread by ±noise so the mean is unchanged.
+func synthetic(power, exp, noise float64, distances ...float64) []Point {
+	var points []Point
+	for _, d := range distances {
+		rssi := power - 10*exp*math.Log10(d)
+		points = append(points, Point{Distance: d, Samples: []float64{rssi - noise, rssi, rssi + noise}})
+	}
+	return points
+}
+
+func TestFit(t *testing.T) {
+	tests := []struct {
+		name     string
+		points   []Point
+		power    float64
+		exp      float64
+		wantRMSE float64
+		wantN    int
+	}{
+		{
+			name:   "free space",
+			points: synthetic(-59, 2, 0, 1, 2, 4),
+			power:  -59, exp: 2, wantN: 9,
+		},
+		{
+			name:   "indoor",
+			points: synthetic(-65, 3.2, 0, 0.5, 1.5, 3, 6, 10),
+			power:  -65, exp: 3.2, wantN: 15,
+		},
+		{
+			// The ±1.5 dB spread at each distance only shows in the residual.
+			name:   "noisy",
+			points: synthetic(-62, 2.7, 1.5, 0.5, 1, 2, 4, 8),
+			power:  -62, exp: 2.7, wantRMSE: math.Sqrt(1.5), wantN: 15,
+		},
+		{
+			name:   "two distances",
+			points: synthetic(-70, 2.5, 0, 1, 3),
+			power:  -70, exp: 2.5, wantN: 6,
+		},
+		{
+			name:   "empty points ignored",
+			points: append(synthetic(-59, 2, 0, 1, 2), Point{Distance: 5}),
+			power:  -59, exp: 2, wantN: 6,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := Fit(tt.points)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(r.Model.MeasuredPower-tt.power) > 1e-9 || math.Abs(r.Model.Exponent-tt.exp) > 1e-9 {
+				t.Errorf("model = %+v, want power %v, exponent %v", r.Model, tt.power, tt.exp)
+			}
+			if math.Abs(r.RMSE-tt.wantRMSE) > 1e-9 {
+				t.Errorf("RMSE = %v, want %v", r.RMSE, tt.wantRMSE)
+			}
+			if r.N != tt.wantN {
+				t.Errorf("N = %d, want %d", r.N, tt.wantN)
+			}
+		})
+	}
+}
+
+func TestFitErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		points  []Point
+		wantErr string
+	}{
+		{"no points", nil, "two or more distinct distances"},
+		{"one distance", synthetic(-59, 2, 1, 2), "two or more distinct distances"},
+		{
+			"same distance twice",
+			[]Point{{Distance: 2, Samples: []float64{-65}}, {Distance: 2, Samples: []float64{-66, -64}}},
+			"two or more distinct distances",
+		},
+		{
+			"second distance without samples",
+			[]Point{{Distance: 1, Samples: []float64{-59}}, {Distance: 3}},
+			"two or more distinct distances",
+		},
+		{"zero distance", []Point{{Distance: 0, Samples: []float64{-40}}, {Distance: 1, Samples: []float64{-59}}}, "distances must be positive"},
+		{"signal rises with distance", synthetic(-59, -2, 0, 1, 2, 4), "signal does not weaken with distance"},
+		{"flat signal", []Point{{Distance: 1, Samples: []float64{-60}}, {Distance: 4, Samples: []float64{-60}}}, "signal does not weaken with distance"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := Fit(tt.points)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Fit = %+v, %v; want error %q", r, err, tt.wantErr)
+			}
+		})
+	}
+}
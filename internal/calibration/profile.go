@@ -0,0 +1,79 @@
+package calibration
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"ble-radar.klederson.com/internal/bluetooth"
+)
+
+// Profiles is the on-disk set of calibrations: an optional global model and
+// per-device models keyed by MAC address.
+type Profiles struct {
+	Global  *bluetooth.PathLoss           `json:"global,omitempty"`
+	Devices map[string]bluetooth.PathLoss `json:"devices,omitempty"`
+	Updated time.Time                     `json:"updated"`
+}
+
+// DefaultPath returns the profile location in the user's config directory.
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "calibration.json"
+	}
+	return filepath.Join(dir, "ble-radar", "calibration.json")
+}
+
+// Load reads profiles from path. A missing file yields empty profiles.
+// Device keys are upper-cased like the addresses they are matched against,
+// so hand-edited entries in lower case apply too.
+func Load(path string) (*Profiles, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &Profiles{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var p Profiles
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, err
+	}
+	devices := p.Devices
+	p.Devices = nil
+	for mac, m := range devices {
+		p.SetDevice(mac, m)
+	}
+	return &p, nil
+}
+
+// Save writes profiles to path, creating parent directories.
+func (p *Profiles) Save(path string) error {
+	p.Updated = time.Now()
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// SetDevice stores a per-device model.
+func (p *Profiles) SetDevice(mac string, m bluetooth.PathLoss) {
+	if p.Devices == nil {
+		p.Devices = make(map[string]bluetooth.PathLoss)
+	}
+	p.Devices[strings.ToUpper(mac)] = m
+}
+
+// Apply installs the profiles into store.
+func (p *Profiles) Apply(store *bluetooth.DeviceStore) {
+	store.SetCalibration(p.Global, p.Devices)
+}
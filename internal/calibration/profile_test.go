@@ -0,0 +1,78 @@
+package calibration
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"ble-radar.klederson.com/internal/bluetooth"
+)
+
+func TestLoadNormalizesDevices(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "calibration.json")
+	data := `{
+  "global": {"measured_power": -60, "path_loss_exp": 2.2},
+  "devices": {
+    "aa:bb:cc:dd:ee:ff": {"measured_power": -71, "path_loss_exp": 3},
+    "00:11:22:33:44:55": {"measured_power": -55, "path_loss_exp": 2}
+  }
+}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]bluetooth.PathLoss{
+		"AA:BB:CC:DD:EE:FF": {MeasuredPower: -71, Exponent: 3},
+		"00:11:22:33:44:55": {MeasuredPower: -55, Exponent: 2},
+	}
+	if !reflect.DeepEqual(p.Devices, want) {
+		t.Errorf("devices = %v, want %v", p.Devices, want)
+	}
+
+	// The hand-edited entry applies to the device the store keys in upper case.
+	store := bluetooth.NewDeviceStore(bluetooth.PathLoss{MeasuredPower: -59, Exponent: 2}, func() bluetooth.Smoother { return passThrough{} })
+	p.Apply(store)
+	store.Upsert(bluetooth.DeviceDiscoveredMsg{MAC: "AA:BB:CC:DD:EE:FF", RSSI: -71})
+	d, ok := store.Get("AA:BB:CC:DD:EE:FF")
+	if !ok {
+		t.Fatal("device not stored")
+	}
+	if d.RefPowerSource != bluetooth.PowerCalibrated || d.RefPower != -71 {
+		t.Errorf("reference %v from %v, want -71 from the device profile", d.RefPower, d.RefPowerSource)
+	}
+}
+
+func TestProfilesRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "calibration.json")
+	p, err := Load(path)
+	if err != nil {
+		t.Fatalf("missing file: %v", err)
+	}
+	if p.Global != nil || len(p.Devices) != 0 {
+		t.Errorf("missing file gave %+v, want empty profiles", p)
+	}
+
+	p.Global = &bluetooth.PathLoss{MeasuredPower: -61, Exponent: 2.4}
+	p.SetDevice("de:ad:be:ef:00:01", bluetooth.PathLoss{MeasuredPower: -68, Exponent: 2.9})
+	if err := p.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Global, p.Global) || !reflect.DeepEqual(got.Devices, p.Devices) || !got.Updated.Equal(p.Updated) {
+		t.Errorf("loaded %+v, want %+v", got, p)
+	}
+	if _, ok := got.Devices["DE:AD:BE:EF:00:01"]; !ok {
+		t.Errorf("devices = %v, want the upper-case address", got.Devices)
+	}
+}
+
+type passThrough struct{}
+
+func (passThrough) Update(rssi float64) float64 { return rssi }
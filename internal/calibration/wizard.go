@@ -0,0 +1,91 @@
+package calibration
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Wizard walks the user through sampling a device at known distances.
+// It starts waiting for a distance (pre-filled with 1 m), collects raw
+// RSSI samples for it, then waits for the next distance.
+type Wizard struct {
+	MAC    string
+	Name   string
+	Input  string // Distance being typed, in meters
+	Points []Point
+
+	target   int
+	sampling *Point
+}
+
+// NewWizard starts a calibration for one device, taking samples readings
+// per distance.
+func NewWizard(mac, name string, samples int) *Wizard {
+	if samples < 1 {
+		samples = 1
+	}
+	return &Wizard{MAC: mac, Name: name, Input: "1", target: samples}
+}
+
+// Begin starts sampling at the distance in Input.
+func (w *Wizard) Begin() error {
+	d, err := strconv.ParseFloat(strings.TrimSpace(w.Input), 64)
+	if err != nil || d <= 0 {
+		return fmt.Errorf("invalid distance %q", w.Input)
+	}
+	w.sampling = &Point{Distance: d}
+	return nil
+}
+
+// Sampling reports whether samples are currently being collected.
+func (w *Wizard) Sampling() bool {
+	return w.sampling != nil
+}
+
+// Progress returns the samples collected for the current point and the
+// number required.
+func (w *Wizard) Progress() (int, int) {
+	if w.sampling == nil {
+		return 0, w.target
+	}
+	return len(w.sampling.Samples), w.target
+}
+
+// Distance returns the distance currently being sampled, or 0.
+func (w *Wizard) Distance() float64 {
+	if w.sampling == nil {
+		return 0
+	}
+	return w.sampling.Distance
+}
+
+// Add records a raw RSSI sample while sampling. When enough samples are
+// collected the point is stored and the wizard waits for the next distance.
+func (w *Wizard) Add(rssi float64) {
+	if w.sampling == nil {
+		return
+	}
+	w.sampling.Samples = append(w.sampling.Samples, rssi)
+	if len(w.sampling.Samples) >= w.target {
+		w.Points = append(w.Points, *w.sampling)
+		w.sampling = nil
+		w.Input = ""
+	}
+}
+
+// Undo discards the sample run in progress or, if idle, the last point.
+func (w *Wizard) Undo() {
+	if w.sampling != nil {
+		w.sampling = nil
+		return
+	}
+	if len(w.Points) > 0 {
+		w.Points = w.Points[:len(w.Points)-1]
+	}
+}
+
+// Fit fits the model to the collected points.
+func (w *Wizard) Fit() (Result, error) {
+	return Fit(w.Points)
+}
@@ -1,35 +1,177 @@
 package config
 
-import "time"
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
 
 const (
-	// RSSI to distance estimation
-	MeasuredPower = -59.0 // RSSI at 1 meter (dBm)
-	PathLossExp   = 2.5   // Path loss exponent (N)
-
-	// Radar display
-	MaxRange      = 30.0 // Maximum range in meters
-	AspectRatio   = 0.5  // Terminal char aspect correction (chars are ~2:1 tall)
-	RingCount     = 4    // Number of concentric rings
-	SweepSpeedRPM = 30   // Sweep rotations per minute (1 rotation per 2 seconds)
-	SweepTrailDeg = 60.0 // Sweep trail angle in degrees
-	TargetFPS     = 30   // Target frames per second
-
-	// Device management
-	DeviceTimeout  = 30 * time.Second // Remove devices not seen for this long
-	EvictInterval  = 5 * time.Second  // How often to run eviction
-	SmoothingAlpha = 0.3              // EMA smoothing factor (30% new, 70% old)
-
-	// Scanner
-	ScanInterval   = 100 * time.Millisecond // BLE scan callback throttle
-	ClassicScanSec = 8                      // hcitool scan duration in seconds
-	WiFiScanSec    = 15                     // iw scan interval in seconds
-
-	// Demo mode
-	DemoDeviceMin = 8  // Minimum fake devices
-	DemoDeviceMax = 12 // Maximum fake devices
-
 	// App
 	AppName    = "BLE-RADAR"
 	AppVersion = "1.0"
 )
+
+// Config holds every runtime-tunable setting. Default returns the built-in
+// values; Load overlays config files on top of them.
+type Config struct {
+	Distance     Distance     `yaml:"distance"`
+	Radar        Radar        `yaml:"radar"`
+	Devices      Devices      `yaml:"devices"`
+	Smoothing    Smoothing    `yaml:"smoothing"`
+	Scanner      Scanner      `yaml:"scanner"`
+	Calibration  Calibration  `yaml:"calibration"`
+	Localization Localization `yaml:"localization"`
+	Survey       Survey       `yaml:"survey"`
+	Trackers     Trackers     `yaml:"trackers"`
+	Identities   []Identity   `yaml:"identities"`
+}
+
+// Distance configures RSSI to distance estimation.
+type Distance struct {
+	MeasuredPower float64 `yaml:"measured_power"` // RSSI at 1 meter (dBm)
+	PathLossExp   float64 `yaml:"path_loss_exp"`  // Path loss exponent (N)
+}
+
+// Radar configures the radar display.
+type Radar struct {
+	MaxRange      float64 `yaml:"max_range"`       // Maximum range in meters
+	AspectRatio   float64 `yaml:"aspect_ratio"`    // Terminal char aspect correction (chars are ~2:1 tall)
+	RingCount     int     `yaml:"ring_count"`      // Number of concentric rings
+	SweepSpeedRPM float64 `yaml:"sweep_speed_rpm"` // Sweep rotations per minute
+	SweepTrailDeg float64 `yaml:"sweep_trail_deg"` // Sweep trail angle in degrees
+	TargetFPS     int     `yaml:"target_fps"`      // Target frames per second
+}
+
+// Devices configures device management in the store.
+type Devices struct {
+	Timeout       time.Duration `yaml:"timeout"`        // Remove devices not seen for this long
+	EvictInterval time.Duration `yaml:"evict_interval"` // How often to run eviction
+}
+
+// Smoothing configures RSSI smoothing.
+type Smoothing struct {
+	Method       string  `yaml:"method"`        // ema, kalman or median
+	Alpha        float64 `yaml:"alpha"`         // EMA smoothing factor (weight of the new sample)
+	KalmanQ      float64 `yaml:"kalman_q"`      // dB² drift allowed per sample
+	KalmanR      float64 `yaml:"kalman_r"`      // dB² variance of a single reading
+	MedianWindow int     `yaml:"median_window"` // Samples in the median filter
+}
+
+// Scanner configures the scan sources.
+type Scanner struct {
+	Adapter         string        `yaml:"adapter"`          // Bluetooth adapter, e.g. hci0
+	Adapters        []string      `yaml:"adapters"`         // BLE adapters scanned concurrently; empty uses adapter
+	Scanners        []string      `yaml:"scanners"`         // Registry names; empty selects defaults
+	ClassicInterval time.Duration `yaml:"classic_interval"` // Pause between hcitool scans
+	WiFiInterval    time.Duration `yaml:"wifi_interval"`    // Pause between WiFi scans
+}
+
+// Calibration configures the distance calibration wizard.
+type Calibration struct {
+	Samples int    `yaml:"samples"` // Raw RSSI readings collected per distance
+	Profile string `yaml:"profile"` // Profile file; empty uses the user config dir
+}
+
+// Survey configures position surveys for trilateration.
+type Survey struct {
+	Samples int    `yaml:"samples"` // Raw RSSI readings collected per position
+	Dir     string `yaml:"dir"`     // Where surveys are saved; empty uses the user config dir
+}
+
+// Trackers configures unwanted-tracker detection. A tag raises an alert
+// once it has been around for MinDuration and was seen in MinWindows
+// separate time windows or at MinPlaces places.
+type Trackers struct {
+	MinDuration time.Duration `yaml:"min_duration"` // How long a tag must stay around
+	Window      time.Duration `yaml:"window"`       // Length of one time window
+	MinWindows  int           `yaml:"min_windows"`  // Distinct windows the tag must appear in
+	MinPlaces   int           `yaml:"min_places"`   // Distinct places the tag must appear at
+	RotationGap time.Duration `yaml:"rotation_gap"` // Longest silence bridged when a tag changes address
+}
+
+// Identity is a known device whose resolvable private addresses are
+// resolved with its Identity Resolving Key.
+type Identity struct {
+	Name string `yaml:"name"` // Shown in place of the advertised name
+	IRK  string `yaml:"irk"`  // 16-byte key as hex (colons allowed) or base64
+}
+
+// Key decodes the IRK. Hex is read most significant byte first, the way
+// tools and pairing databases print it.
+func (i Identity) Key() ([16]byte, error) {
+	var key [16]byte
+	s := strings.ReplaceAll(strings.TrimSpace(i.IRK), ":", "")
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		b, err = base64.StdEncoding.DecodeString(s)
+	}
+	if err != nil {
+		return key, fmt.Errorf("irk %q is neither hex nor base64", i.IRK)
+	}
+	if len(b) != len(key) {
+		return key, fmt.Errorf("irk must be 16 bytes, got %d", len(b))
+	}
+	copy(key[:], b)
+	return key, nil
+}
+
+// Localization configures bearing estimation from several adapters.
+type Localization struct {
+	Anchors map[string]Position `yaml:"anchors"` // Adapter ID -> position; needs three or more, not in a line
+}
+
+// Position is a point in meters relative to the radar center: X east, Y north.
+type Position struct {
+	X float64 `yaml:"x"`
+	Y float64 `yaml:"y"`
+}
+
+// Default returns the built-in configuration.
+func Default() *Config {
+	return &Config{
+		Distance: Distance{
+			MeasuredPower: -59.0,
+			PathLossExp:   2.5,
+		},
+		Radar: Radar{
+			MaxRange:      30.0,
+			AspectRatio:   0.5,
+			RingCount:     4,
+			SweepSpeedRPM: 30, // 1 rotation per 2 seconds
+			SweepTrailDeg: 60.0,
+			TargetFPS:     30,
+		},
+		Devices: Devices{
+			Timeout:       30 * time.Second,
+			EvictInterval: 5 * time.Second,
+		},
+		Smoothing: Smoothing{
+			Method:       "ema",
+			Alpha:        0.3, // 30% new, 70% old
+			KalmanQ:      0.5,
+			KalmanR:      8.0,
+			MedianWindow: 5,
+		},
+		Scanner: Scanner{
+			Adapter:         "hci0",
+			ClassicInterval: 8 * time.Second,
+			WiFiInterval:    15 * time.Second,
+		},
+		Calibration: Calibration{
+			Samples: 20,
+		},
+		Survey: Survey{
+			Samples: 20,
+		},
+		Trackers: Trackers{
+			MinDuration: 15 * time.Minute,
+			Window:      5 * time.Minute,
+			MinWindows:  3,
+			MinPlaces:   2,
+			RotationGap: 30 * time.Second,
+		},
+	}
+}
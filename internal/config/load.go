@@ -0,0 +1,164 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the config file looked up in each XDG config directory.
+const FileName = "config.yaml"
+
+// SearchPaths returns candidate config files from lowest to highest
+// precedence: $XDG_CONFIG_DIRS (default /etc/xdg), then $XDG_CONFIG_HOME
+// (default ~/.config).
+func SearchPaths() []string {
+	var paths []string
+
+	dirs := os.Getenv("XDG_CONFIG_DIRS")
+	if dirs == "" {
+		dirs = "/etc/xdg"
+	}
+	sys := filepath.SplitList(dirs)
+	// XDG_CONFIG_DIRS is ordered most important first.
+	for i := len(sys) - 1; i >= 0; i-- {
+		if sys[i] != "" {
+			paths = append(paths, filepath.Join(sys[i], "ble-radar", FileName))
+		}
+	}
+
+	if home, err := os.UserConfigDir(); err == nil {
+		paths = append(paths, filepath.Join(home, "ble-radar", FileName))
+	}
+	return paths
+}
+
+// Load returns the defaults overlaid with config files. If path is set only
+// that file is read and it must exist; otherwise every existing file from
+// SearchPaths is applied in order. The returned list names the files used.
+func Load(path string) (*Config, []string, error) {
+	cfg := Default()
+
+	candidates := SearchPaths()
+	if path != "" {
+		candidates = []string{path}
+	}
+
+	var used []string
+	for _, p := range candidates {
+		data, err := os.ReadFile(p)
+		if errors.Is(err, fs.ErrNotExist) && path == "" {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := cfg.merge(data); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", p, err)
+		}
+		used = append(used, p)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("invalid configuration (%s): %w", sourceList(used), err)
+	}
+	return cfg, used, nil
+}
+
+// merge overlays the YAML document in data onto c. Keys that are absent keep
+// their current value; unknown keys are an error.
+func (c *Config) merge(data []byte) error {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+// Validate checks that every setting is usable and reports all problems.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Distance.MeasuredPower < 0, "distance.measured_power must be negative dBm, got %g", c.Distance.MeasuredPower)
+	check(c.Distance.PathLossExp > 0, "distance.path_loss_exp must be positive, got %g", c.Distance.PathLossExp)
+
+	check(c.Radar.MaxRange > 0, "radar.max_range must be positive, got %g", c.Radar.MaxRange)
+	check(c.Radar.AspectRatio > 0, "radar.aspect_ratio must be positive, got %g", c.Radar.AspectRatio)
+	check(c.Radar.RingCount >= 1 && c.Radar.RingCount <= 12, "radar.ring_count must be between 1 and 12, got %d", c.Radar.RingCount)
+	check(c.Radar.SweepSpeedRPM >= 0, "radar.sweep_speed_rpm must not be negative, got %g", c.Radar.SweepSpeedRPM)
+	check(c.Radar.SweepTrailDeg > 0 && c.Radar.SweepTrailDeg <= 360, "radar.sweep_trail_deg must be in (0, 360], got %g", c.Radar.SweepTrailDeg)
+	check(c.Radar.TargetFPS >= 1 && c.Radar.TargetFPS <= 120, "radar.target_fps must be between 1 and 120, got %d", c.Radar.TargetFPS)
+
+	check(c.Devices.Timeout > 0, "devices.timeout must be positive, got %s", c.Devices.Timeout)
+	check(c.Devices.EvictInterval > 0, "devices.evict_interval must be positive, got %s", c.Devices.EvictInterval)
+
+	switch strings.ToLower(c.Smoothing.Method) {
+	case "ema", "kalman", "median":
+	default:
+		errs = append(errs, fmt.Errorf("smoothing.method must be ema, kalman or median, got %q", c.Smoothing.Method))
+	}
+	check(c.Smoothing.Alpha > 0 && c.Smoothing.Alpha <= 1, "smoothing.alpha must be in (0, 1], got %g", c.Smoothing.Alpha)
+	check(c.Smoothing.KalmanQ > 0, "smoothing.kalman_q must be positive, got %g", c.Smoothing.KalmanQ)
+	check(c.Smoothing.KalmanR > 0, "smoothing.kalman_r must be positive, got %g", c.Smoothing.KalmanR)
+	check(c.Smoothing.MedianWindow >= 1, "smoothing.median_window must be at least 1, got %d", c.Smoothing.MedianWindow)
+
+	check(c.Scanner.Adapter != "", "scanner.adapter must not be empty")
+	seen := make(map[string]bool, len(c.Scanner.Adapters))
+	for _, a := range c.Scanner.Adapters {
+		check(a != "", "scanner.adapters must not contain empty names")
+		check(!seen[a], "scanner.adapters lists %s twice", a)
+		seen[a] = true
+	}
+	check(c.Scanner.ClassicInterval > 0, "scanner.classic_interval must be positive, got %s", c.Scanner.ClassicInterval)
+	check(c.Scanner.WiFiInterval > 0, "scanner.wifi_interval must be positive, got %s", c.Scanner.WiFiInterval)
+
+	n := len(c.Localization.Anchors)
+	check(n == 0 || n >= 3, "localization.anchors needs at least three adapters, got %d", n)
+	for id := range c.Localization.Anchors {
+		check(id != "", "localization.anchors must not contain empty adapter names")
+	}
+	if n >= 3 {
+		for _, id := range c.Scanner.Adapters {
+			if _, ok := c.Localization.Anchors[id]; !ok {
+				errs = append(errs, fmt.Errorf("localization.anchors has no position for scanner adapter %s", id))
+			}
+		}
+	}
+
+	check(c.Calibration.Samples >= 1, "calibration.samples must be at least 1, got %d", c.Calibration.Samples)
+	check(c.Survey.Samples >= 1, "survey.samples must be at least 1, got %d", c.Survey.Samples)
+	check(c.Trackers.MinDuration >= 0, "trackers.min_duration must not be negative, got %s", c.Trackers.MinDuration)
+	check(c.Trackers.Window > 0, "trackers.window must be positive, got %s", c.Trackers.Window)
+	check(c.Trackers.MinWindows >= 1, "trackers.min_windows must be at least 1, got %d", c.Trackers.MinWindows)
+	check(c.Trackers.MinPlaces >= 1, "trackers.min_places must be at least 1, got %d", c.Trackers.MinPlaces)
+	check(c.Trackers.RotationGap >= 0, "trackers.rotation_gap must not be negative, got %s", c.Trackers.RotationGap)
+
+	for i, id := range c.Identities {
+		check(id.Name != "", "identities[%d].name must not be empty", i)
+		if _, err := id.Key(); err != nil {
+			errs = append(errs, fmt.Errorf("identities[%d].%w", i, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func sourceList(used []string) string {
+	if len(used) == 0 {
+		return "defaults and flags"
+	}
+	return strings.Join(used, ", ")
+}
@@ -0,0 +1,249 @@
+package config
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(*Config)
+		want   []string // Substrings of the error; none for a valid config
+	}{
+		{name: "defaults", change: func(*Config) {}},
+		{
+			name:   "positive measured power",
+			change: func(c *Config) { c.Distance.MeasuredPower = 4 },
+			want:   []string{"distance.measured_power must be negative dBm, got 4"},
+		},
+		{
+			name:   "zero path loss exponent",
+			change: func(c *Config) { c.Distance.PathLossExp = 0 },
+			want:   []string{"distance.path_loss_exp"},
+		},
+		{
+			name:   "ring count",
+			change: func(c *Config) { c.Radar.RingCount = 13 },
+			want:   []string{"radar.ring_count must be between 1 and 12, got 13"},
+		},
+		{
+			name:   "sweep trail full circle",
+			change: func(c *Config) { c.Radar.SweepTrailDeg = 360 },
+		},
+		{
+			name:   "sweep trail",
+			change: func(c *Config) { c.Radar.SweepTrailDeg = 0 },
+			want:   []string{"radar.sweep_trail_deg"},
+		},
+		{
+			name:   "stopped sweep",
+			change: func(c *Config) { c.Radar.SweepSpeedRPM = 0 },
+		},
+		{
+			name:   "frame rate",
+			change: func(c *Config) { c.Radar.TargetFPS = 0 },
+			want:   []string{"radar.target_fps"},
+		},
+		{
+			name:   "device timeout",
+			change: func(c *Config) { c.Devices.Timeout = 0 },
+			want:   []string{"devices.timeout must be positive, got 0s"},
+		},
+		{
+			name:   "smoothing method case",
+			change: func(c *Config) { c.Smoothing.Method = "Kalman" },
+		},
+		{
+			name:   "smoothing method",
+			change: func(c *Config) { c.Smoothing.Method = "mean" },
+			want:   []string{`smoothing.method must be ema, kalman or median, got "mean"`},
+		},
+		{
+			name:   "alpha",
+			change: func(c *Config) { c.Smoothing.Alpha = 1.5 },
+			want:   []string{"smoothing.alpha"},
+		},
+		{
+			name:   "duplicate adapters",
+			change: func(c *Config) { c.Scanner.Adapters = []string{"hci0", "hci1", "hci0"} },
+			want:   []string{"scanner.adapters lists hci0 twice"},
+		},
+		{
+			name:   "empty adapter",
+			change: func(c *Config) { c.Scanner.Adapters = []string{""} },
+			want:   []string{"scanner.adapters must not contain empty names"},
+		},
+		{
+			name: "anchors",
+			change: func(c *Config) {
+				c.Scanner.Adapters = []string{"hci0", "hci1", "hci2"}
+				c.Localization.Anchors = map[string]Position{"hci0": {}, "hci1": {X: 1}, "hci2": {Y: 1}}
+			},
+		},
+		{
+			name:   "single anchor",
+			change: func(c *Config) { c.Localization.Anchors = map[string]Position{"hci0": {}} },
+			want:   []string{"localization.anchors needs at least three adapters, got 1"},
+		},
+		{
+			name: "two anchors",
+			change: func(c *Config) {
+				c.Scanner.Adapters = []string{"hci0", "hci1"}
+				c.Localization.Anchors = map[string]Position{"hci0": {}, "hci1": {X: 1}}
+			},
+			want: []string{"localization.anchors needs at least three adapters, got 2"},
+		},
+		{
+			name: "adapter without anchor",
+			change: func(c *Config) {
+				c.Scanner.Adapters = []string{"hci0", "hci1", "hci2", "hci3"}
+				c.Localization.Anchors = map[string]Position{"hci0": {}, "hci1": {X: 1}, "hci2": {Y: 1}}
+			},
+			want: []string{"localization.anchors has no position for scanner adapter hci3"},
+		},
+		{
+			name:   "negative tracker duration",
+			change: func(c *Config) { c.Trackers.MinDuration = -time.Minute },
+			want:   []string{"trackers.min_duration must not be negative, got -1m0s"},
+		},
+		{
+			name: "identities",
+			change: func(c *Config) {
+				c.Identities = []Identity{
+					{Name: "phone", IRK: "ec0234a357c8ad05341010a60a397d9b"},
+					{Name: "watch", IRK: "7AI0o1fIrQU0EBCmCjl9mw=="},
+				}
+			},
+		},
+		{
+			name: "bad identities",
+			change: func(c *Config) {
+				c.Identities = []Identity{{IRK: "ec0234a357c8ad05341010a60a397d9b"}, {Name: "watch", IRK: "0102"}}
+			},
+			want: []string{"identities[0].name must not be empty", "identities[1].irk must be 16 bytes, got 2"},
+		},
+		{
+			// Every problem is reported, not only the first.
+			name: "several problems",
+			change: func(c *Config) {
+				c.Radar.MaxRange = -1
+				c.Smoothing.MedianWindow = 0
+				c.Survey.Samples = 0
+			},
+			want: []string{"radar.max_range", "smoothing.median_window", "survey.samples"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Default()
+			tt.change(c)
+			err := c.Validate()
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("Validate: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Validate = nil, want %q", tt.want)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(err.Error(), w) {
+					t.Errorf("Validate = %q, want it to contain %q", err, w)
+				}
+			}
+			if n := strings.Count(err.Error(), "\n") + 1; n != len(tt.want) {
+				t.Errorf("Validate reported %d problems, want %d:\n%v", n, len(tt.want), err)
+			}
+		})
+	}
+}
+
+func TestIdentityKey(t *testing.T) {
+	want := [16]byte{0xEC, 0x02, 0x34, 0xA3, 0x57, 0xC8, 0xAD, 0x05, 0x34, 0x10, 0x10, 0xA6, 0x0A, 0x39, 0x7D, 0x9B}
+	tests := []struct {
+		irk     string
+		wantErr string
+	}{
+		{irk: "ec0234a357c8ad05341010a60a397d9b"},
+		{irk: "EC:02:34:A3:57:C8:AD:05:34:10:10:A6:0A:39:7D:9B"},
+		{irk: " ec0234a357c8ad05341010a60a397d9b\n"},
+		{irk: "7AI0o1fIrQU0EBCmCjl9mw=="},
+		{irk: "ec0234a357c8ad05341010a60a397d", wantErr: "irk must be 16 bytes, got 15"},
+		{irk: "not a key!", wantErr: "neither hex nor base64"},
+		{irk: "", wantErr: "irk must be 16 bytes, got 0"},
+	}
+	for _, tt := range tests {
+		key, err := Identity{IRK: tt.irk}.Key()
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Key(%q) error = %v, want %q", tt.irk, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || key != want {
+			t.Errorf("Key(%q) = %X, %v; want %X", tt.irk, key, err, want)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	p := write("partial.yaml", "radar:\n  max_range: 12\nsmoothing:\n  method: median\n")
+	cfg, used, err := Load(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(used) != 1 || used[0] != p {
+		t.Errorf("used = %v, want [%s]", used, p)
+	}
+	def := Default()
+	if cfg.Radar.MaxRange != 12 || cfg.Smoothing.Method != "median" {
+		t.Errorf("file values not applied: max_range %v, method %q", cfg.Radar.MaxRange, cfg.Smoothing.Method)
+	}
+	if cfg.Radar.RingCount != def.Radar.RingCount || cfg.Distance != def.Distance {
+		t.Error("absent keys lost their defaults")
+	}
+
+	if _, _, err := Load(write("empty.yaml", "")); err != nil {
+		t.Errorf("empty file: %v", err)
+	}
+	if _, _, err := Load(write("unknown.yaml", "radar:\n  max_rnage: 12\n")); err == nil {
+		t.Error("unknown key accepted")
+	}
+	_, _, err = Load(write("invalid.yaml", "radar:\n  ring_count: 0\n"))
+	if err == nil || !strings.Contains(err.Error(), "invalid.yaml") || !strings.Contains(err.Error(), "radar.ring_count") {
+		t.Errorf("invalid value: error %v, want it to name the file and the key", err)
+	}
+	if _, _, err := Load(filepath.Join(dir, "missing.yaml")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("missing explicit file: error %v, want not exist", err)
+	}
+}
+
+func TestSearchPaths(t *testing.T) {
+	t.Setenv("XDG_CONFIG_DIRS", "/etc/first:/etc/second")
+	t.Setenv("XDG_CONFIG_HOME", "/home/u/.config")
+	want := []string{
+		"/etc/second/ble-radar/config.yaml",
+		"/etc/first/ble-radar/config.yaml",
+		"/home/u/.config/ble-radar/config.yaml",
+	}
+	got := SearchPaths()
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("SearchPaths = %v, want %v", got, want)
+	}
+}
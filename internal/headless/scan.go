@@ -0,0 +1,191 @@
+// Package headless runs the scanners and device store without a TUI.
+package headless
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"time"
+
+	"ble-radar.klederson.com/internal/bluetooth"
+	"ble-radar.klederson.com/internal/calibration"
+	"ble-radar.klederson.com/internal/config"
+	"ble-radar.klederson.com/internal/localization"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Options configures a headless scan.
+type Options struct {
+	Config   *config.Config
+	Scanners []bluetooth.Scanner
+	Duration time.Duration                 // Zero runs until ctx is cancelled
+	Types    map[bluetooth.DeviceType]bool // Empty emits every type
+	Recorder *bluetooth.Recorder           // Optional session recorder
+
+	Smoothing   bluetooth.SmootherFactory   // RSSI filter for each device
+	Calibration *calibration.Profiles       // Optional distance calibration
+	Identities  *bluetooth.IdentityResolver // Optional IRKs of known devices
+	Vendors     bluetooth.VendorLookup      // Optional OUI database
+}
+
+// Record is one JSON Lines entry written per discovery.
+type Record struct {
+	Time         time.Time `json:"time"`
+	MAC          string    `json:"mac"`
+	Name         string    `json:"name,omitempty"`
+	Type         string    `json:"type"`
+	RSSI         int16     `json:"rssi"`
+	SmoothedRSSI float64   `json:"rssi_smoothed"`
+	Distance     float64   `json:"distance_m"`
+	RefPower     float64   `json:"ref_power_dbm"`
+	RefSource    string    `json:"ref_power_source"`
+	Frequency    int       `json:"frequency_mhz,omitempty"`
+	Channel      int       `json:"channel,omitempty"`
+	Adapter      string    `json:"adapter,omitempty"`
+	AddressType  string    `json:"address_type,omitempty"`
+	DeviceID     string    `json:"device_id,omitempty"` // Store key when the address was linked to an earlier one
+	Identity     string    `json:"identity,omitempty"`  // Known device whose IRK resolved the address
+	Platform     string    `json:"platform,omitempty"`  // Apple, Windows, Android, Xbox or Linux
+	Vendor       string    `json:"vendor,omitempty"`    // Organization owning the OUI of a public address
+
+	ClassOfDevice bluetooth.DeviceClass `json:"class_of_device,omitempty"` // Classic devices
+
+	// Set when several adapters located the device
+	BearingDeg *float64 `json:"bearing_deg,omitempty"`
+	Confidence *float64 `json:"bearing_confidence,omitempty"`
+
+	Beacon     *bluetooth.Beacon          `json:"beacon,omitempty"`
+	Continuity *bluetooth.Continuity      `json:"continuity,omitempty"`
+	Microsoft  *bluetooth.MicrosoftBeacon `json:"microsoft,omitempty"`
+	FastPair   *bluetooth.FastPair        `json:"fast_pair,omitempty"`
+	Sensor     *bluetooth.Sensor          `json:"sensor,omitempty"`
+	Tracker    *bluetooth.Tracker         `json:"tracker,omitempty"`
+
+	// AD fields of this advertisement as received, not accumulated
+	Advertisement *bluetooth.Advertisement `json:"advertisement,omitempty"`
+}
+
+// Run starts the scanners, feeds every discovery through a DeviceStore and
+// writes one Record per DeviceDiscoveredMsg to w as newline-delimited JSON.
+// It returns when ctx is cancelled or the duration elapses.
+func Run(ctx context.Context, opts Options, w io.Writer) error {
+	if opts.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Duration)
+		defer cancel()
+	}
+
+	// Sends give up once the scan is cancelled, so scanners still sending
+	// after we stop reading do not block.
+	sink := make(chan tea.Msg, 64)
+	scanCtx, stopScan := context.WithCancel(ctx)
+	defer func() {
+		stopScan()
+		for _, s := range opts.Scanners {
+			s.Stop()
+		}
+	}()
+
+	scanSink := bluetooth.ContextSink(scanCtx, sink)
+	if opts.Recorder != nil {
+		scanSink = opts.Recorder.Wrap(scanSink)
+	}
+	for _, s := range opts.Scanners {
+		if err := s.Start(scanCtx, scanSink); err != nil {
+			return err
+		}
+	}
+
+	cfg := opts.Config
+	store := bluetooth.NewDeviceStore(bluetooth.PathLoss{
+		MeasuredPower: cfg.Distance.MeasuredPower,
+		Exponent:      cfg.Distance.PathLossExp,
+	}, opts.Smoothing)
+	if opts.Calibration != nil {
+		opts.Calibration.Apply(store)
+	}
+	if locator, ok := localization.FromConfig(cfg.Localization); ok {
+		store.SetLocalizer(locator)
+	}
+	if opts.Identities != nil {
+		store.SetIdentities(opts.Identities)
+	}
+	if opts.Vendors != nil {
+		store.SetVendors(opts.Vendors)
+	}
+	enc := json.NewEncoder(w)
+	evict := time.NewTicker(cfg.Devices.EvictInterval)
+	defer evict.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case <-evict.C:
+			store.Evict(cfg.Devices.Timeout)
+
+		case m := <-sink:
+			msg, ok := m.(bluetooth.DeviceDiscoveredMsg)
+			if !ok {
+				continue
+			}
+			if len(opts.Types) > 0 && !opts.Types[msg.Type] {
+				continue
+			}
+			if !msg.At.IsZero() {
+				// Replayed: evict on the recorded clock, whatever the speed.
+				store.Advance(msg.At, cfg.Devices.Timeout)
+			}
+			store.Upsert(msg)
+			if err := enc.Encode(newRecord(msg, store)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func newRecord(msg bluetooth.DeviceDiscoveredMsg, store *bluetooth.DeviceStore) Record {
+	rec := Record{
+		Time:      store.Now(),
+		MAC:       msg.MAC,
+		Name:      msg.Name,
+		Type:      msg.Type.String(),
+		RSSI:      msg.RSSI,
+		Frequency: msg.Frequency,
+		Channel:   msg.Channel,
+		Adapter:   msg.Adapter,
+
+		AddressType:   msg.AddressType.String(),
+		ClassOfDevice: msg.ClassOfDevice,
+
+		Advertisement: msg.Advertisement,
+	}
+	if d, ok := store.Get(msg.MAC); ok {
+		rec.SmoothedRSSI = d.RSSI
+		rec.Distance = d.Distance
+		rec.RefPower = d.RefPower
+		rec.RefSource = d.RefPowerSource.String()
+		if rec.Name == "" {
+			rec.Name = d.Name
+		}
+		rec.Beacon = d.Beacon
+		rec.Tracker = d.Tracker
+		rec.Continuity = d.Continuity
+		rec.Microsoft = d.Microsoft
+		rec.FastPair = d.FastPair
+		rec.Sensor = d.Sensor
+		rec.Platform = d.Platform
+		rec.Identity = d.Identity
+		rec.Vendor = d.Vendor
+		if d.MAC != msg.MAC {
+			rec.DeviceID = d.MAC
+		}
+		if d.Located {
+			deg := d.Angle * 180 / math.Pi
+			rec.BearingDeg, rec.Confidence = &deg, &d.Confidence
+		}
+	}
+	return rec
+}
@@ -0,0 +1,150 @@
+package headless
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"ble-radar.klederson.com/internal/bluetooth"
+	"ble-radar.klederson.com/internal/config"
+)
+
+// fakeScanner sends msgs, then waits for late to close and sends more
+// discoveries than the sink buffers, as a scanner still running after the
+// reader stopped would.
+type fakeScanner struct {
+	msgs    []any
+	late    chan struct{}
+	done    chan struct{}
+	stopped atomic.Bool
+}
+
+func (s *fakeScanner) Name() string                       { return "fake" }
+func (s *fakeScanner) Capabilities() bluetooth.Capability { return bluetooth.CapSynthetic }
+func (s *fakeScanner) Stop()                              { s.stopped.Store(true) }
+func (s *fakeScanner) Status() bluetooth.Status           { return bluetooth.Status{} }
+func (s *fakeScanner) Start(ctx context.Context, sink bluetooth.Sink) error {
+	go func() {
+		defer close(s.done)
+		for _, m := range s.msgs {
+			sink.Send(m)
+		}
+		<-s.late
+		for i := 0; i < 200; i++ {
+			sink.Send(bluetooth.DeviceDiscoveredMsg{MAC: "AA:AA:AA:AA:AA:AA", RSSI: -50, Type: bluetooth.DeviceTypeBLE})
+		}
+	}()
+	return nil
+}
+
+func TestRun(t *testing.T) {
+	s := &fakeScanner{
+		msgs: []any{
+			bluetooth.DeviceDiscoveredMsg{MAC: "00:11:22:33:44:01", Name: "one", RSSI: -60, Type: bluetooth.DeviceTypeBLE},
+			bluetooth.DeviceDiscoveredMsg{MAC: "00:11:22:33:44:02", RSSI: -70, Type: bluetooth.DeviceTypeClassic},
+			bluetooth.ClassicScanErrorMsg{},
+			bluetooth.DeviceDiscoveredMsg{MAC: "00:11:22:33:44:01", RSSI: -62, Type: bluetooth.DeviceTypeBLE},
+			bluetooth.DeviceDiscoveredMsg{MAC: "00:11:22:33:44:03", RSSI: -80, Type: bluetooth.DeviceTypeBLE},
+		},
+		late: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	smoothing, err := bluetooth.NewSmootherFactory("ema", bluetooth.SmoothingParams{Alpha: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	err = Run(context.Background(), Options{
+		Config:    config.Default(),
+		Scanners:  []bluetooth.Scanner{s},
+		Duration:  200 * time.Millisecond,
+		Types:     map[bluetooth.DeviceType]bool{bluetooth.DeviceTypeBLE: true},
+		Smoothing: smoothing,
+	}, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.stopped.Load() {
+		t.Error("scanner not stopped")
+	}
+
+	// The scanner keeps sending after Run returned and must not block.
+	close(s.late)
+	select {
+	case <-s.done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("scanner blocked sending after Run returned")
+	}
+
+	want := []struct {
+		mac  string
+		name string
+		rssi int16
+	}{
+		{"00:11:22:33:44:01", "one", -60},
+		{"00:11:22:33:44:01", "one", -62},
+		{"00:11:22:33:44:03", "", -80},
+	}
+	var got []Record
+	sc := bufio.NewScanner(&out)
+	for sc.Scan() {
+		var r Record
+		if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+			t.Fatalf("line %q: %v", sc.Text(), err)
+		}
+		got = append(got, r)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("%d records, want %d:\n%s", len(got), len(want), out.String())
+	}
+	for i, w := range want {
+		r := got[i]
+		if r.MAC != w.mac || r.Name != w.name || r.RSSI != w.rssi || r.Type != "BLE" {
+			t.Errorf("record %d = %s %q %s %d, want %s %q BLE %d", i, r.MAC, r.Name, r.Type, r.RSSI, w.mac, w.name, w.rssi)
+		}
+		if r.SmoothedRSSI != float64(w.rssi) || r.Distance <= 0 {
+			t.Errorf("record %d: smoothed %v, distance %v; want %d and a distance", i, r.SmoothedRSSI, r.Distance, w.rssi)
+		}
+	}
+}
+
+func TestRunCancel(t *testing.T) {
+	s := &fakeScanner{late: make(chan struct{}), done: make(chan struct{})}
+	close(s.late)
+	smoothing, err := bluetooth.NewSmootherFactory("ema", bluetooth.SmoothingParams{Alpha: 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ret := make(chan error, 1)
+	go func() {
+		ret <- Run(ctx, Options{
+			Config:    config.Default(),
+			Scanners:  []bluetooth.Scanner{s},
+			Types:     map[bluetooth.DeviceType]bool{bluetooth.DeviceTypeWiFi: true},
+			Smoothing: smoothing,
+		}, &bytes.Buffer{})
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-ret:
+		if err != nil {
+			t.Errorf("Run = %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+	select {
+	case <-s.done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("scanner blocked after cancel")
+	}
+}
@@ -0,0 +1,218 @@
+package localization
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+const (
+	// markWindow is how far around a mark's time RSSI samples are averaged.
+	markWindow = 1500 * time.Millisecond
+
+	// maxHuntSamples caps the retained sample history.
+	maxHuntSamples = 4096
+
+	// HeadingStep is how far one turn key rotates the heading.
+	HeadingStep = 15 * math.Pi / 180
+)
+
+// HeadingMark records that the user faced Angle at Time.
+type HeadingMark struct {
+	Angle float64 // Radians clockwise from north
+	Time  time.Time
+}
+
+// MarkReading is a mark with the RSSI measured around it.
+type MarkReading struct {
+	HeadingMark
+	RSSI     float64 // Mean raw RSSI within the mark window
+	Samples  int
+	Complete bool // The window has closed
+}
+
+// Bearing is the outcome of a fox hunt estimate.
+type Bearing struct {
+	Angle      float64 // Radians clockwise from north
+	Amplitude  float64 // dB difference between facing towards and away, halved
+	Residual   float64 // RMS misfit in dB
+	Confidence float64 // [0, 1]
+}
+
+type huntSample struct {
+	t    time.Time
+	rssi float64
+}
+
+// FoxHunt finds the bearing of one device with a single adapter. The user
+// turns on the spot (or walks a circle) and marks the heading they face;
+// the body shadows the antenna, so RSSI peaks when facing the device.
+// Fitting rssi = a + b*cos(heading - bearing) to the marks gives the bearing.
+type FoxHunt struct {
+	MAC     string
+	Name    string
+	Heading float64 // Heading the user currently faces, radians
+	Marks   []HeadingMark
+
+	samples []huntSample
+}
+
+// NewFoxHunt starts a hunt for the given device facing north.
+func NewFoxHunt(mac, name string) *FoxHunt {
+	return &FoxHunt{MAC: mac, Name: name}
+}
+
+// Turn rotates the current heading by delta radians.
+func (f *FoxHunt) Turn(delta float64) {
+	f.Heading = math.Mod(f.Heading+delta, 2*math.Pi)
+	if f.Heading < 0 {
+		f.Heading += 2 * math.Pi
+	}
+}
+
+// Add records a raw RSSI sample from the hunted device.
+func (f *FoxHunt) Add(t time.Time, rssi float64) {
+	f.samples = append(f.samples, huntSample{t: t, rssi: rssi})
+	if len(f.samples) > maxHuntSamples {
+		f.samples = f.samples[len(f.samples)-maxHuntSamples:]
+	}
+}
+
+// Last returns the most recent RSSI sample.
+func (f *FoxHunt) Last() (float64, bool) {
+	if len(f.samples) == 0 {
+		return 0, false
+	}
+	return f.samples[len(f.samples)-1].rssi, true
+}
+
+// Mark records the current heading at time t.
+func (f *FoxHunt) Mark(t time.Time) {
+	f.Marks = append(f.Marks, HeadingMark{Angle: f.Heading, Time: t})
+}
+
+// Undo removes the last mark.
+func (f *FoxHunt) Undo() {
+	if len(f.Marks) > 0 {
+		f.Marks = f.Marks[:len(f.Marks)-1]
+	}
+}
+
+// Readings returns every mark with the RSSI averaged over its window.
+func (f *FoxHunt) Readings(now time.Time) []MarkReading {
+	out := make([]MarkReading, len(f.Marks))
+	for i, m := range f.Marks {
+		r := MarkReading{HeadingMark: m, Complete: now.Sub(m.Time) >= markWindow}
+		sum := 0.0
+		for _, s := range f.samples {
+			if s.t.Sub(m.Time).Abs() <= markWindow {
+				sum += s.rssi
+				r.Samples++
+			}
+		}
+		if r.Samples > 0 {
+			r.RSSI = sum / float64(r.Samples)
+		}
+		out[i] = r
+	}
+	return out
+}
+
+// Estimate fits the bearing to the completed marks. It needs readings at
+// three or more distinct headings.
+func (f *FoxHunt) Estimate(now time.Time) (Bearing, error) {
+	var h, y []float64
+	distinct := make(map[int]bool)
+	for _, r := range f.Readings(now) {
+		if !r.Complete || r.Samples == 0 {
+			continue
+		}
+		h = append(h, r.Angle)
+		y = append(y, r.RSSI)
+		distinct[int(math.Round(r.Angle*180/math.Pi))%360] = true
+	}
+	if len(distinct) < 3 {
+		return Bearing{}, errors.New("mark at least three different headings")
+	}
+
+	// Least squares on y = a + c*cos(h) + s*sin(h)
+	var m [3][3]float64
+	var v [3]float64
+	for i := range h {
+		row := [3]float64{1, math.Cos(h[i]), math.Sin(h[i])}
+		for r := 0; r < 3; r++ {
+			for c := 0; c < 3; c++ {
+				m[r][c] += row[r] * row[c]
+			}
+			v[r] += row[r] * y[i]
+		}
+	}
+	x, ok := solve3(m, v)
+	if !ok {
+		return Bearing{}, errors.New("headings too close together, spread them around")
+	}
+
+	amp := math.Hypot(x[1], x[2])
+	angle := math.Atan2(x[2], x[1])
+	if angle < 0 {
+		angle += 2 * math.Pi
+	}
+
+	sq := 0.0
+	for i := range h {
+		d := y[i] - (x[0] + x[1]*math.Cos(h[i]) + x[2]*math.Sin(h[i]))
+		sq += d * d
+	}
+	resid := math.Sqrt(sq / float64(len(h)))
+
+	// Signal: a clear peak over the noise. Coverage: headings spread around
+	// the circle rather than bunched on one side.
+	var cx, cy float64
+	for _, a := range h {
+		cx += math.Cos(a)
+		cy += math.Sin(a)
+	}
+	spread := 1 - math.Hypot(cx, cy)/float64(len(h))
+	signal := 1 - math.Exp(-amp/(resid+1))
+	coverage := math.Min(1, 2*spread)
+
+	return Bearing{
+		Angle:      angle,
+		Amplitude:  amp,
+		Residual:   resid,
+		Confidence: signal * coverage,
+	}, nil
+}
+
+// solve3 solves m*x = v by Gaussian elimination with partial pivoting.
+func solve3(m [3][3]float64, v [3]float64) ([3]float64, bool) {
+	for col := 0; col < 3; col++ {
+		p := col
+		for r := col + 1; r < 3; r++ {
+			if math.Abs(m[r][col]) > math.Abs(m[p][col]) {
+				p = r
+			}
+		}
+		if math.Abs(m[p][col]) < 1e-9 {
+			return [3]float64{}, false
+		}
+		m[col], m[p] = m[p], m[col]
+		v[col], v[p] = v[p], v[col]
+		for r := col + 1; r < 3; r++ {
+			k := m[r][col] / m[col][col]
+			for c := col; c < 3; c++ {
+				m[r][c] -= k * m[col][c]
+			}
+			v[r] -= k * v[col]
+		}
+	}
+	var x [3]float64
+	for r := 2; r >= 0; r-- {
+		sum := v[r]
+		for c := r + 1; c < 3; c++ {
+			sum -= m[r][c] * x[c]
+		}
+		x[r] = sum / m[r][r]
+	}
+	return x, true
+}
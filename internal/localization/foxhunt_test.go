@@ -0,0 +1,170 @@
+package localization
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+const deg = math.Pi / 180
+
+// hunt marks each heading in turn, five seconds apart, with RSSI following
+// the body-shadowing model -70 + amp*cos(heading - bearing) plus the given
+// per-mark noise. It returns the hunt and a time after every window closed.
+func hunt(bearing, amp float64, headings, noise []float64) (*FoxHunt, time.Time) {
+	f := NewFoxHunt("AA:BB:CC:DD:EE:FF", "tag")
+	t := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i, h := range headings {
+		f.Turn(h - f.Heading)
+		rssi := -70 + amp*math.Cos(h-bearing)
+		if noise != nil {
+			rssi += noise[i]
+		}
+		f.Add(t.Add(-time.Second), rssi-1)
+		f.Add(t.Add(time.Second), rssi+1)
+		f.Mark(t)
+		t = t.Add(5 * time.Second)
+	}
+	return f, t
+}
+
+// angleDiff returns the absolute difference of two angles in radians.
+func angleDiff(a, b float64) float64 {
+	d := math.Mod(math.Abs(a-b), 2*math.Pi)
+	return math.Min(d, 2*math.Pi-d)
+}
+
+func TestFoxHuntEstimate(t *testing.T) {
+	tests := []struct {
+		name      string
+		bearing   float64
+		headings  []float64
+		noise     []float64
+		tolerance float64 // Bearing tolerance, radians
+		minConf   float64
+		maxConf   float64
+	}{
+		{
+			name:     "four quadrants",
+			bearing:  60 * deg,
+			headings: []float64{0, 90 * deg, 180 * deg, 270 * deg},
+			minConf:  0.9, maxConf: 1,
+		},
+		{
+			name:     "three headings across north",
+			bearing:  300 * deg,
+			headings: []float64{0, 120 * deg, 240 * deg},
+			minConf:  0.9, maxConf: 1,
+		},
+		{
+			name:     "full turn in 45 degree steps",
+			bearing:  200 * deg,
+			headings: []float64{0, 45 * deg, 90 * deg, 135 * deg, 180 * deg, 225 * deg, 270 * deg, 315 * deg},
+			minConf:  0.9, maxConf: 1,
+		},
+		{
+			// An exact fit, but the headings cover only one side.
+			name:     "bunched headings",
+			bearing:  10 * deg,
+			headings: []float64{0, 15 * deg, 30 * deg},
+			minConf:  0, maxConf: 0.2,
+		},
+		{
+			name:      "noisy",
+			bearing:   135 * deg,
+			headings:  []float64{0, 45 * deg, 90 * deg, 135 * deg, 180 * deg, 225 * deg, 270 * deg, 315 * deg},
+			noise:     []float64{1.5, -2, 0.5, 2, -1, 1, -1.5, 0},
+			tolerance: 20 * deg,
+			minConf:   0.5, maxConf: 0.95,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, now := hunt(tt.bearing, 6, tt.headings, tt.noise)
+			b, err := f.Estimate(now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tol := tt.tolerance
+			if tol == 0 {
+				tol = 1e-6
+			}
+			if d := angleDiff(b.Angle, tt.bearing); d > tol {
+				t.Errorf("bearing = %.1f°, want %.1f°", b.Angle/deg, tt.bearing/deg)
+			}
+			if b.Angle < 0 || b.Angle >= 2*math.Pi {
+				t.Errorf("bearing %v outside [0, 2π)", b.Angle)
+			}
+			if tt.noise == nil {
+				if math.Abs(b.Amplitude-6) > 1e-6 || b.Residual > 1e-6 {
+					t.Errorf("amplitude %v, residual %v; want 6, 0", b.Amplitude, b.Residual)
+				}
+			}
+			if b.Confidence < tt.minConf || b.Confidence > tt.maxConf {
+				t.Errorf("confidence = %.2f, want in [%.2f, %.2f]", b.Confidence, tt.minConf, tt.maxConf)
+			}
+		})
+	}
+}
+
+func TestFoxHuntEstimateErrors(t *testing.T) {
+	f, now := hunt(0, 6, []float64{0, 90 * deg}, nil)
+	if _, err := f.Estimate(now); err == nil {
+		t.Error("estimated from two headings")
+	}
+
+	f, now = hunt(0, 6, []float64{0, 0, 0, 360 * deg}, nil)
+	if _, err := f.Estimate(now); err == nil {
+		t.Error("estimated from one repeated heading")
+	}
+
+	// The last mark's window is still open.
+	f, now = hunt(0, 6, []float64{0, 120 * deg, 240 * deg}, nil)
+	last := f.Marks[len(f.Marks)-1].Time
+	if _, err := f.Estimate(last.Add(time.Second)); err == nil {
+		t.Error("estimated before the third window closed")
+	}
+	if _, err := f.Estimate(now); err != nil {
+		t.Errorf("after the windows closed: %v", err)
+	}
+
+	// A mark without samples does not count.
+	f.Undo()
+	f.Turn(120 * deg)
+	f.Mark(now)
+	if _, err := f.Estimate(now.Add(time.Minute)); err == nil {
+		t.Error("estimated with a mark that has no samples")
+	}
+}
+
+func TestFoxHuntReadings(t *testing.T) {
+	f, now := hunt(0, 6, []float64{0, 90 * deg}, nil)
+	rs := f.Readings(now)
+	if len(rs) != 2 {
+		t.Fatalf("%d readings, want 2", len(rs))
+	}
+	for i, want := range []float64{-64, -70} {
+		r := rs[i]
+		if r.Samples != 2 || math.Abs(r.RSSI-want) > 1e-9 || !r.Complete {
+			t.Errorf("reading %d = %+v, want RSSI %v from 2 samples, complete", i, r, want)
+		}
+	}
+	if got, ok := f.Last(); !ok || got != -69 {
+		t.Errorf("Last = %v, %v; want -69", got, ok)
+	}
+}
+
+func TestFoxHuntTurn(t *testing.T) {
+	f := NewFoxHunt("AA:BB:CC:DD:EE:FF", "")
+	for _, tt := range []struct{ delta, want float64 }{
+		{-HeadingStep, 345 * deg},
+		{2 * HeadingStep, 15 * deg},
+		{-30 * deg, 345 * deg},
+		{375 * deg, 0},
+	} {
+		f.Turn(tt.delta)
+		if angleDiff(f.Heading, tt.want) > 1e-9 || f.Heading < 0 || f.Heading >= 2*math.Pi {
+			t.Errorf("Turn(%.0f°) heading = %.1f°, want %.0f°", tt.delta/deg, f.Heading/deg, tt.want/deg)
+		}
+	}
+}
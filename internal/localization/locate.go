@@ -0,0 +1,217 @@
+// Package localization estimates device bearings from the RSSI that
+// several adapters at known positions report for the same device.
+package localization
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"ble-radar.klederson.com/internal/bluetooth"
+	"ble-radar.klederson.com/internal/config"
+)
+
+const (
+	// staleAfter drops readings this much older than the device's newest one.
+	staleAfter = 10 * time.Second
+
+	// spreadScale is the RSSI spread (dB) at which the bearing confidence
+	// from signal differences reaches about 63%.
+	spreadScale = 6.0
+
+	// refineIterations bounds the Gauss-Newton position refinement.
+	refineIterations = 20
+
+	// minAnchors is how many anchors must hear a device to place it. Two
+	// anchors, like anchors in a row, cannot tell which side of their line
+	// the device is on.
+	minAnchors = 3
+
+	// minFlatness is the smallest ratio between the narrow and the wide
+	// extent of the hearing anchors for them to count as spanning a plane.
+	minFlatness = 0.1
+)
+
+// Locator implements bluetooth.Localizer for a fixed set of anchors.
+type Locator struct {
+	anchors map[string]bluetooth.Point // Adapter ID -> position
+}
+
+// New returns a locator for adapters at the given positions.
+func New(anchors map[string]bluetooth.Point) *Locator {
+	return &Locator{anchors: anchors}
+}
+
+// FromConfig builds a locator from the configured anchors. It returns
+// false when fewer than three anchors are configured.
+func FromConfig(c config.Localization) (*Locator, bool) {
+	if len(c.Anchors) < minAnchors {
+		return nil, false
+	}
+	anchors := make(map[string]bluetooth.Point, len(c.Anchors))
+	for id, p := range c.Anchors {
+		anchors[id] = bluetooth.Point{X: p.X, Y: p.Y}
+	}
+	return New(anchors), true
+}
+
+// observation is one adapter's reading converted to a range.
+type observation struct {
+	id   string
+	at   bluetooth.Point
+	rssi float64
+	rng  float64 // Meters, from the path loss model
+}
+
+// Locate implements bluetooth.Localizer. It needs fresh readings from at
+// least three anchors that do not stand in a line.
+//
+// The bearing comes from the RSSI gradient across the anchors: the device
+// lies towards the adapters that hear it loudest. The position is then
+// refined by least-squares multilateration. Confidence grows with the RSSI
+// spread and the agreement between the fitted position and the measured
+// ranges.
+func (l *Locator) Locate(d *bluetooth.Device) (bluetooth.Location, bool) {
+	obs := l.observations(d)
+	if len(obs) < minAnchors || !spansPlane(obs) {
+		return bluetooth.Location{}, false
+	}
+
+	// Anchor centroid and mean signal
+	var c bluetooth.Point
+	meanRSSI, meanRange := 0.0, 0.0
+	for _, o := range obs {
+		c.X += o.at.X
+		c.Y += o.at.Y
+		meanRSSI += o.rssi
+		meanRange += o.rng
+	}
+	n := float64(len(obs))
+	c.X, c.Y = c.X/n, c.Y/n
+	meanRSSI /= n
+	meanRange /= n
+
+	// Gradient: anchors weighted by how much louder than average they hear
+	// the device.
+	var gx, gy float64
+	lo, hi := obs[0].rssi, obs[0].rssi
+	for _, o := range obs {
+		w := o.rssi - meanRSSI
+		gx += w * (o.at.X - c.X)
+		gy += w * (o.at.Y - c.Y)
+		lo, hi = math.Min(lo, o.rssi), math.Max(hi, o.rssi)
+	}
+	norm := math.Hypot(gx, gy)
+	if norm == 0 {
+		return bluetooth.Location{}, false
+	}
+	pos := bluetooth.Point{X: c.X + gx/norm*meanRange, Y: c.Y + gy/norm*meanRange}
+
+	pos, rms := refine(pos, obs)
+	fit := math.Exp(-rms / math.Max(meanRange, 1))
+	spread := 1 - math.Exp(-(hi-lo)/spreadScale)
+
+	angle := math.Atan2(pos.X, pos.Y)
+	if angle < 0 {
+		angle += 2 * math.Pi
+	}
+	return bluetooth.Location{
+		Position:   pos,
+		Angle:      angle,
+		Distance:   math.Max(0.1, math.Hypot(pos.X, pos.Y)),
+		Confidence: spread * fit,
+	}, true
+}
+
+// spansPlane reports whether the anchors of obs enclose an area rather than
+// standing in or near a line, by comparing the axes of their spread.
+func spansPlane(obs []observation) bool {
+	var mx, my float64
+	for _, o := range obs {
+		mx += o.at.X
+		my += o.at.Y
+	}
+	n := float64(len(obs))
+	mx, my = mx/n, my/n
+
+	var sxx, sxy, syy float64
+	for _, o := range obs {
+		dx, dy := o.at.X-mx, o.at.Y-my
+		sxx += dx * dx
+		sxy += dx * dy
+		syy += dy * dy
+	}
+	mid := (sxx + syy) / 2
+	diff := math.Sqrt((sxx-syy)*(sxx-syy)/4 + sxy*sxy)
+	wide, narrow := mid+diff, mid-diff
+	return wide > 0 && narrow >= minFlatness*minFlatness*wide
+}
+
+// observations returns the device's recent readings from known anchors,
+// sorted by adapter ID for deterministic results.
+func (l *Locator) observations(d *bluetooth.Device) []observation {
+	var newest time.Time
+	for _, r := range d.Adapters {
+		if r.LastSeen.After(newest) {
+			newest = r.LastSeen
+		}
+	}
+
+	var obs []observation
+	for id, r := range d.Adapters {
+		at, ok := l.anchors[id]
+		if !ok || newest.Sub(r.LastSeen) > staleAfter {
+			continue
+		}
+		obs = append(obs, observation{
+			id:   id,
+			at:   at,
+			rssi: r.RSSI,
+			rng:  bluetooth.RSSIToDistance(r.RSSI, d.RefPower, d.PathLossExp),
+		})
+	}
+	sort.Slice(obs, func(i, j int) bool { return obs[i].id < obs[j].id })
+	return obs
+}
+
+// refine runs Gauss-Newton on the range residuals |p - a_i| - r_i, weighting
+// near anchors higher since RSSI ranges get noisier with distance. It
+// returns the refined position and the weighted RMS residual in meters.
+func refine(p bluetooth.Point, obs []observation) (bluetooth.Point, float64) {
+	for it := 0; it < refineIterations; it++ {
+		// Normal equations J^T W J dp = -J^T W f
+		var a11, a12, a22, b1, b2 float64
+		for _, o := range obs {
+			dx, dy := p.X-o.at.X, p.Y-o.at.Y
+			dist := math.Max(math.Hypot(dx, dy), 1e-6)
+			jx, jy := dx/dist, dy/dist
+			f := dist - o.rng
+			w := 1 / (o.rng * o.rng)
+			a11 += w * jx * jx
+			a12 += w * jx * jy
+			a22 += w * jy * jy
+			b1 -= w * jx * f
+			b2 -= w * jy * f
+		}
+		det := a11*a22 - a12*a12
+		if math.Abs(det) < 1e-12 {
+			break
+		}
+		stepX := (a22*b1 - a12*b2) / det
+		stepY := (a11*b2 - a12*b1) / det
+		p.X += stepX
+		p.Y += stepY
+		if math.Hypot(stepX, stepY) < 1e-3 {
+			break
+		}
+	}
+
+	var sum, wsum float64
+	for _, o := range obs {
+		f := math.Hypot(p.X-o.at.X, p.Y-o.at.Y) - o.rng
+		w := 1 / (o.rng * o.rng)
+		sum += w * f * f
+		wsum += w
+	}
+	return p, math.Sqrt(sum / wsum)
+}
@@ -0,0 +1,204 @@
+package localization
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"ble-radar.klederson.com/internal/bluetooth"
+	"ble-radar.klederson.com/internal/config"
+)
+
+// square is four adapters on the corners of a 6 m square around the radar.
+var square = map[string]bluetooth.Point{
+	"hci0": {X: -3, Y: -3},
+	"hci1": {X: 3, Y: -3},
+	"hci2": {X: 3, Y: 3},
+	"hci3": {X: -3, Y: 3},
+}
+
+// heard returns a device at pos as the given anchors hear it, with RSSI
+// following the path loss model exactly plus an optional per-anchor offset.
+func heard(anchors map[string]bluetooth.Point, pos bluetooth.Point, offset map[string]float64) *bluetooth.Device {
+	t0 := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	d := &bluetooth.Device{RefPower: -59, PathLossExp: 2, Adapters: map[string]bluetooth.AdapterReading{}}
+	for id, a := range anchors {
+		rssi := -59 - 20*math.Log10(math.Hypot(pos.X-a.X, pos.Y-a.Y)) + offset[id]
+		d.Adapters[id] = bluetooth.AdapterReading{RSSI: rssi, RawRSSI: rssi, LastSeen: t0}
+	}
+	return d
+}
+
+// only returns the anchors named by ids.
+func only(ids ...string) map[string]bluetooth.Point {
+	out := map[string]bluetooth.Point{}
+	for _, id := range ids {
+		out[id] = square[id]
+	}
+	return out
+}
+
+func TestLocateBearing(t *testing.T) {
+	tests := []struct {
+		name    string
+		anchors map[string]bluetooth.Point
+		pos     bluetooth.Point
+		angle   float64 // Degrees clockwise from north
+	}{
+		{"four anchors north", square, bluetooth.Point{X: 0, Y: 10}, 0},
+		{"four anchors north-east", square, bluetooth.Point{X: 6, Y: 8}, 36.87},
+		{"four anchors south-east", square, bluetooth.Point{X: 5, Y: -5}, 135},
+		{"four anchors west", square, bluetooth.Point{X: -8, Y: -2}, 255.96},
+		{"three anchors east", only("hci0", "hci1", "hci2"), bluetooth.Point{X: 9, Y: 0}, 90},
+		{"three anchors south-west", only("hci0", "hci1", "hci3"), bluetooth.Point{X: -4, Y: -7}, 209.74},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc, ok := New(tt.anchors).Locate(heard(tt.anchors, tt.pos, nil))
+			if !ok {
+				t.Fatal("not located")
+			}
+			deg := loc.Angle * 180 / math.Pi
+			if diff := math.Mod(deg-tt.angle+540, 360) - 180; math.Abs(diff) > 1 {
+				t.Errorf("angle = %.2f°, want %.2f°", deg, tt.angle)
+			}
+			if want := math.Hypot(tt.pos.X, tt.pos.Y); math.Abs(loc.Distance-want) > 0.1 {
+				t.Errorf("distance = %.2f, want %.2f", loc.Distance, want)
+			}
+			if loc.Confidence <= 0 || loc.Confidence > 1 {
+				t.Errorf("confidence = %v, want in (0, 1]", loc.Confidence)
+			}
+		})
+	}
+}
+
+func TestLocateConfidence(t *testing.T) {
+	l := New(square)
+	confidence := func(pos bluetooth.Point, offset map[string]float64) float64 {
+		t.Helper()
+		loc, ok := l.Locate(heard(square, pos, offset))
+		if !ok {
+			t.Fatalf("device at %v not located", pos)
+		}
+		return loc.Confidence
+	}
+
+	// A near device spreads the RSSI more across the anchors than a far one.
+	near := confidence(bluetooth.Point{X: 4, Y: 5}, nil)
+	far := confidence(bluetooth.Point{X: 40, Y: 50}, nil)
+	if near <= far {
+		t.Errorf("near %v <= far %v", near, far)
+	}
+
+	// Ranges that disagree with any single position lower it.
+	noisy := confidence(bluetooth.Point{X: 4, Y: 5}, map[string]float64{"hci0": 8, "hci2": -8})
+	if near <= noisy {
+		t.Errorf("consistent %v <= inconsistent %v", near, noisy)
+	}
+}
+
+func TestLocateAmbiguous(t *testing.T) {
+	pos := bluetooth.Point{X: 2, Y: 6}
+	tests := []struct {
+		name    string
+		anchors map[string]bluetooth.Point
+		heard   map[string]bluetooth.Point // Anchors that report the device
+	}{
+		{"one anchor", square, only("hci0")},
+		{"two anchors", square, only("hci0", "hci2")},
+		{"unknown adapters", only("hci0", "hci1"), square},
+		{"anchors in a row", map[string]bluetooth.Point{
+			"hci0": {X: -4, Y: 0}, "hci1": {X: 0, Y: 0}, "hci2": {X: 4, Y: 0.2},
+		}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			heardBy := tt.heard
+			if heardBy == nil {
+				heardBy = tt.anchors
+			}
+			if loc, ok := New(tt.anchors).Locate(heard(heardBy, pos, nil)); ok {
+				t.Errorf("located at %+v", loc)
+			}
+		})
+	}
+}
+
+func TestObservations(t *testing.T) {
+	t0 := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	reading := func(rssi float64, age time.Duration) bluetooth.AdapterReading {
+		return bluetooth.AdapterReading{RSSI: rssi, LastSeen: t0.Add(-age)}
+	}
+	tests := []struct {
+		name     string
+		adapters map[string]bluetooth.AdapterReading
+		want     []string // Adapter IDs kept
+	}{
+		{
+			name: "all fresh",
+			adapters: map[string]bluetooth.AdapterReading{
+				"hci2": reading(-70, 0), "hci0": reading(-60, time.Second), "hci1": reading(-65, 9*time.Second),
+			},
+			want: []string{"hci0", "hci1", "hci2"},
+		},
+		{
+			name: "stale reading dropped",
+			adapters: map[string]bluetooth.AdapterReading{
+				"hci0": reading(-60, 0), "hci1": reading(-65, 11*time.Second), "hci2": reading(-70, 2*time.Second),
+				"hci3": reading(-72, 3*time.Second),
+			},
+			want: []string{"hci0", "hci2", "hci3"},
+		},
+		{
+			name: "age is relative to the newest reading",
+			adapters: map[string]bluetooth.AdapterReading{
+				"hci0": reading(-60, time.Hour), "hci1": reading(-65, time.Hour+5*time.Second),
+			},
+			want: []string{"hci0", "hci1"},
+		},
+		{
+			name: "unknown adapter ignored",
+			adapters: map[string]bluetooth.AdapterReading{
+				"hci0": reading(-60, 0), "hci9": reading(-50, 0),
+			},
+			want: []string{"hci0"},
+		},
+		{name: "no readings"},
+	}
+	l := New(square)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, o := range l.observations(&bluetooth.Device{RefPower: -59, PathLossExp: 2, Adapters: tt.adapters}) {
+				got = append(got, o.id)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("observations from %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// A stale third anchor leaves two, which cannot place the device.
+	d := heard(only("hci0", "hci1", "hci2"), bluetooth.Point{X: 2, Y: 6}, nil)
+	r := d.Adapters["hci1"]
+	r.LastSeen = r.LastSeen.Add(-staleAfter - time.Second)
+	d.Adapters["hci1"] = r
+	if loc, ok := l.Locate(d); ok {
+		t.Errorf("located from two fresh anchors at %+v", loc)
+	}
+}
+
+func TestFromConfig(t *testing.T) {
+	if _, ok := FromConfig(config.Localization{Anchors: map[string]config.Position{"hci0": {}, "hci1": {X: 1}}}); ok {
+		t.Error("built a locator from two anchors")
+	}
+	l, ok := FromConfig(config.Localization{Anchors: map[string]config.Position{"hci0": {}, "hci1": {X: 1}, "hci2": {Y: 2}}})
+	if !ok {
+		t.Fatal("no locator from three anchors")
+	}
+	want := map[string]bluetooth.Point{"hci0": {}, "hci1": {X: 1}, "hci2": {Y: 2}}
+	if !reflect.DeepEqual(l.anchors, want) {
+		t.Errorf("anchors = %v, want %v", l.anchors, want)
+	}
+}
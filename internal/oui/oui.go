@@ -0,0 +1,241 @@
+// Package oui maps MAC addresses to the organization the IEEE assigned them
+// to, from the MA-L, MA-M and MA-S (and legacy IAB) registries.
+package oui
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// embedded is the database compiled into the binary, in the form Write
+// produces. It is built from the IEEE MA-L registry (oui.csv) only; refresh
+// it, adding mam.csv and oui36.csv where available, with
+// `ble-radar oui update --output internal/oui/oui.csv.gz oui.csv mam.csv oui36.csv`.
+//
+//go:embed oui.csv.gz
+var embedded []byte
+
+// registryBits is the assignment length of each IEEE registry, in bits.
+var registryBits = map[string]int{
+	"MA-L": 24,
+	"MA-M": 28,
+	"MA-S": 36,
+	"IAB":  36,
+}
+
+// registryNames lists the registries by assignment length, longest first,
+// which is also the lookup order.
+var registryNames = []string{"MA-S", "MA-M", "MA-L"}
+
+// DB holds OUI assignments keyed by prefix length and prefix.
+type DB struct {
+	prefixes map[int]map[uint64]string
+}
+
+// New returns an empty database.
+func New() *DB {
+	return &DB{prefixes: make(map[int]map[uint64]string)}
+}
+
+// Len returns the number of assignments.
+func (db *DB) Len() int {
+	n := 0
+	for _, m := range db.prefixes {
+		n += len(m)
+	}
+	return n
+}
+
+// Lookup returns the organization a MAC address was assigned to. Locally
+// administered and multicast addresses are not assigned by the IEEE and
+// yield "", as do unknown prefixes. db may be nil.
+func (db *DB) Lookup(mac string) string {
+	if db == nil {
+		return ""
+	}
+	addr, ok := parseMAC(mac)
+	if !ok {
+		return ""
+	}
+	if first := byte(addr >> 40); first&0x02 != 0 || first&0x01 != 0 {
+		return ""
+	}
+	for _, reg := range registryNames {
+		bits := registryBits[reg]
+		if name, ok := db.prefixes[bits][addr>>(48-bits)]; ok {
+			return name
+		}
+	}
+	return ""
+}
+
+// parseMAC reads a 48-bit address written with or without ':', '-' or '.'
+// separators.
+func parseMAC(mac string) (uint64, bool) {
+	hex := strings.NewReplacer(":", "", "-", "", ".", "").Replace(mac)
+	if len(hex) != 12 {
+		return 0, false
+	}
+	v, err := strconv.ParseUint(hex, 16, 64)
+	return v, err == nil
+}
+
+// Read adds the assignments of an IEEE registry CSV (oui.csv, mam.csv,
+// oui36.csv or iab.csv, with a header row of Registry, Assignment,
+// Organization Name, ...) or of a file written by Write, gzip-compressed or
+// not.
+func (db *DB) Read(r io.Reader) error {
+	br := bufio.NewReader(r)
+	if magic, _ := br.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return err
+		}
+		defer zr.Close()
+		r = zr
+	} else {
+		r = br
+	}
+
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	for line := 1; ; line++ {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if len(rec) < 3 || rec[0] == "Registry" {
+			continue
+		}
+		bits, ok := registryBits[rec[0]]
+		if !ok {
+			continue // CID and other non-address registries
+		}
+		assignment := strings.TrimSpace(rec[1])
+		prefix, err := strconv.ParseUint(assignment, 16, 64)
+		if err != nil || len(assignment)*4 != bits {
+			return fmt.Errorf("line %d: invalid %s assignment %q", line, rec[0], assignment)
+		}
+		if db.prefixes[bits] == nil {
+			db.prefixes[bits] = make(map[uint64]string)
+		}
+		db.prefixes[bits][prefix] = shortName(rec[2])
+	}
+}
+
+// Write stores the database as gzip-compressed CSV in the IEEE column
+// layout, without addresses, sorted by registry and assignment.
+func (db *DB) Write(w io.Writer) error {
+	zw, err := gzip.NewWriterLevel(w, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(zw)
+	if err := cw.Write([]string{"Registry", "Assignment", "Organization Name"}); err != nil {
+		return err
+	}
+	for i := len(registryNames) - 1; i >= 0; i-- {
+		reg := registryNames[i]
+		bits := registryBits[reg]
+		prefixes := make([]uint64, 0, len(db.prefixes[bits]))
+		for p := range db.prefixes[bits] {
+			prefixes = append(prefixes, p)
+		}
+		sort.Slice(prefixes, func(a, b int) bool { return prefixes[a] < prefixes[b] })
+		for _, p := range prefixes {
+			if err := cw.Write([]string{reg, fmt.Sprintf("%0*X", bits/4, p), db.prefixes[bits][p]}); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// legalSuffixes are dropped from organization names, case-insensitively,
+// so that vendors fit the device list.
+var legalSuffixes = []string{
+	"co.,ltd.", "co.,ltd", "co., ltd.", "co., ltd", "co. ltd.", "co ltd",
+	"inc.", "inc", "incorporated", "ltd.", "ltd", "limited", "llc",
+	"corporation", "corp.", "corp", "gmbh & co. kg", "gmbh", "ag", "ab", "a/s", "b.v.", "bv",
+	"s.a.", "sa", "s.p.a.", "oy", "pty", "plc", "co.",
+}
+
+// shortName trims legal suffixes and separators from an organization name,
+// e.g. "TP-LINK TECHNOLOGIES CO.,LTD." becomes "TP-LINK TECHNOLOGIES".
+func shortName(name string) string {
+	name = strings.TrimSpace(name)
+	for {
+		trimmed := strings.TrimRight(name, " ,")
+		lower := strings.ToLower(trimmed)
+		for _, s := range legalSuffixes {
+			if strings.HasSuffix(lower, " "+s) || strings.HasSuffix(lower, ","+s) {
+				trimmed = trimmed[:len(trimmed)-len(s)-1]
+				break
+			}
+		}
+		trimmed = strings.TrimRight(trimmed, " ,")
+		if trimmed == name || trimmed == "" {
+			return name
+		}
+		name = trimmed
+	}
+}
+
+// DefaultPath returns the location `ble-radar oui update` writes to in the
+// user's config directory. A database there replaces the embedded one.
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "oui.csv.gz"
+	}
+	return filepath.Join(dir, "ble-radar", "oui.csv.gz")
+}
+
+// Load reads the database at path, or the embedded one if path does not
+// exist.
+func Load(path string) (*DB, error) {
+	db := New()
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return db, db.Read(bytes.NewReader(embedded))
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if err := db.Read(f); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return db, nil
+}
+
+// Save writes the database to path, creating parent directories.
+func (db *DB) Save(path string) error {
+	var buf bytes.Buffer
+	if err := db.Write(&buf); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
@@ -0,0 +1,138 @@
+package oui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// registries is an excerpt of the IEEE oui.csv, mam.csv and oui36.csv
+// layouts, with nested MA-L, MA-M and MA-S assignments.
+const registries = `Registry,Assignment,Organization Name,Organization Address
+MA-L,70B3D5,IEEE Registration Authority,445 Hoes Lane Piscataway NJ US 08554
+MA-L,000393,"Apple, Inc.",1 Infinite Loop Cupertino CA US 95014
+MA-M,70B3D5F,"Acme Sensors Co.,Ltd.",Somewhere
+MA-S,70B3D5F2A,Tiny Widgets GmbH,Elsewhere
+IAB,0050C2123,Legacy Instruments Inc.,Nowhere
+CID,0A1B2C,Not An Address Registry,
+`
+
+func TestLookup(t *testing.T) {
+	db := New()
+	if err := db.Read(strings.NewReader(registries)); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		mac  string
+		want string
+	}{
+		{"00:03:93:12:34:56", "Apple"},
+		{"00-03-93-12-34-56", "Apple"},
+		{"0003.9312.3456", "Apple"},
+		{"70:B3:D5:F2:A1:23", "Tiny Widgets"},                // MA-S wins over MA-M and MA-L
+		{"70:B3:D5:F1:23:45", "Acme Sensors"},                // MA-M wins over MA-L
+		{"70:B3:D5:01:23:45", "IEEE Registration Authority"}, // Only MA-L
+		{"00:50:C2:12:3F:FF", "Legacy Instruments"},          // IAB is a 36-bit block
+		{"0A:1B:2C:00:00:01", ""},                            // CID assignments are not addresses
+		{"00:03:94:12:34:56", ""},
+		{"02:03:93:12:34:56", ""}, // Locally administered
+		{"01:03:93:12:34:56", ""}, // Multicast
+		{"03:03:93:12:34:56", ""},
+		{"00:03:93:12:34", ""},
+		{"not a mac", ""},
+	}
+	for _, tt := range tests {
+		if got := db.Lookup(tt.mac); got != tt.want {
+			t.Errorf("Lookup(%q) = %q, want %q", tt.mac, got, tt.want)
+		}
+	}
+
+	var none *DB
+	if got := none.Lookup("00:03:93:12:34:56"); got != "" {
+		t.Errorf("nil DB Lookup = %q, want empty", got)
+	}
+}
+
+func TestReadInvalidAssignment(t *testing.T) {
+	for _, csv := range []string{
+		"MA-L,0003,Short\n",
+		"MA-M,000393,Long\n",
+		"MA-L,00039G,Not hex\n",
+	} {
+		if err := New().Read(strings.NewReader(csv)); err == nil {
+			t.Errorf("Read(%q) succeeded, want an error", csv)
+		}
+	}
+}
+
+func TestShortName(t *testing.T) {
+	tests := []struct {
+		name, want string
+	}{
+		{"Apple, Inc.", "Apple"},
+		{"TP-LINK TECHNOLOGIES CO.,LTD.", "TP-LINK TECHNOLOGIES"},
+		{"Samsung Electronics Co.,Ltd", "Samsung Electronics"},
+		{"Shenzhen Foo Co., Ltd.", "Shenzhen Foo"},
+		{"Robert Bosch GmbH", "Robert Bosch"},
+		{"Miele & Cie. GmbH & Co. KG", "Miele & Cie."},
+		{"Sony Corporation", "Sony"},
+		{"Nordic Semiconductor ASA", "Nordic Semiconductor ASA"},
+		{"Espressif Inc.", "Espressif"},
+		{"  Raspberry Pi Trading Ltd  ", "Raspberry Pi Trading"},
+		{"Inc.", "Inc."}, // Nothing left to keep
+		{"XEROX CORPORATION", "XEROX"},
+	}
+	for _, tt := range tests {
+		if got := shortName(tt.name); got != tt.want {
+			t.Errorf("shortName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWriteRead(t *testing.T) {
+	db := New()
+	if err := db.Read(strings.NewReader(registries)); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := db.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if b := buf.Bytes(); len(b) < 2 || b[0] != 0x1f || b[1] != 0x8b {
+		t.Fatal("Write did not gzip its output")
+	}
+
+	back := New()
+	if err := back.Read(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if back.Len() != db.Len() {
+		t.Errorf("read back %d assignments, want %d", back.Len(), db.Len())
+	}
+	for bits, prefixes := range db.prefixes {
+		for p, name := range prefixes {
+			if got := back.prefixes[bits][p]; got != name {
+				t.Errorf("%d-bit prefix %X = %q after round trip, want %q", bits, p, got, name)
+			}
+		}
+	}
+}
+
+func TestEmbedded(t *testing.T) {
+	db := New()
+	if err := db.Read(bytes.NewReader(embedded)); err != nil {
+		t.Fatal(err)
+	}
+	if n := db.Len(); n < 30000 {
+		t.Errorf("embedded database has %d assignments, want the full MA-L registry", n)
+	}
+	for mac, want := range map[string]string{
+		"00:03:93:00:00:01": "Apple",
+		"B8:27:EB:00:00:01": "Raspberry Pi Foundation",
+		"00:50:F2:00:00:01": "MICROSOFT",
+	} {
+		if got := db.Lookup(mac); got != want {
+			t.Errorf("embedded Lookup(%s) = %q, want %q", mac, got, want)
+		}
+	}
+}
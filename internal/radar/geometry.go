@@ -1,24 +1,20 @@
 package radar
 
-import (
-	"math"
-
-	"ble-radar.klederson.com/internal/config"
-)
+import "math"
 
 // CellDistance computes the distance from a cell to the radar center,
 // accounting for terminal aspect ratio.
-func CellDistance(col, row, centerX, centerY int) float64 {
+func CellDistance(col, row, centerX, centerY int, aspect float64) float64 {
 	dx := float64(col - centerX)
-	dy := float64(row-centerY) / config.AspectRatio
+	dy := float64(row-centerY) / aspect
 	return math.Sqrt(dx*dx + dy*dy)
 }
 
 // CellAngle computes the angle from center to a cell.
 // Returns radians in [0, 2π), where 0=north, increasing clockwise.
-func CellAngle(col, row, centerX, centerY int) float64 {
+func CellAngle(col, row, centerX, centerY int, aspect float64) float64 {
 	dx := float64(col - centerX)
-	dy := float64(row-centerY) / config.AspectRatio
+	dy := float64(row-centerY) / aspect
 	angle := math.Atan2(dx, -dy) // 0=north, clockwise
 	if angle < 0 {
 		angle += 2 * math.Pi
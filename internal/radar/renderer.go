@@ -4,10 +4,10 @@ import (
 	"crypto/sha256"
 	"fmt"
 	"math"
+	"sort"
 	"strings"
 
 	"ble-radar.klederson.com/internal/bluetooth"
-	"ble-radar.klederson.com/internal/config"
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -33,6 +33,8 @@ var (
 	styleLabelCls = lipgloss.NewStyle().Foreground(colorDeviceCls)
 	styleLabelWiFi = lipgloss.NewStyle().Foreground(colorDeviceWiFi)
 	styleLabelDim = lipgloss.NewStyle().Foreground(colorLabelDim)
+	styleRingLabel = lipgloss.NewStyle().Foreground(colorMid)
+	styleBearing   = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFAA00")).Bold(true)
 )
 
 const maxLabelLen = 8
@@ -43,28 +45,41 @@ type devPos struct {
 	label    string
 	labelCol int
 	labelRow int
+	hashed   bool // Angle is the MAC-hash fallback while localization is on
+}
+
+// Settings controls the radar scale and geometry.
+type Settings struct {
+	MaxRange    float64 // Meters at the outer ring
+	AspectRatio float64 // Terminal cell width/height correction
+	RingCount   int     // Number of concentric rings
+	Localized   bool    // Bearings are estimated; mark devices that still use the MAC hash
+
+	ShowBearing bool    // Draw a ray from the center along Bearing
+	Bearing     float64 // Radians clockwise from north
 }
 
 // Render produces the complete radar display as a styled string.
-func Render(width, height int, devices []*bluetooth.Device, sweep *Sweep) string {
+func Render(width, height int, devices []*bluetooth.Device, sweep *Sweep, set Settings) string {
 	if width < 10 || height < 5 {
 		return ""
 	}
 
 	centerX := width / 2
 	centerY := height / 2
-	radius := float64(min(centerX-1, int(float64(centerY-1)/config.AspectRatio)))
+	aspect := set.AspectRatio
+	radius := float64(min(centerX-1, int(float64(centerY-1)/aspect)))
 	if radius < 3 {
 		radius = 3
 	}
 
-	ringRadii := make([]float64, config.RingCount)
+	ringRadii := make([]float64, set.RingCount)
 	for i := range ringRadii {
-		ringRadii[i] = radius * float64(i+1) / float64(config.RingCount)
+		ringRadii[i] = radius * float64(i+1) / float64(set.RingCount)
 	}
 
 	// Pre-compute device positions and labels with collision avoidance
-	dps := buildDevicePositions(devices, centerX, centerY, radius, width)
+	dps := buildDevicePositions(devices, centerX, centerY, radius, width, set)
 
 	// Build a lookup map for label cells: key = row*width+col → index into dps + char offset
 	type labelCell struct {
@@ -82,6 +97,8 @@ func Render(width, height int, devices []*bluetooth.Device, sweep *Sweep) string
 		}
 	}
 
+	ringLabels := buildRingLabels(ringRadii, set, centerX, centerY, width, dps)
+
 	var sb strings.Builder
 	for row := 0; row < height; row++ {
 		for col := 0; col < width; col++ {
@@ -90,10 +107,14 @@ func Render(width, height int, devices []*bluetooth.Device, sweep *Sweep) string
 			if lc, ok := labelMap[key]; ok {
 				dp := dps[lc.dpIdx]
 				ch := dp.label[lc.charIdx]
-				sb.WriteString(styleLabelFor(dp.dev, sweep, col, row, centerX, centerY, ch))
+				sb.WriteString(styleLabelFor(dp.dev, sweep, col, row, centerX, centerY, aspect, ch))
+				continue
+			}
+			if ch, ok := ringLabels[key]; ok {
+				sb.WriteString(styleRingLabel.Render(string(ch)))
 				continue
 			}
-			sb.WriteString(renderCell(col, row, centerX, centerY, radius, ringRadii, sweep, dps))
+			sb.WriteString(renderCell(col, row, centerX, centerY, set, radius, ringRadii, sweep, dps))
 		}
 		if row < height-1 {
 			sb.WriteByte('\n')
@@ -104,7 +125,7 @@ func Render(width, height int, devices []*bluetooth.Device, sweep *Sweep) string
 }
 
 // buildDevicePositions computes positions and resolves label collisions.
-func buildDevicePositions(devices []*bluetooth.Device, centerX, centerY int, radius float64, width int) []devPos {
+func buildDevicePositions(devices []*bluetooth.Device, centerX, centerY int, radius float64, width int, set Settings) []devPos {
 	dps := make([]devPos, 0, len(devices))
 
 	// Track occupied row segments: map[row] → list of (startCol, endCol)
@@ -112,9 +133,9 @@ func buildDevicePositions(devices []*bluetooth.Device, centerX, centerY int, rad
 	occupied := make(map[int][]segment)
 
 	for _, d := range devices {
-		devRadius := MetersToRadius(d.Distance, config.MaxRange, radius)
+		devRadius := MetersToRadius(d.Distance, set.MaxRange, radius)
 		dc := centerX + int(math.Round(devRadius*math.Sin(d.Angle)))
-		dr := centerY - int(math.Round(devRadius*math.Cos(d.Angle)*config.AspectRatio))
+		dr := centerY - int(math.Round(devRadius*math.Cos(d.Angle)*set.AspectRatio))
 
 		label := deviceCallsign(d)
 
@@ -175,6 +196,7 @@ func buildDevicePositions(devices []*bluetooth.Device, centerX, centerY int, rad
 			label:    label,
 			labelCol: lc,
 			labelRow: lr,
+			hashed:   set.Localized && !d.Located && !d.Pinned,
 		}
 		dps = append(dps, dp)
 
@@ -190,9 +212,34 @@ func buildDevicePositions(devices []*bluetooth.Device, centerX, centerY int, rad
 	return dps
 }
 
+// buildRingLabels places the distance of each ring just right of the north
+// axis, where it crosses the ring. Cells under a device symbol are skipped.
+func buildRingLabels(ringRadii []float64, set Settings, centerX, centerY, width int, dps []devPos) map[int]byte {
+	devCells := make(map[int]bool, len(dps))
+	for _, dp := range dps {
+		devCells[dp.row*width+dp.col] = true
+	}
+
+	labels := make(map[int]byte)
+	for i, r := range ringRadii {
+		row := centerY - int(math.Round(r*set.AspectRatio))
+		if row < 0 {
+			continue
+		}
+		text := FormatMeters(set.MaxRange * float64(i+1) / float64(len(ringRadii)))
+		for ci := 0; ci < len(text); ci++ {
+			col := centerX + 2 + ci
+			if col >= width || devCells[row*width+col] {
+				continue
+			}
+			labels[row*width+col] = text[ci]
+		}
+	}
+	return labels
+}
+
 func deviceCallsign(d *bluetooth.Device) string {
-	if d.Name != "" {
-		name := d.Name
+	if name := d.KnownName(); name != "" {
 		if len(name) > maxLabelLen {
 			name = name[:maxLabelLen]
 		}
@@ -202,12 +249,12 @@ func deviceCallsign(d *bluetooth.Device) string {
 	return fmt.Sprintf("#%02X%X", h[0], h[1]&0x0F)
 }
 
-func styleLabelFor(d *bluetooth.Device, sweep *Sweep, col, row, centerX, centerY int, ch byte) string {
-	intensity := sweep.Intensity(CellAngle(col, row, centerX, centerY))
+func styleLabelFor(d *bluetooth.Device, sweep *Sweep, col, row, centerX, centerY int, aspect float64, ch byte) string {
+	intensity := sweep.Intensity(CellAngle(col, row, centerX, centerY, aspect))
 	s := string(ch)
 	brightSty := lipgloss.NewStyle().Foreground(colorBright).Bold(true)
 
-	if d.Name == "" {
+	if d.KnownName() == "" {
 		if intensity > 0.5 {
 			return lipgloss.NewStyle().Foreground(lipgloss.Color("#00CC33")).Render(s)
 		}
@@ -233,12 +280,15 @@ func styleLabelFor(d *bluetooth.Device, sweep *Sweep, col, row, centerX, centerY
 	}
 }
 
-func renderCell(col, row, centerX, centerY int, radius float64, ringRadii []float64, sweep *Sweep, devPositions []devPos) string {
-	dist := CellDistance(col, row, centerX, centerY)
-	angle := CellAngle(col, row, centerX, centerY)
+func renderCell(col, row, centerX, centerY int, set Settings, radius float64, ringRadii []float64, sweep *Sweep, devPositions []devPos) string {
+	dist := CellDistance(col, row, centerX, centerY, set.AspectRatio)
+	angle := CellAngle(col, row, centerX, centerY, set.AspectRatio)
 
 	for _, dp := range devPositions {
 		if col == dp.col && row == dp.row {
+			if dp.hashed {
+				return renderUnlocated(dp.dev)
+			}
 			return renderDevice(dp.dev, sweep, angle)
 		}
 	}
@@ -251,6 +301,11 @@ func renderCell(col, row, centerX, centerY int, radius float64, ringRadii []floa
 		return styleCenter.Render("+")
 	}
 
+	// Pinned bearing ray: cells within half a cell of the line
+	if set.ShowBearing && dist >= 1 && dist <= radius && AngleDiff(angle, set.Bearing)*dist < 0.5 {
+		return styleBearing.Render(":")
+	}
+
 	if col == centerX && dist <= radius {
 		return renderSweepChar('|', sweep, angle)
 	}
@@ -278,10 +333,11 @@ func renderDevice(d *bluetooth.Device, sweep *Sweep, cellAngle float64) string {
 
 	switch d.Type {
 	case bluetooth.DeviceTypeClassic:
+		glyph := d.ClassOfDevice.Glyph()
 		if intensity > 0.5 {
-			return brightSty.Render("B")
+			return brightSty.Render(glyph)
 		}
-		return styleClassDev.Render("B")
+		return styleClassDev.Render(glyph)
 	case bluetooth.DeviceTypeWiFi:
 		if intensity > 0.5 {
 			return brightSty.Render("W")
@@ -295,6 +351,19 @@ func renderDevice(d *bluetooth.Device, sweep *Sweep, cellAngle float64) string {
 	}
 }
 
+// renderUnlocated draws a device whose bearing is unknown as a plain "?"
+// in its type color, without the sweep highlight.
+func renderUnlocated(d *bluetooth.Device) string {
+	switch d.Type {
+	case bluetooth.DeviceTypeClassic:
+		return styleLegClass.Render("?")
+	case bluetooth.DeviceTypeWiFi:
+		return styleLegWiFi.Render("?")
+	default:
+		return styleLegBLE.Render("?")
+	}
+}
+
 func renderSweepChar(ch rune, sweep *Sweep, angle float64) string {
 	intensity := sweep.Intensity(angle)
 	color := sweepColor(intensity)
@@ -329,20 +398,68 @@ func sweepColor(intensity float64) string {
 	return "#005511"
 }
 
-// RenderLegend produces the radar legend line.
-func RenderLegend(width int) string {
+// RenderLegend produces the radar legend. With localization enabled it
+// explains the marker for devices without an estimated bearing. When
+// devices holds classic devices drawn by their device class, a second line
+// explains the class glyphs in use.
+func RenderLegend(width int, localized bool, devices []*bluetooth.Device) string {
 	legend := "   " +
 		styleLegBLE.Render("* BLE") +
 		"  " +
 		styleLegClass.Render("B Classic") +
 		"  " +
 		styleLegWiFi.Render("W WiFi")
+	if localized {
+		legend += "  " + styleDot.Render("? no bearing")
+	}
+
+	legend = centerLine(width, legend)
+	if classes := renderClassLegend(width, devices); classes != "" {
+		legend += "\n" + centerLine(width, classes)
+	}
+	return legend
+}
+
+// renderClassLegend lists the classic device glyphs on the radar other
+// than the generic "B", e.g. "A Audio/Video  P Phone", as far as width
+// allows. It returns "" when there are none.
+func renderClassLegend(width int, devices []*bluetooth.Device) string {
+	seen := make(map[bluetooth.MajorClass]bool)
+	var majors []bluetooth.MajorClass
+	for _, d := range devices {
+		if d.Type != bluetooth.DeviceTypeClassic || d.ClassOfDevice.Glyph() == "B" {
+			continue
+		}
+		if m := d.ClassOfDevice.Major(); !seen[m] {
+			seen[m] = true
+			majors = append(majors, m)
+		}
+	}
+	sort.Slice(majors, func(i, j int) bool { return majors[i] < majors[j] })
+
+	var parts []string
+	used := 3
+	for _, m := range majors {
+		part := m.Glyph() + " " + m.String()
+		if used+len(part) > width {
+			break
+		}
+		parts = append(parts, part)
+		used += len(part) + 2
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "   " + styleLegClass.Render(strings.Join(parts, "  "))
+}
 
-	pad := (width - lipgloss.Width(legend)) / 2
+// centerLine pads line on the left to center it in width.
+func centerLine(width int, line string) string {
+	pad := (width - lipgloss.Width(line)) / 2
 	if pad < 0 {
 		pad = 0
 	}
-	return strings.Repeat(" ", pad) + legend
+	return strings.Repeat(" ", pad) + line
 }
 
 func min(a, b int) int {
@@ -0,0 +1,54 @@
+package radar
+
+import (
+	"fmt"
+	"math"
+
+	"ble-radar.klederson.com/internal/bluetooth"
+)
+
+// RangePresets are the zoom steps in meters, from closest to farthest.
+var RangePresets = []float64{2, 5, 10, 20, 30, 50, 100}
+
+// ZoomIn returns the next preset smaller than r, or the smallest preset.
+func ZoomIn(r float64) float64 {
+	for i := len(RangePresets) - 1; i >= 0; i-- {
+		if RangePresets[i] < r {
+			return RangePresets[i]
+		}
+	}
+	return RangePresets[0]
+}
+
+// ZoomOut returns the next preset larger than r, or the largest preset.
+func ZoomOut(r float64) float64 {
+	for _, p := range RangePresets {
+		if p > r {
+			return p
+		}
+	}
+	return RangePresets[len(RangePresets)-1]
+}
+
+// FitRange returns the smallest preset that contains every device, leaving
+// a little margin so the farthest one does not sit on the outer ring.
+func FitRange(devices []*bluetooth.Device) float64 {
+	farthest := 0.0
+	for _, d := range devices {
+		farthest = math.Max(farthest, d.Distance)
+	}
+	for _, p := range RangePresets {
+		if farthest*1.1 <= p {
+			return p
+		}
+	}
+	return RangePresets[len(RangePresets)-1]
+}
+
+// FormatMeters formats a ring distance compactly, e.g. "7.5m" or "25m".
+func FormatMeters(m float64) string {
+	if m == math.Trunc(m) {
+		return fmt.Sprintf("%.0fm", m)
+	}
+	return fmt.Sprintf("%.1fm", m)
+}
@@ -3,28 +3,30 @@ package radar
 import (
 	"math"
 	"time"
-
-	"ble-radar.klederson.com/internal/config"
 )
 
 // Sweep manages the rotating sweep line state.
 type Sweep struct {
 	Angle     float64 // Current angle in radians [0, 2π)
 	StartTime time.Time
+	SpeedRPM  float64 // Rotations per minute
+	TrailDeg  float64 // Trailing glow in degrees
 }
 
 // NewSweep creates a new sweep starting at 0 degrees (north).
-func NewSweep() *Sweep {
+func NewSweep(speedRPM, trailDeg float64) *Sweep {
 	return &Sweep{
 		Angle:     0,
 		StartTime: time.Now(),
+		SpeedRPM:  speedRPM,
+		TrailDeg:  trailDeg,
 	}
 }
 
 // Update advances the sweep angle based on elapsed time.
 func (s *Sweep) Update() {
 	elapsed := time.Since(s.StartTime).Seconds()
-	rps := float64(config.SweepSpeedRPM) / 60.0 // rotations per second
+	rps := s.SpeedRPM / 60.0 // rotations per second
 	s.Angle = math.Mod(elapsed*rps*2*math.Pi, 2*math.Pi)
 }
 
@@ -34,7 +36,7 @@ func (s *Sweep) Degrees() float64 {
 }
 
 // Intensity returns the glow intensity [0, 1] for a given cell angle.
-// The sweep has a trailing glow of SweepTrailDeg degrees.
+// The sweep has a trailing glow of TrailDeg degrees.
 // Returns 0 if the cell is outside the sweep trail.
 func (s *Sweep) Intensity(cellAngle float64) float64 {
 	// Calculate how far behind the sweep this angle is
@@ -43,7 +45,7 @@ func (s *Sweep) Intensity(cellAngle float64) float64 {
 		diff += 2 * math.Pi
 	}
 
-	trailRad := config.SweepTrailDeg * math.Pi / 180.0
+	trailRad := s.TrailDeg * math.Pi / 180.0
 	if diff > trailRad {
 		return 0
 	}
@@ -0,0 +1,155 @@
+package survey
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Field identifies an input of the position form.
+type Field int
+
+const (
+	FieldName Field = iota
+	FieldX
+	FieldY
+	fieldCount
+)
+
+// Form walks the user through recording observations. It waits for a
+// position name and coordinates, collects raw RSSI samples there, then
+// waits for the next position.
+type Form struct {
+	Survey *Survey
+	Field  Field // Input being edited
+	Inputs [fieldCount]string
+
+	target   int
+	sampling *Observation
+	last     float64
+	heard    bool
+}
+
+// NewForm edits s, taking samples readings per position.
+func NewForm(s *Survey, samples int) *Form {
+	if samples < 1 {
+		samples = 1
+	}
+	f := &Form{Survey: s, target: samples}
+	f.reset()
+	return f
+}
+
+// reset clears the inputs and suggests a name for the next position.
+func (f *Form) reset() {
+	f.Inputs = [fieldCount]string{fmt.Sprintf("P%d", len(f.Survey.Observations)+1), "", ""}
+	f.Field = FieldName
+}
+
+// NextField moves the cursor to the next input, wrapping around.
+func (f *Form) NextField() {
+	f.Field = (f.Field + 1) % fieldCount
+}
+
+// PrevField moves the cursor to the previous input, wrapping around.
+func (f *Form) PrevField() {
+	f.Field = (f.Field + fieldCount - 1) % fieldCount
+}
+
+// Type appends s to the current input. Coordinates accept only numbers.
+func (f *Form) Type(s string) {
+	if f.Sampling() {
+		return
+	}
+	if f.Field != FieldName {
+		for _, r := range s {
+			if (r < '0' || r > '9') && r != '.' && r != '-' {
+				return
+			}
+		}
+	}
+	f.Inputs[f.Field] += s
+}
+
+// Backspace deletes the last character of the current input.
+func (f *Form) Backspace() {
+	if in := f.Inputs[f.Field]; in != "" && !f.Sampling() {
+		r := []rune(in)
+		f.Inputs[f.Field] = string(r[:len(r)-1])
+	}
+}
+
+// Begin starts sampling at the position in the inputs.
+func (f *Form) Begin(now time.Time) error {
+	name := strings.TrimSpace(f.Inputs[FieldName])
+	if name == "" {
+		return fmt.Errorf("position needs a name")
+	}
+	x, err := strconv.ParseFloat(strings.TrimSpace(f.Inputs[FieldX]), 64)
+	if err != nil {
+		return fmt.Errorf("invalid X %q", f.Inputs[FieldX])
+	}
+	y, err := strconv.ParseFloat(strings.TrimSpace(f.Inputs[FieldY]), 64)
+	if err != nil {
+		return fmt.Errorf("invalid Y %q", f.Inputs[FieldY])
+	}
+	f.sampling = &Observation{Name: name, X: x, Y: y, Time: now}
+	return nil
+}
+
+// Sampling reports whether samples are currently being collected.
+func (f *Form) Sampling() bool {
+	return f.sampling != nil
+}
+
+// Progress returns the samples collected for the current position and the
+// number required.
+func (f *Form) Progress() (int, int) {
+	if f.sampling == nil {
+		return 0, f.target
+	}
+	return len(f.sampling.Samples), f.target
+}
+
+// Current returns the position being sampled, if any.
+func (f *Form) Current() (Observation, bool) {
+	if f.sampling == nil {
+		return Observation{}, false
+	}
+	return *f.sampling, true
+}
+
+// Add records a raw RSSI sample while sampling. When enough samples are
+// collected the observation is stored and the form waits for the next
+// position.
+func (f *Form) Add(rssi float64) {
+	f.last, f.heard = rssi, true
+	if f.sampling == nil {
+		return
+	}
+	f.sampling.Samples = append(f.sampling.Samples, rssi)
+	if len(f.sampling.Samples) >= f.target {
+		f.Survey.Observations = append(f.Survey.Observations, *f.sampling)
+		f.sampling = nil
+		f.reset()
+	}
+}
+
+// Last returns the most recent RSSI sample, sampling or not.
+func (f *Form) Last() (float64, bool) {
+	return f.last, f.heard
+}
+
+// Undo discards the sample run in progress or, if idle, the last
+// observation.
+func (f *Form) Undo() {
+	if f.sampling != nil {
+		f.sampling = nil
+		return
+	}
+	if n := len(f.Survey.Observations); n > 0 {
+		f.Survey.Observations = f.Survey.Observations[:n-1]
+		f.reset()
+	}
+}
@@ -0,0 +1,89 @@
+// Package survey locates a device by trilateration from RSSI snapshots
+// taken at named positions, and persists surveys for later analysis.
+package survey
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"ble-radar.klederson.com/internal/bluetooth"
+)
+
+// Observation is a set of raw RSSI samples taken at one named position.
+type Observation struct {
+	Name    string    `json:"name"`
+	X       float64   `json:"x"` // Meters east
+	Y       float64   `json:"y"` // Meters north
+	Samples []float64 `json:"samples"`
+	Time    time.Time `json:"time"`
+}
+
+// Mean returns the average RSSI of the observation's samples.
+func (o Observation) Mean() float64 {
+	if len(o.Samples) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range o.Samples {
+		sum += v
+	}
+	return sum / float64(len(o.Samples))
+}
+
+// Survey is every observation of one device plus the path loss model used
+// to turn RSSI into range.
+type Survey struct {
+	MAC          string             `json:"mac"`
+	Name         string             `json:"name,omitempty"`
+	Model        bluetooth.PathLoss `json:"model"`
+	Observations []Observation      `json:"observations"`
+	Updated      time.Time          `json:"updated"`
+}
+
+// DefaultDir returns the survey directory in the user's config directory.
+func DefaultDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "surveys"
+	}
+	return filepath.Join(dir, "ble-radar", "surveys")
+}
+
+// PathFor returns the file a device's survey is stored in under dir.
+func PathFor(dir, mac string) string {
+	return filepath.Join(dir, strings.ReplaceAll(strings.ToUpper(mac), ":", "")+".json")
+}
+
+// Load reads a survey from path.
+func Load(path string) (*Survey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s Survey
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Save writes the survey to path, creating parent directories.
+func (s *Survey) Save(path string) error {
+	s.Updated = time.Now()
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Solve trilaterates the device from the survey's observations.
+func (s *Survey) Solve() (Fix, error) {
+	return Trilaterate(s.Observations, s.Model)
+}
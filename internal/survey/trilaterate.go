@@ -0,0 +1,147 @@
+package survey
+
+import (
+	"errors"
+	"math"
+
+	"ble-radar.klederson.com/internal/bluetooth"
+)
+
+const (
+	// rssiSigma is the assumed standard deviation of a mean RSSI reading
+	// indoors (dB), from shadowing and multipath.
+	rssiSigma = 4.0
+
+	// chi2For95 is the 95% quantile of the chi-square distribution with two
+	// degrees of freedom, scaling the covariance to the error ellipse.
+	chi2For95 = 5.991
+
+	maxIterations = 50
+)
+
+// Ellipse is an error ellipse around a position estimate.
+type Ellipse struct {
+	Major float64 // Semi-major axis in meters
+	Minor float64 // Semi-minor axis in meters
+	Angle float64 // Major axis direction, radians counterclockwise from east
+}
+
+// Fix is a trilaterated position.
+type Fix struct {
+	Position bluetooth.Point
+	Ellipse  Ellipse // 95% confidence region
+	RMS      float64 // RMS range residual in meters
+	N        int     // Observations used
+}
+
+// Bearing returns the direction of the major axis as a compass bearing in
+// [0, π), clockwise from north.
+func (e Ellipse) Bearing() float64 {
+	b := math.Mod(math.Pi/2-e.Angle, math.Pi)
+	if b < 0 {
+		b += math.Pi
+	}
+	return b
+}
+
+// Range converts an observation's mean RSSI to meters with model.
+func Range(o Observation, model bluetooth.PathLoss) float64 {
+	return bluetooth.RSSIToDistance(o.Mean(), model.MeasuredPower, model.Exponent)
+}
+
+// Trilaterate finds the position that best matches the ranges implied by
+// each observation, by weighted least squares (Gauss-Newton). A range's
+// error grows in proportion to the range itself under the log-distance
+// model, so far observations get less weight.
+func Trilaterate(obs []Observation, model bluetooth.PathLoss) (Fix, error) {
+	type anchor struct{ x, y, r, w float64 }
+	var as []anchor
+	for _, o := range obs {
+		if len(o.Samples) == 0 {
+			continue
+		}
+		r := Range(o, model)
+		sigma := r * math.Ln10 / (10 * model.Exponent) * rssiSigma
+		as = append(as, anchor{x: o.X, y: o.Y, r: r, w: 1 / (sigma * sigma)})
+	}
+	if len(as) < 3 {
+		return Fix{}, errors.New("need observations from at least three positions")
+	}
+
+	// Start from the centroid weighted towards the strongest readings.
+	var px, py, ws float64
+	for _, a := range as {
+		w := 1 / (a.r * a.r)
+		px += w * a.x
+		py += w * a.y
+		ws += w
+	}
+	px, py = px/ws, py/ws
+
+	var a11, a12, a22 float64
+	for it := 0; it < maxIterations; it++ {
+		var b1, b2 float64
+		a11, a12, a22 = 0, 0, 0
+		for _, a := range as {
+			dx, dy := px-a.x, py-a.y
+			dist := math.Max(math.Hypot(dx, dy), 1e-6)
+			jx, jy := dx/dist, dy/dist
+			f := dist - a.r
+			a11 += a.w * jx * jx
+			a12 += a.w * jx * jy
+			a22 += a.w * jy * jy
+			b1 -= a.w * jx * f
+			b2 -= a.w * jy * f
+		}
+		det := a11*a22 - a12*a12
+		if det < 1e-12*(a11+a22)*(a11+a22) {
+			return Fix{}, errors.New("positions are collinear; add one off the line")
+		}
+		sx := (a22*b1 - a12*b2) / det
+		sy := (a11*b2 - a12*b1) / det
+		px += sx
+		py += sy
+		if math.Hypot(sx, sy) < 1e-4 {
+			break
+		}
+	}
+
+	// Residuals
+	var chi2, sq float64
+	for _, a := range as {
+		f := math.Hypot(px-a.x, py-a.y) - a.r
+		chi2 += a.w * f * f
+		sq += f * f
+	}
+	n := len(as)
+
+	// Covariance (J^T W J)^-1, inflated when the misfit exceeds the
+	// assumed RSSI noise.
+	det := a11*a22 - a12*a12
+	cxx, cxy, cyy := a22/det, -a12/det, a11/det
+	if n > 2 {
+		if s := chi2 / float64(n-2); s > 1 {
+			cxx, cxy, cyy = cxx*s, cxy*s, cyy*s
+		}
+	}
+
+	return Fix{
+		Position: bluetooth.Point{X: px, Y: py},
+		Ellipse:  ellipse(cxx, cxy, cyy),
+		RMS:      math.Sqrt(sq / float64(n)),
+		N:        n,
+	}, nil
+}
+
+// ellipse returns the 95% error ellipse of a 2x2 covariance matrix.
+func ellipse(cxx, cxy, cyy float64) Ellipse {
+	mid := (cxx + cyy) / 2
+	diff := math.Sqrt((cxx-cyy)*(cxx-cyy)/4 + cxy*cxy)
+	l1, l2 := mid+diff, math.Max(mid-diff, 0)
+	k := math.Sqrt(chi2For95)
+	return Ellipse{
+		Major: k * math.Sqrt(l1),
+		Minor: k * math.Sqrt(l2),
+		Angle: 0.5 * math.Atan2(2*cxy, cxx-cyy),
+	}
+}
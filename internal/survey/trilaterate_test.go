@@ -0,0 +1,120 @@
+package survey
+
+import (
+	"math"
+	"testing"
+
+	"ble-radar.klederson.com/internal/bluetooth"
+)
+
+var testModel = bluetooth.PathLoss{MeasuredPower: -59, Exponent: 2}
+
+// observe returns an observation at (x, y) whose RSSI puts it exactly d
+// meters away under testModel.
+func observe(x, y, d float64) Observation {
+	rssi := testModel.MeasuredPower - 10*testModel.Exponent*math.Log10(d)
+	return Observation{X: x, Y: y, Samples: []float64{rssi - 1, rssi + 1}}
+}
+
+func TestTrilaterate(t *testing.T) {
+	target := bluetooth.Point{X: 3, Y: 4}
+	at := func(x, y float64) Observation {
+		return observe(x, y, math.Hypot(target.X-x, target.Y-y))
+	}
+
+	tests := []struct {
+		name    string
+		obs     []Observation
+		wantN   int
+		wantErr bool
+	}{
+		{name: "three corners", obs: []Observation{at(0, 0), at(10, 0), at(0, 10)}, wantN: 3},
+		{name: "square", obs: []Observation{at(0, 0), at(10, 0), at(0, 10), at(10, 10)}, wantN: 4},
+		{name: "target outside", obs: []Observation{at(-5, -5), at(-2, -6), at(-6, -1)}, wantN: 3},
+		{
+			name:  "empty observations skipped",
+			obs:   []Observation{at(0, 0), {X: 5, Y: 5}, at(10, 0), at(0, 10)},
+			wantN: 3,
+		},
+		{name: "two positions", obs: []Observation{at(0, 0), at(10, 0)}, wantErr: true},
+		{name: "collinear", obs: []Observation{at(0, 0), at(5, 0), at(10, 0)}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fix, err := Trilaterate(tt.obs, testModel)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Trilaterate = %+v, want an error", fix)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := math.Hypot(fix.Position.X-target.X, fix.Position.Y-target.Y); d > 1e-3 {
+				t.Errorf("position = %+v, want %+v", fix.Position, target)
+			}
+			if fix.RMS > 1e-3 {
+				t.Errorf("RMS = %v, want 0", fix.RMS)
+			}
+			if fix.N != tt.wantN {
+				t.Errorf("N = %d, want %d", fix.N, tt.wantN)
+			}
+			if fix.Ellipse.Major <= 0 || fix.Ellipse.Minor > fix.Ellipse.Major {
+				t.Errorf("ellipse = %+v", fix.Ellipse)
+			}
+		})
+	}
+}
+
+func TestTrilaterateNoise(t *testing.T) {
+	// Ranges off by 20% in alternating directions: the fit stays near the
+	// target and the ellipse covers it.
+	target := bluetooth.Point{X: 4, Y: 6}
+	var obs []Observation
+	for i, p := range []bluetooth.Point{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}} {
+		d := math.Hypot(target.X-p.X, target.Y-p.Y) * (1 + 0.2*float64(1-2*(i%2)))
+		obs = append(obs, observe(p.X, p.Y, d))
+	}
+	fix, err := Trilaterate(obs, testModel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	off := math.Hypot(fix.Position.X-target.X, fix.Position.Y-target.Y)
+	if off > 2 {
+		t.Errorf("position = %+v, %.2f m from %+v", fix.Position, off, target)
+	}
+	if off > fix.Ellipse.Major {
+		t.Errorf("ellipse %+v does not reach the target %.2f m away", fix.Ellipse, off)
+	}
+	if fix.RMS == 0 {
+		t.Error("RMS = 0 with noisy ranges")
+	}
+}
+
+func TestEllipse(t *testing.T) {
+	k := math.Sqrt(chi2For95)
+	tests := []struct {
+		name          string
+		cxx, cxy, cyy float64
+		want          Ellipse
+		bearing       float64
+	}{
+		{"east-west", 4, 0, 1, Ellipse{Major: 2 * k, Minor: k, Angle: 0}, math.Pi / 2},
+		{"north-south", 1, 0, 4, Ellipse{Major: 2 * k, Minor: k, Angle: math.Pi / 2}, 0},
+		{"northeast", 2.5, 1.5, 2.5, Ellipse{Major: 2 * k, Minor: k, Angle: math.Pi / 4}, math.Pi / 4},
+		{"circle", 1, 0, 1, Ellipse{Major: k, Minor: k, Angle: 0}, math.Pi / 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ellipse(tt.cxx, tt.cxy, tt.cyy)
+			if math.Abs(got.Major-tt.want.Major) > 1e-9 || math.Abs(got.Minor-tt.want.Minor) > 1e-9 ||
+				math.Abs(got.Angle-tt.want.Angle) > 1e-9 {
+				t.Errorf("ellipse = %+v, want %+v", got, tt.want)
+			}
+			if b := got.Bearing(); math.Abs(b-tt.bearing) > 1e-9 {
+				t.Errorf("Bearing = %v, want %v", b, tt.bearing)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+// Package tracking flags tracking tags (AirTag, Tile, SmartTag) that stay
+// with the user over time and across places, following each tag through
+// its address rotations.
+package tracking
+
+import (
+	"sort"
+	"time"
+
+	"ble-radar.klederson.com/internal/bluetooth"
+	"ble-radar.klederson.com/internal/config"
+)
+
+const (
+	// settleDelay is how long a new address must be known before it is
+	// matched to a track that went silent. An old address still heard
+	// after that is a different tag, not a rotation.
+	settleDelay = 3 * time.Second
+
+	// retention drops tracks not seen for this long. Find My tags away
+	// from their owner keep their key for a day.
+	retention = 24 * time.Hour
+)
+
+// Thresholds decide when a track raises an alert.
+type Thresholds struct {
+	MinDuration time.Duration
+	Window      time.Duration
+	MinWindows  int
+	MinPlaces   int
+	RotationGap time.Duration
+}
+
+// ThresholdsFromConfig converts the trackers section of the config.
+func ThresholdsFromConfig(c config.Trackers) Thresholds {
+	return Thresholds{
+		MinDuration: c.MinDuration,
+		Window:      c.Window,
+		MinWindows:  c.MinWindows,
+		MinPlaces:   c.MinPlaces,
+		RotationGap: c.RotationGap,
+	}
+}
+
+// Track is one physical tag, possibly seen under several addresses.
+type Track struct {
+	ID        int
+	Kind      bluetooth.TrackerKind
+	Model     string
+	MAC       string   // Current address
+	MACs      []string // Every address, oldest first
+	FirstSeen time.Time
+	LastSeen  time.Time
+	Windows   int  // Distinct time windows the tag was seen in
+	Places    int  // Distinct places it was seen at; 0 when places are unknown
+	Alert     bool // Persistence thresholds exceeded
+
+	key     string
+	windows map[int64]bool
+	places  map[int]bool
+}
+
+// Duration returns how long the tag has been around.
+func (t *Track) Duration() time.Duration {
+	return t.LastSeen.Sub(t.FirstSeen)
+}
+
+// Label returns the tag model, or its network if the model is unknown.
+func (t *Track) Label() string {
+	if t.Model != "" {
+		return t.Model
+	}
+	return t.Kind.String()
+}
+
+// Monitor follows tracking tags across snapshots of the device store.
+// It is not safe for concurrent use.
+type Monitor struct {
+	th     Thresholds
+	tracks []*Track
+	byMAC  map[string]*Track
+	nextID int
+	places places
+}
+
+// NewMonitor returns a monitor raising alerts at the given thresholds.
+func NewMonitor(th Thresholds) *Monitor {
+	return &Monitor{th: th, byMAC: make(map[string]*Track)}
+}
+
+// Observe updates the tracks from a store snapshot taken at now. Find My
+// tags near their owner are ignored: a companion's AirTag is not a threat.
+func (m *Monitor) Observe(devices []*bluetooth.Device, now time.Time) {
+	place := m.places.observe(devices)
+
+	// Oldest addresses first, so a rotation links to the track it continues.
+	tags := make([]*bluetooth.Device, 0)
+	for _, d := range devices {
+		if d.Tracker != nil && (d.Tracker.Kind != bluetooth.TrackerFindMy || d.Tracker.Separated) {
+			tags = append(tags, d)
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].FirstSeen.Before(tags[j].FirstSeen) })
+
+	// A track continues under at most one new address per snapshot.
+	claimed := make(map[*Track]bool)
+	for _, d := range tags {
+		t, ok := m.byMAC[d.MAC]
+		if !ok {
+			if now.Sub(d.FirstSeen) < settleDelay {
+				continue
+			}
+			t = m.link(d, claimed)
+			m.byMAC[d.MAC] = t
+			claimed[t] = true
+		}
+		m.update(t, d, place)
+	}
+
+	m.expire(now)
+}
+
+// link returns the track a new address belongs to: one with the same frame
+// key, else one of the same model that fell silent just before the address
+// appeared, else a new track.
+func (m *Monitor) link(d *bluetooth.Device, claimed map[*Track]bool) *Track {
+	tr := d.Tracker
+	var best *Track
+	for _, t := range m.tracks {
+		if claimed[t] || t.Kind != tr.Kind {
+			continue
+		}
+		if tr.Key != "" && t.key == tr.Key {
+			best = t
+			break
+		}
+		if t.Model != tr.Model {
+			continue
+		}
+		gap := d.FirstSeen.Sub(t.LastSeen)
+		if gap < -settleDelay || gap > m.th.RotationGap {
+			continue
+		}
+		if best == nil || t.LastSeen.After(best.LastSeen) {
+			best = t
+		}
+	}
+	if best != nil {
+		best.MAC = d.MAC
+		best.MACs = append(best.MACs, d.MAC)
+		return best
+	}
+
+	m.nextID++
+	t := &Track{
+		ID:        m.nextID,
+		Kind:      tr.Kind,
+		Model:     tr.Model,
+		MAC:       d.MAC,
+		MACs:      []string{d.MAC},
+		FirstSeen: d.FirstSeen,
+		LastSeen:  d.FirstSeen,
+		windows:   make(map[int64]bool),
+		places:    make(map[int]bool),
+	}
+	m.tracks = append(m.tracks, t)
+	return t
+}
+
+// update records a sighting of t as device d at place and re-evaluates the
+// alert.
+func (m *Monitor) update(t *Track, d *bluetooth.Device, place int) {
+	if d.Tracker.Key != "" {
+		t.key = d.Tracker.Key
+	}
+	if d.Tracker.Model != "" {
+		t.Model = d.Tracker.Model
+	}
+	if d.LastSeen.After(t.LastSeen) {
+		t.LastSeen = d.LastSeen
+	}
+	t.windows[t.LastSeen.UnixNano()/int64(m.th.Window)] = true
+	if place > 0 {
+		t.places[place] = true
+	}
+	t.Windows, t.Places = len(t.windows), len(t.places)
+	t.Alert = t.Duration() >= m.th.MinDuration &&
+		(t.Windows >= m.th.MinWindows || t.Places >= m.th.MinPlaces)
+}
+
+// expire forgets tracks not seen within the retention period.
+func (m *Monitor) expire(now time.Time) {
+	kept := m.tracks[:0]
+	for _, t := range m.tracks {
+		if now.Sub(t.LastSeen) <= retention {
+			kept = append(kept, t)
+			continue
+		}
+		for _, mac := range t.MACs {
+			delete(m.byMAC, mac)
+		}
+	}
+	m.tracks = kept
+}
+
+// Track returns a copy of the track the address belongs to.
+func (m *Monitor) Track(mac string) (Track, bool) {
+	t, ok := m.byMAC[mac]
+	if !ok {
+		return Track{}, false
+	}
+	return t.copy(), true
+}
+
+// Alerts returns the alerting tracks seen within the last window, longest
+// following first.
+func (m *Monitor) Alerts(now time.Time) []Track {
+	var out []Track
+	for _, t := range m.tracks {
+		if t.Alert && now.Sub(t.LastSeen) <= m.th.Window {
+			out = append(out, t.copy())
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Duration() > out[j].Duration() })
+	return out
+}
+
+// copy returns the track without its bookkeeping maps.
+func (t *Track) copy() Track {
+	cp := *t
+	cp.MACs = append([]string(nil), t.MACs...)
+	cp.windows, cp.places = nil, nil
+	return cp
+}
@@ -0,0 +1,211 @@
+package tracking
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"ble-radar.klederson.com/internal/bluetooth"
+)
+
+var (
+	t0 = time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	testThresholds = Thresholds{
+		MinDuration: 30 * time.Minute,
+		Window:      10 * time.Minute,
+		MinWindows:  3,
+		MinPlaces:   2,
+		RotationGap: 5 * time.Minute,
+	}
+)
+
+// airTag returns a separated AirTag heard under mac from first to last.
+func airTag(mac, key string, first, last time.Time) *bluetooth.Device {
+	return &bluetooth.Device{
+		MAC:       mac,
+		Type:      bluetooth.DeviceTypeBLE,
+		FirstSeen: first,
+		LastSeen:  last,
+		Tracker: &bluetooth.Tracker{
+			Kind: bluetooth.TrackerFindMy, Model: "AirTag", State: "separated", Separated: true, Key: key,
+		},
+	}
+}
+
+// accessPoints returns WiFi devices for the given BSSIDs.
+func accessPoints(bssids ...string) []*bluetooth.Device {
+	var out []*bluetooth.Device
+	for _, b := range bssids {
+		out = append(out, &bluetooth.Device{MAC: b, Type: bluetooth.DeviceTypeWiFi})
+	}
+	return out
+}
+
+func TestMonitorRotation(t *testing.T) {
+	quiet := t0.Add(10 * time.Minute) // The first address is last heard here
+	tests := []struct {
+		name       string
+		next       *bluetooth.Device
+		stillHeard bool // The first address is heard alongside the next one
+		same       bool
+	}{
+		{"within rotation gap", airTag("5B:00:00:00:00:02", "k2", quiet.Add(2*time.Minute), quiet.Add(2*time.Minute)), false, true},
+		{"after rotation gap", airTag("5B:00:00:00:00:02", "k2", quiet.Add(6*time.Minute), quiet.Add(6*time.Minute)), false, false},
+		{"same key after gap", airTag("5B:00:00:00:00:02", "k1", quiet.Add(2*time.Hour), quiet.Add(2*time.Hour)), false, true},
+		{"no keys within gap", airTag("5B:00:00:00:00:02", "", quiet.Add(time.Minute), quiet.Add(time.Minute)), false, true},
+		{"other model", func() *bluetooth.Device {
+			d := airTag("5B:00:00:00:00:02", "k2", quiet.Add(time.Minute), quiet.Add(time.Minute))
+			d.Tracker.Model = "AirPods"
+			return d
+		}(), false, false},
+		{"other network", func() *bluetooth.Device {
+			d := airTag("5B:00:00:00:00:02", "k1", quiet.Add(time.Minute), quiet.Add(time.Minute))
+			d.Tracker.Kind = bluetooth.TrackerSmartTag
+			return d
+		}(), false, false},
+		{"old address still heard", airTag("5B:00:00:00:00:02", "k2", quiet, quiet), true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMonitor(testThresholds)
+			first := airTag("4A:00:00:00:00:01", "k1", t0, quiet)
+			if tt.next.Tracker.Key == "" {
+				first.Tracker.Key = ""
+			}
+			m.Observe([]*bluetooth.Device{first}, quiet)
+
+			now := tt.next.FirstSeen.Add(settleDelay + time.Second)
+			tt.next.LastSeen = now
+			devices := []*bluetooth.Device{tt.next}
+			if tt.stillHeard {
+				first.LastSeen = now
+				devices = append(devices, first)
+			}
+			m.Observe(devices, now)
+
+			a, ok := m.Track(first.MAC)
+			if !ok {
+				t.Fatal("first address not tracked")
+			}
+			b, ok := m.Track(tt.next.MAC)
+			if !ok {
+				t.Fatal("next address not tracked")
+			}
+			if same := a.ID == b.ID; same != tt.same {
+				t.Errorf("same track = %v, want %v (tracks %+v and %+v)", same, tt.same, a, b)
+			}
+			if tt.same && (len(b.MACs) != 2 || b.MAC != tt.next.MAC || !b.FirstSeen.Equal(t0)) {
+				t.Errorf("continued track = %+v", b)
+			}
+		})
+	}
+}
+
+func TestMonitorPersistenceAcrossRotation(t *testing.T) {
+	// An AirTag stays with the user for an hour, taking a new address and
+	// key every 15 minutes. The store forgets old addresses, so each
+	// snapshot only holds the current one.
+	m := NewMonitor(testThresholds)
+	var firstAlert time.Duration
+	for min := 0; min < 60; min++ {
+		now := t0.Add(time.Duration(min) * time.Minute)
+		n := min / 15
+		mac := fmt.Sprintf("4A:00:00:00:00:%02X", n)
+		tag := airTag(mac, fmt.Sprintf("k%d", n), t0.Add(time.Duration(n)*15*time.Minute), now)
+		m.Observe([]*bluetooth.Device{tag}, now)
+
+		if tr, ok := m.Track(mac); ok && tr.Alert && firstAlert == 0 {
+			firstAlert = now.Sub(t0)
+		}
+	}
+
+	now := t0.Add(59 * time.Minute)
+	tr, ok := m.Track("4A:00:00:00:00:03")
+	if !ok {
+		t.Fatal("last address not tracked")
+	}
+	if first, _ := m.Track("4A:00:00:00:00:00"); first.ID != tr.ID {
+		t.Errorf("first address on track %d, last on %d", first.ID, tr.ID)
+	}
+	if len(tr.MACs) != 4 || tr.Duration() != 59*time.Minute || tr.Windows != 6 || tr.Places != 0 {
+		t.Errorf("track = %+v, want 4 addresses over 59 minutes in 6 windows", tr)
+	}
+	// The address taken at 30 minutes joins the track a snapshot later,
+	// once it has settled.
+	if want := testThresholds.MinDuration + time.Minute; firstAlert != want {
+		t.Errorf("first alert after %v, want %v", firstAlert, want)
+	}
+	if alerts := m.Alerts(now); len(alerts) != 1 || alerts[0].ID != tr.ID {
+		t.Errorf("alerts = %+v, want track %d", alerts, tr.ID)
+	}
+
+	// Once the tag is gone the alert fades after a window and the track
+	// after the retention period.
+	if alerts := m.Alerts(now.Add(testThresholds.Window + time.Second)); len(alerts) != 0 {
+		t.Errorf("alerts after silence = %+v", alerts)
+	}
+	m.Observe(nil, now.Add(retention+time.Second))
+	if _, ok := m.Track("4A:00:00:00:00:03"); ok {
+		t.Error("track kept past retention")
+	}
+}
+
+func TestMonitorPlaces(t *testing.T) {
+	// Ten minutes is too short to alert on time windows alone, but the tag
+	// follows the user from home to the office.
+	th := testThresholds
+	th.MinDuration = 5 * time.Minute
+	th.MinWindows = 10
+	home := accessPoints("AA:00:00:00:00:01", "AA:00:00:00:00:02")
+	office := accessPoints("BB:00:00:00:00:01", "BB:00:00:00:00:02", "BB:00:00:00:00:03")
+
+	tests := []struct {
+		name      string
+		moves     bool
+		wantAlert bool
+	}{
+		{"stays home", false, false},
+		{"follows to the office", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMonitor(th)
+			for min := 0; min <= 10; min++ {
+				now := t0.Add(time.Duration(min) * time.Minute)
+				devices := home
+				if tt.moves && min > 5 {
+					devices = office
+				}
+				tag := airTag("4A:00:00:00:00:01", "k1", t0, now)
+				m.Observe(append([]*bluetooth.Device{tag}, devices...), now)
+			}
+			tr, _ := m.Track("4A:00:00:00:00:01")
+			if tr.Alert != tt.wantAlert {
+				t.Errorf("alert = %v, want %v (track %+v)", tr.Alert, tt.wantAlert, tr)
+			}
+		})
+	}
+}
+
+func TestMonitorIgnores(t *testing.T) {
+	now := t0.Add(time.Minute)
+	nearOwner := airTag("4A:00:00:00:00:01", "", t0, now)
+	nearOwner.Tracker.State, nearOwner.Tracker.Separated = "near owner", false
+	unsettled := airTag("4A:00:00:00:00:02", "k2", now.Add(-time.Second), now)
+	phone := &bluetooth.Device{MAC: "4A:00:00:00:00:03", Type: bluetooth.DeviceTypeBLE, FirstSeen: t0, LastSeen: now}
+
+	m := NewMonitor(testThresholds)
+	m.Observe([]*bluetooth.Device{nearOwner, unsettled, phone}, now)
+	for _, d := range []*bluetooth.Device{nearOwner, unsettled, phone} {
+		if tr, ok := m.Track(d.MAC); ok {
+			t.Errorf("%s tracked as %+v", d.MAC, tr)
+		}
+	}
+
+	// The new address is tracked once it settles.
+	m.Observe([]*bluetooth.Device{unsettled}, now.Add(settleDelay))
+	if _, ok := m.Track(unsettled.MAC); !ok {
+		t.Error("settled address not tracked")
+	}
+}
@@ -0,0 +1,58 @@
+package tracking
+
+import "ble-radar.klederson.com/internal/bluetooth"
+
+// samePlace is the share of visible access points that must be known at
+// a place to be there again.
+const samePlace = 0.5
+
+// places tells locations apart by the WiFi access points in range, which
+// stay put while people and their devices move with the user.
+type places struct {
+	known   []map[string]bool // Access points per place; place ID is index+1
+	current int               // 0 until an access point has been seen
+}
+
+// observe returns the ID of the place the snapshot was taken at, or 0 if
+// no access points have been seen yet.
+func (p *places) observe(devices []*bluetooth.Device) int {
+	var aps []string
+	for _, d := range devices {
+		if d.Type == bluetooth.DeviceTypeWiFi {
+			aps = append(aps, d.MAC)
+		}
+	}
+	if len(aps) == 0 {
+		return p.current
+	}
+
+	match := func(id int) float64 {
+		n := 0
+		for _, ap := range aps {
+			if p.known[id-1][ap] {
+				n++
+			}
+		}
+		return float64(n) / float64(len(aps))
+	}
+
+	best, bestScore := 0, 0.0
+	if p.current > 0 && match(p.current) >= samePlace {
+		best = p.current
+	} else {
+		for id := 1; id <= len(p.known); id++ {
+			if s := match(id); s >= samePlace && s > bestScore {
+				best, bestScore = id, s
+			}
+		}
+	}
+	if best == 0 {
+		p.known = append(p.known, make(map[string]bool))
+		best = len(p.known)
+	}
+	for _, ap := range aps {
+		p.known[best-1][ap] = true
+	}
+	p.current = best
+	return best
+}
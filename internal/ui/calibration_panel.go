@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"ble-radar.klederson.com/internal/calibration"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// RenderCalibrationPanel renders the distance calibration wizard in place
+// of the radar. status is a one-line message such as a save confirmation.
+func RenderCalibrationPanel(w *calibration.Wizard, width, height int, status string) string {
+	innerW := width - 4
+	if innerW < 20 {
+		innerW = 20
+	}
+
+	title := StylePanelTitle.Render("CALIBRATION")
+	escHint := StyleHelp.Render("[ESC]")
+	titleLine := title + strings.Repeat(" ", max(0, innerW-lipgloss.Width(title)-lipgloss.Width(escHint))) + escHint
+	sep := StyleRadarRing.Render(strings.Repeat("-", innerW))
+
+	labelSty := lipgloss.NewStyle().Foreground(ColorMidGreen)
+	valSty := lipgloss.NewStyle().Foreground(ColorMatrixGreen).Bold(true)
+
+	name := w.Name
+	if name == "" {
+		name = "[unnamed]"
+	}
+	lines := []string{
+		titleLine, sep, "",
+		labelSty.Render("  Device    ") + valSty.Render(name),
+		labelSty.Render("  MAC       ") + valSty.Render(w.MAC),
+		"",
+	}
+
+	// Current step
+	if w.Sampling() {
+		n, target := w.Progress()
+		lines = append(lines,
+			valSty.Render(fmt.Sprintf("  Sampling at %.2fm ... %d/%d", w.Distance(), n, target)),
+			StyleHelp.Render("  Keep the device still. [U] abort this point"),
+		)
+	} else {
+		prompt := "  Place the device at a known distance, type it and press Enter"
+		if len(w.Points) == 0 {
+			prompt = "  Place the device at 1 m (or type another distance) and press Enter"
+		}
+		lines = append(lines,
+			labelSty.Render(prompt),
+			labelSty.Render("  Distance: ")+StyleFilterActive.Render(w.Input+"_")+labelSty.Render(" m"),
+		)
+	}
+	lines = append(lines, "")
+
+	// Collected points
+	if len(w.Points) > 0 {
+		lines = append(lines, labelSty.Render("  Points:"))
+		for _, p := range w.Points {
+			lines = append(lines, valSty.Render(fmt.Sprintf("    %6.2fm  %6.1f dBm  (%d samples)",
+				p.Distance, p.Mean(), len(p.Samples))))
+		}
+		lines = append(lines, "")
+	}
+
+	// Fit
+	if res, err := w.Fit(); err == nil {
+		lines = append(lines,
+			labelSty.Render("  Fit       ")+valSty.Render(fmt.Sprintf("MeasuredPower %.1f dBm  PathLossExp %.2f",
+				res.Model.MeasuredPower, res.Model.Exponent)),
+			labelSty.Render("  Residual  ")+valSty.Render(fmt.Sprintf("%.1f dB RMS over %d samples", res.RMSE, res.N)),
+			"",
+			StyleHelp.Render("  [G] save as global profile  [D] save for this device  [U] undo point"),
+		)
+	} else if len(w.Points) > 0 {
+		lines = append(lines, StyleHelp.Render("  "+err.Error()))
+	}
+
+	if status != "" {
+		lines = append(lines, "", StyleIsolateMarker.Render("  "+status))
+	}
+
+	for len(lines) < height-2 {
+		lines = append(lines, "")
+	}
+	if len(lines) > height-2 {
+		lines = lines[:height-2]
+	}
+
+	content := strings.Join(lines, "\n")
+	return StylePanelActive.Width(width - 2).Height(height - 2).Render(content)
+}
@@ -3,15 +3,21 @@ package ui
 import (
 	"fmt"
 	"math"
+	"sort"
 	"strings"
 	"time"
 
 	"ble-radar.klederson.com/internal/bluetooth"
+	"ble-radar.klederson.com/internal/tracking"
 	"github.com/charmbracelet/lipgloss"
 )
 
 // RenderDetailPanel renders the device detail overlay that replaces the radar area.
-func RenderDetailPanel(d *bluetooth.Device, width, height int, rssiHistory []float64) string {
+// rssiHistory holds smoothed samples and rawHistory the unfiltered ones.
+// track is the tracker monitor's history of the tag, nil if none.
+// sensorHistory holds recent sensor readings per quantity. advOpen expands
+// the Advertisement section.
+func RenderDetailPanel(d *bluetooth.Device, width, height int, rssiHistory, rawHistory []float64, track *tracking.Track, sensorHistory map[bluetooth.Quantity][]float64, advOpen bool) string {
 	innerW := width - 4
 	if innerW < 20 {
 		innerW = 20
@@ -31,13 +37,18 @@ func RenderDetailPanel(d *bluetooth.Device, width, height int, rssiHistory []flo
 
 	fields := []struct{ label, value string }{
 		{"Name", d.DisplayName()},
-		{"MAC", d.MAC},
+		{"MAC", addressLabel(d)},
 		{"Type", d.Type.String()},
-		{"RSSI", fmt.Sprintf("%d dBm", int(d.RSSI))},
+		{"RSSI", fmt.Sprintf("%d dBm (raw %d)", int(d.RSSI), int(d.RawRSSI))},
 		{"Distance", fmt.Sprintf("~%.1fm", d.Distance)},
+		{"Ref @1m", fmt.Sprintf("%.0f dBm (%s)", d.RefPower, d.RefPowerSource)},
 		{"Last", formatLastSeen(d.LastSeen)},
 	}
 
+	if d.Platform != "" {
+		fields = append(fields, struct{ label, value string }{"Platform", d.Platform})
+	}
+
 	if d.Type == bluetooth.DeviceTypeWiFi {
 		if d.Frequency > 0 {
 			fields = append(fields, struct{ label, value string }{
@@ -51,12 +62,83 @@ func RenderDetailPanel(d *bluetooth.Device, width, height int, rssiHistory []flo
 		}
 	}
 
+	if d.ClassOfDevice != 0 {
+		fields = append(fields, classFields(d.ClassOfDevice)...)
+	}
+	if d.Vendor != "" {
+		fields = append(fields, struct{ label, value string }{"Vendor", d.Vendor})
+	}
+	if d.AddressType != bluetooth.AddressUnknown {
+		fields = append(fields, addressFields(d)...)
+	}
+
+	if b := d.Beacon; b != nil {
+		fields = append(fields, beaconFields(b)...)
+	}
+
+	if t := d.Tracker; t != nil {
+		fields = append(fields, trackerFields(t, track)...)
+	}
+
+	if c := d.Continuity; c != nil {
+		fields = append(fields, continuityFields(c)...)
+	}
+
+	if s := d.Sensor; s != nil {
+		fields = append(fields, sensorFields(s)...)
+	}
+
+	if m := d.Microsoft; m != nil {
+		fields = append(fields, microsoftFields(m)...)
+	}
+
+	if f := d.FastPair; f != nil {
+		fields = append(fields, fastPairFields(f)...)
+	}
+
+	if len(d.Adapters) > 1 {
+		fields = append(fields, adapterFields(d)...)
+	}
+
+	if d.Pinned {
+		fields = append(fields, struct{ label, value string }{"Bearing", fmt.Sprintf("%.0f° %s (fox hunt)",
+			d.Angle*180/math.Pi, angleToDir(d.Angle))})
+	} else if d.Located {
+		fields = append(fields,
+			struct{ label, value string }{"Bearing", fmt.Sprintf("%.0f° %s (confidence %.0f%%)",
+				d.Angle*180/math.Pi, angleToDir(d.Angle), d.Confidence*100)},
+			struct{ label, value string }{"Position", fmt.Sprintf("%.1fm E, %.1fm N", d.Position.X, d.Position.Y)},
+		)
+	} else if len(d.Adapters) > 1 {
+		fields = append(fields, struct{ label, value string }{"Bearing", "unknown (angle from MAC hash)"})
+	}
+
 	for _, f := range fields {
 		label := labelSty.Render(fmt.Sprintf("  %-10s", f.label))
 		value := valSty.Render(f.value)
 		lines = append(lines, label+value)
 	}
 
+	// Advertisement, collapsed to a one-line summary unless expanded
+	if a := d.Advertisement; a != nil {
+		lines = append(lines, "")
+		if !advOpen {
+			lines = append(lines, labelSty.Render("  + Advertisement ")+valSty.Render(advSummary(a))+StyleHelp.Render(" [A] expand"))
+		} else {
+			lines = append(lines, labelSty.Render("  - Advertisement")+StyleHelp.Render(" [A] collapse"))
+			valueW := max(10, innerW-12)
+			for _, f := range advertisementFields(a) {
+				label := labelSty.Render(fmt.Sprintf("  %-10s", f.label))
+				for i, chunk := range wrapValue(f.value, valueW) {
+					if i > 0 {
+						label = strings.Repeat(" ", 12)
+					}
+					lines = append(lines, label+valSty.Render(chunk))
+				}
+			}
+		}
+	}
+
 	lines = append(lines, "")
 
 	// Signal bar
@@ -70,15 +152,40 @@ func RenderDetailPanel(d *bluetooth.Device, width, height int, rssiHistory []flo
 
 	lines = append(lines, "")
 
-	// RSSI sparkline
+	// RSSI sparklines, filtered over raw on a shared scale
 	if len(rssiHistory) > 0 {
 		sparkW := innerW - 4
 		if sparkW < 10 {
 			sparkW = 10
 		}
-		lines = append(lines, labelSty.Render("  RSSI History:"))
-		spark := renderSparkline(rssiHistory, sparkW)
+		minV, maxV := sparkRange(rssiHistory, rawHistory)
+		lines = append(lines, labelSty.Render("  RSSI History (filtered / raw):"))
+		spark := renderSparklineRange(rssiHistory, sparkW, minV, maxV)
 		lines = append(lines, "  "+lipgloss.NewStyle().Foreground(ColorGreen).Render(spark))
+		if len(rawHistory) > 0 {
+			rawSpark := renderSparklineRange(rawHistory, sparkW, minV, maxV)
+			lines = append(lines, "  "+lipgloss.NewStyle().Foreground(ColorMidGreen).Render(rawSpark))
+		}
+	}
+
+	// Sensor readings, each on its own scale
+	if len(sensorHistory) > 0 {
+		lines = append(lines, labelSty.Render("  Sensor History:"))
+		for _, q := range bluetooth.Quantities {
+			values := sensorHistory[q]
+			if len(values) < 2 {
+				continue
+			}
+			minV, maxV := sparkRange(values)
+			scale := fmt.Sprintf(" %s..%s", q.Format(minV), q.Format(maxV))
+			sparkW := innerW - 14 - len(scale)
+			if sparkW < 10 {
+				sparkW = 10
+			}
+			spark := renderSparklineRange(values, sparkW, minV, maxV)
+			lines = append(lines, labelSty.Render(fmt.Sprintf("  %-10s", q))+
+				lipgloss.NewStyle().Foreground(ColorGreen).Render(spark)+valSty.Render(scale))
+		}
 	}
 
 	lines = append(lines, "")
@@ -127,6 +234,311 @@ func RenderDetailPanel(d *bluetooth.Device, width, height int, rssiHistory []flo
 	return StylePanelActive.Width(width - 2).Height(height - 2).Render(content)
 }
 
+// advSummary counts the fields of an advertisement for the collapsed
+// section.
+func advSummary(a *bluetooth.Advertisement) string {
+	parts := []string{
+		fmt.Sprintf("%d services", len(a.Services)),
+		fmt.Sprintf("%d mfr data", len(a.Manufacturer)),
+		fmt.Sprintf("%d svc data", len(a.ServiceData)),
+	}
+	if len(a.Raw) > 0 {
+		parts = append(parts, fmt.Sprintf("%d bytes", len(a.Raw)))
+	} else {
+		parts = append(parts, "partial")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// advertisementFields returns the detail rows for every AD field, each
+// raw value followed by its decoded meaning.
+func advertisementFields(a *bluetooth.Advertisement) []struct{ label, value string } {
+	var fields []struct{ label, value string }
+	if a.LocalName != "" {
+		kind := "complete"
+		if a.ShortName {
+			kind = "shortened"
+		}
+		fields = append(fields, struct{ label, value string }{"Adv name", fmt.Sprintf("%q (%s)", a.LocalName, kind)})
+	}
+	if a.HasFlags {
+		fields = append(fields, struct{ label, value string }{"Flags", fmt.Sprintf("0x%02X %s", uint8(a.Flags), a.Flags)})
+	}
+	if a.HasConnectable {
+		connectable := "no"
+		if a.Connectable {
+			connectable = "yes"
+		}
+		fields = append(fields, struct{ label, value string }{"Connect", connectable})
+	}
+	if a.HasTxPower {
+		fields = append(fields, struct{ label, value string }{"TX power", fmt.Sprintf("%d dBm", a.TxPower)})
+	}
+	if a.HasAppearance {
+		value := fmt.Sprintf("0x%04X", a.Appearance)
+		if name := bluetooth.LookupAppearance(a.Appearance); name != "" {
+			value += " " + name
+		}
+		fields = append(fields, struct{ label, value string }{"Category", value})
+	}
+	for _, s := range a.Services {
+		value := s.UUID
+		if name := s.Name(); name != "" {
+			value += " " + name
+		}
+		if !s.Complete && len(a.Raw) > 0 { // Unknown without the raw bytes
+			value += " (incomplete list)"
+		}
+		fields = append(fields, struct{ label, value string }{"Service", value})
+	}
+	for _, m := range a.Manufacturer {
+		company := bluetooth.LookupManufacturer(m.CompanyID)
+		if company == "" {
+			company = "unknown"
+		}
+		fields = append(fields, struct{ label, value string }{"Mfr data", fmt.Sprintf("0x%04X %s: %s", m.CompanyID, company, m.Data)})
+	}
+	for _, sd := range a.ServiceData {
+		uuid := sd.UUID
+		if name := sd.Name(); name != "" {
+			uuid += " " + name
+		}
+		fields = append(fields, struct{ label, value string }{"Svc data", fmt.Sprintf("%s: %s", uuid, sd.Data)})
+	}
+	for _, f := range a.Other {
+		name := f.Name()
+		if name == "" {
+			name = "unassigned"
+		}
+		fields = append(fields, struct{ label, value string }{fmt.Sprintf("AD 0x%02X", f.Type), fmt.Sprintf("%s: %s", name, f.Data)})
+	}
+	if len(a.Raw) > 0 {
+		fields = append(fields, struct{ label, value string }{"Raw", a.Raw.String()})
+	} else {
+		// BlueZ hands out decoded fields only, without the PDU type
+		fields = append(fields, struct{ label, value string }{"Partial", "decoded by BlueZ; connectable and unknown AD types not reported"})
+	}
+	return fields
+}
+
+// wrapValue splits s into chunks of at most width runes.
+func wrapValue(s string, width int) []string {
+	r := []rune(s)
+	var chunks []string
+	for len(r) > width {
+		chunks = append(chunks, string(r[:width]))
+		r = r[width:]
+	}
+	return append(chunks, string(r))
+}
+
+// beaconFields returns the detail rows for a decoded iBeacon/Eddystone frame.
+func beaconFields(b *bluetooth.Beacon) []struct{ label, value string } {
+	fields := []struct{ label, value string }{
+		{"Beacon", b.Format.String()},
+	}
+	if b.UUID != "" {
+		fields = append(fields,
+			struct{ label, value string }{"UUID", b.UUID},
+			struct{ label, value string }{"Major", fmt.Sprintf("%d", b.Major)},
+			struct{ label, value string }{"Minor", fmt.Sprintf("%d", b.Minor)},
+		)
+	}
+	if b.Namespace != "" {
+		fields = append(fields,
+			struct{ label, value string }{"Namespace", b.Namespace},
+			struct{ label, value string }{"Instance", b.Instance},
+		)
+	}
+	if b.URL != "" {
+		fields = append(fields, struct{ label, value string }{"URL", b.URL})
+	}
+	if b.EID != "" {
+		fields = append(fields, struct{ label, value string }{"EID", b.EID})
+	}
+	if b.HasMeasuredPower {
+		fields = append(fields, struct{ label, value string }{
+			"Tx @1m", fmt.Sprintf("%d dBm", b.MeasuredPower),
+		})
+	}
+	if b.HasTelemetry {
+		fields = append(fields,
+			struct{ label, value string }{"Battery", fmt.Sprintf("%d mV", b.BatteryMV)},
+			struct{ label, value string }{"Temp", fmt.Sprintf("%.1f C", b.Temperature)},
+			struct{ label, value string }{"Uptime", b.Uptime.Truncate(time.Second).String()},
+		)
+	}
+	return fields
+}
+
+// continuityFields returns the detail rows for decoded Apple Continuity
+// messages.
+func continuityFields(c *bluetooth.Continuity) []struct{ label, value string } {
+	class := c.Class
+	if class == "" {
+		class = "Apple device"
+	}
+	fields := []struct{ label, value string }{
+		{"Apple", class},
+		{"Messages", strings.Join(c.Messages, ", ")},
+	}
+	if c.Activity != "" {
+		activity := c.Activity
+		if c.WiFi != "" {
+			activity += ", Wi-Fi " + c.WiFi
+		}
+		fields = append(fields, struct{ label, value string }{"Activity", activity})
+	}
+	if c.Clipboard {
+		fields = append(fields, struct{ label, value string }{"Clipboard", "copied data offered via Handoff"})
+	}
+	if c.Action != "" {
+		fields = append(fields, struct{ label, value string }{"Action", c.Action})
+	}
+	if c.AirPlayIP != "" {
+		fields = append(fields, struct{ label, value string }{"AirPlay", c.AirPlayIP})
+	}
+	if a := c.AirPods; a != nil {
+		lid := "closed"
+		if a.LidOpen {
+			lid = "open"
+		}
+		fields = append(fields,
+			struct{ label, value string }{"Battery", fmt.Sprintf("L %s  R %s  case %s",
+				podLevel(a.Left, a.LeftCharging), podLevel(a.Right, a.RightCharging), podLevel(a.Case, a.CaseCharging))},
+			struct{ label, value string }{"Lid", lid},
+		)
+	}
+	return fields
+}
+
+// sensorFields returns the detail rows for sensor beacon readings.
+func sensorFields(s *bluetooth.Sensor) []struct{ label, value string } {
+	fields := []struct{ label, value string }{{"Sensor", s.Format.String()}}
+	for _, q := range bluetooth.Quantities {
+		if v, ok := s.Readings[q]; ok {
+			fields = append(fields, struct{ label, value string }{q.String(), q.Format(v)})
+		}
+	}
+	return fields
+}
+
+// classFields returns the detail rows for a Class of Device.
+func classFields(c bluetooth.DeviceClass) []struct{ label, value string } {
+	fields := []struct{ label, value string }{
+		{"Class", fmt.Sprintf("%s (0x%06X)", c, uint32(c))},
+	}
+	if services := c.Services(); len(services) > 0 {
+		fields = append(fields, struct{ label, value string }{"Services", strings.Join(services, ", ")})
+	}
+	return fields
+}
+
+// microsoftFields returns the detail rows for a CDP or Swift Pair beacon.
+func microsoftFields(m *bluetooth.MicrosoftBeacon) []struct{ label, value string } {
+	if !m.SwiftPair {
+		return []struct{ label, value string }{{"CDP", m.DeviceType}}
+	}
+	fields := []struct{ label, value string }{{"Swift Pair", "offers to pair with Windows"}}
+	if m.Name != "" {
+		fields = append(fields, struct{ label, value string }{"Pair name", m.Name})
+	}
+	if m.ClassOfDevice != 0 {
+		fields = append(fields, struct{ label, value string }{"Icon", m.ClassOfDevice.String()})
+	}
+	return fields
+}
+
+// fastPairFields returns the detail rows for a Fast Pair advertisement.
+func fastPairFields(f *bluetooth.FastPair) []struct{ label, value string } {
+	var fields []struct{ label, value string }
+	if f.ModelID != "" {
+		model := f.ModelID
+		if f.Model != "" {
+			model = fmt.Sprintf("%s (%s)", f.Model, f.ModelID)
+		}
+		fields = append(fields, struct{ label, value string }{"Fast Pair", model})
+	}
+	state := "discoverable"
+	switch {
+	case f.AccountKeyFilter && f.ShowUI:
+		state = "paired, offers to pair more phones"
+	case f.AccountKeyFilter:
+		state = "paired"
+	case f.ModelID == "" || !f.ShowUI:
+		state = "not discoverable"
+	}
+	return append(fields, struct{ label, value string }{"Pairing", state})
+}
+
+// podLevel formats an AirPods battery level, marking charging with "+".
+func podLevel(pct int, charging bool) string {
+	s := "--"
+	if pct >= 0 {
+		s = fmt.Sprintf("%d%%", pct)
+	}
+	if charging {
+		s += "+"
+	}
+	return s
+}
+
+// addressFields returns the detail rows about the device address and, for
+// rotating addresses, how the device is recognized across rotations.
+func addressFields(d *bluetooth.Device) []struct{ label, value string } {
+	kind := d.AddressType.String()
+	if d.AddressType.Rotating() {
+		kind += " (rotates)"
+	}
+	fields := []struct{ label, value string }{{"Addr type", kind}}
+	if d.Identity != "" {
+		fields = append(fields, struct{ label, value string }{"Identity", d.Identity + " (resolved by IRK)"})
+		if d.Name != "" && d.Name != d.Identity {
+			fields = append(fields, struct{ label, value string }{"Adv name", d.Name})
+		}
+	}
+	if n := len(d.Addresses); n > 1 {
+		fields = append(fields, struct{ label, value string }{"Linked", fmt.Sprintf("%d addresses, first %s", n, d.Addresses[0])})
+	}
+	if len(d.Fingerprint) >= 8 {
+		fields = append(fields, struct{ label, value string }{"Shape", d.Fingerprint[:8]})
+	}
+	if d.AdvInterval > 0 {
+		fields = append(fields, struct{ label, value string }{"Adv every", d.AdvInterval.Round(10 * time.Millisecond).String()})
+	}
+	return fields
+}
+
+// trackerFields returns the detail rows for a tracking tag and, if the
+// monitor follows it, how long and where it has been around.
+func trackerFields(t *bluetooth.Tracker, track *tracking.Track) []struct{ label, value string } {
+	fields := []struct{ label, value string }{
+		{"Tracker", fmt.Sprintf("%s (%s)", t.Label(), t.Kind)},
+	}
+	if t.State != "" {
+		fields = append(fields, struct{ label, value string }{"State", t.State})
+	}
+	if t.Battery != "" {
+		fields = append(fields, struct{ label, value string }{"Battery", t.Battery})
+	}
+	if track == nil {
+		return fields
+	}
+	places := "unknown (no WiFi)"
+	if track.Places > 0 {
+		places = fmt.Sprintf("%d", track.Places)
+	}
+	fields = append(fields,
+		struct{ label, value string }{"Around", fmt.Sprintf("%s in %d windows, places %s",
+			track.Duration().Truncate(time.Second), track.Windows, places)},
+		struct{ label, value string }{"Addresses", fmt.Sprintf("%d", len(track.MACs))},
+	)
+	if track.Alert {
+		fields = append(fields, struct{ label, value string }{"Alert", "FOLLOWING YOU"})
+	}
+	return fields
+}
+
 func renderSignalBar(rssi float64, width int) string {
 	// Map RSSI -100..-30 to 0..width filled bars
 	ratio := (rssi + 100.0) / 70.0
@@ -144,24 +556,31 @@ func renderSignalBar(rssi float64, width int) string {
 	return StyleHelp.Render("[") + filledPart + emptyPart + StyleHelp.Render("]")
 }
 
-func renderSparkline(values []float64, width int) string {
+// sparkRange returns the combined min/max of all series.
+func sparkRange(series ...[]float64) (minV, maxV float64) {
+	first := true
+	for _, values := range series {
+		for _, v := range values {
+			if first || v < minV {
+				minV = v
+			}
+			if first || v > maxV {
+				maxV = v
+			}
+			first = false
+		}
+	}
+	return minV, maxV
+}
+
+// renderSparklineRange draws values scaled to [minV, maxV].
+func renderSparklineRange(values []float64, width int, minV, maxV float64) string {
 	if len(values) == 0 {
 		return ""
 	}
 
 	chars := []byte{'_', '.', '-', '~', '^'}
 
-	// Find min/max for scaling
-	minV, maxV := values[0], values[0]
-	for _, v := range values {
-		if v < minV {
-			minV = v
-		}
-		if v > maxV {
-			maxV = v
-		}
-	}
-
 	rng := maxV - minV
 	if rng < 1 {
 		rng = 1
@@ -227,3 +646,26 @@ func max(a, b int) int {
 	}
 	return b
 }
+
+// adapterFields lists the reading of every adapter that heard d, strongest
+// first, marking the one the merged RSSI comes from.
+func adapterFields(d *bluetooth.Device) []struct{ label, value string } {
+	ids := make([]string, 0, len(d.Adapters))
+	for id := range d.Adapters {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return d.Adapters[ids[i]].RSSI > d.Adapters[ids[j]].RSSI
+	})
+
+	var fields []struct{ label, value string }
+	for _, id := range ids {
+		r := d.Adapters[id]
+		value := fmt.Sprintf("%d dBm (raw %d, %s)", int(r.RSSI), int(r.RawRSSI), formatLastSeen(r.LastSeen))
+		if id == d.BestAdapter {
+			value += " *"
+		}
+		fields = append(fields, struct{ label, value string }{id, value})
+	}
+	return fields
+}
@@ -29,7 +29,8 @@ var hiddenDevSty = lipgloss.NewStyle().
 
 // RenderDeviceList renders the scrollable device list panel with cursor and visibility controls.
 // The filter bar stays fixed at the top; only the device entries scroll.
-func RenderDeviceList(devices []*bluetooth.Device, width, height int, cursorIndex int, hiddenDevices map[string]bool, isolateMAC string, filter FilterState) string {
+// following holds the addresses of tracking tags that raised an alert.
+func RenderDeviceList(devices []*bluetooth.Device, width, height int, cursorIndex int, hiddenDevices map[string]bool, isolateMAC string, following map[string]bool, filter FilterState) string {
 	innerW := width - 4
 	if innerW < 10 {
 		innerW = 10
@@ -79,7 +80,7 @@ func RenderDeviceList(devices []*bluetooth.Device, width, height int, cursorInde
 			isHidden := hiddenDevices[devices[i].MAC]
 			isIsolated := devices[i].MAC == isolateMAC
 
-			entry := renderDeviceEntryFull(devices[i], innerW, isCursor, isHidden, isIsolated)
+			entry := renderDeviceEntryFull(devices[i], innerW, isCursor, isHidden, isIsolated, following[devices[i].MAC])
 			for _, l := range entry {
 				if count >= devSpace {
 					break
@@ -128,12 +129,12 @@ func RenderDeviceList(devices []*bluetooth.Device, width, height int, cursorInde
 	return strings.Join(outLines, "\n")
 }
 
-func renderDeviceEntryFull(d *bluetooth.Device, maxW int, isCursor, isHidden, isIsolated bool) []string {
+func renderDeviceEntryFull(d *bluetooth.Device, maxW int, isCursor, isHidden, isIsolated, isFollowing bool) []string {
 	symbol := "*"
 	tag := "[BLE]"
 	switch d.Type {
 	case bluetooth.DeviceTypeClassic:
-		symbol = "B"
+		symbol = d.ClassOfDevice.Glyph()
 		tag = "[CLS]"
 	case bluetooth.DeviceTypeWiFi:
 		symbol = "W"
@@ -164,7 +165,7 @@ func renderDeviceEntryFull(d *bluetooth.Device, maxW int, isCursor, isHidden, is
 		cursor = ">>"
 	}
 
-	mac := d.MAC
+	mac := addressLabel(d)
 	if len(mac) > maxW-8 {
 		mac = mac[:maxW-8]
 	}
@@ -174,11 +175,17 @@ func renderDeviceEntryFull(d *bluetooth.Device, maxW int, isCursor, isHidden, is
 
 	rawLine1 := fmt.Sprintf("%s %s %s %s %s %s", cursor, check, symbol, name, iso, tag)
 	rawLine2 := fmt.Sprintf("       %s", mac)
+	if v := vendorLabel(d, maxW-9-len(mac)); v != "" {
+		rawLine2 += "  " + v
+	}
 	line3Extra := ""
 	if d.Type == bluetooth.DeviceTypeWiFi && d.Band() != "" {
 		line3Extra = fmt.Sprintf("  %s ch%d", d.Band(), d.Channel)
 	}
-	rawLine3 := fmt.Sprintf("       %s  %s%s", rssiStr, distStr, line3Extra)
+	if class := d.ClassOfDevice.Label(); class != "" {
+		line3Extra = "  " + class
+	}
+	rawLine3 := fmt.Sprintf("       %s  %s%s%s", rssiStr, distStr, line3Extra, trackerTag(d, isFollowing))
 
 	// Truncate to maxW to prevent line wrapping inside the panel
 	rawLine1 = truncRaw(rawLine1, maxW)
@@ -204,15 +211,53 @@ func renderDeviceEntryFull(d *bluetooth.Device, maxW int, isCursor, isHidden, is
 		}
 	}
 
-	return renderNormalEntry(d, rawLine1, rawLine2, rawLine3, maxW, isIsolated)
+	return renderNormalEntry(d, rawLine1, rawLine2, rawLine3, maxW, isIsolated, isFollowing)
 }
 
-func renderNormalEntry(d *bluetooth.Device, raw1, raw2, raw3 string, maxW int, isIsolated bool) []string {
+// addressLabel returns the address last heard, with the number of earlier
+// addresses linked to the device.
+func addressLabel(d *bluetooth.Device) string {
+	addr := d.Address
+	if addr == "" {
+		addr = d.MAC
+	}
+	if n := len(d.Addresses) - 1; n > 0 {
+		addr += fmt.Sprintf(" +%d", n)
+	}
+	return addr
+}
+
+// vendorLabel returns the OUI vendor shown after the address, cut to
+// width, or "" if unknown or there is no room.
+func vendorLabel(d *bluetooth.Device, width int) string {
+	v := d.Vendor
+	if width < 4 {
+		return ""
+	}
+	if len(v) > width {
+		v = v[:width]
+	}
+	return v
+}
+
+// trackerTag marks tracking tags on the third line of an entry.
+func trackerTag(d *bluetooth.Device, following bool) string {
+	switch {
+	case following:
+		return "  FOLLOWING"
+	case d.Tracker != nil:
+		return "  TAG"
+	default:
+		return ""
+	}
+}
+
+func renderNormalEntry(d *bluetooth.Device, raw1, raw2, raw3 string, maxW int, isIsolated, isFollowing bool) []string {
 	symbol := StyleDeviceTypeBLE.Render("*")
 	typeTag := StyleDeviceTypeBLE.Render("[BLE]")
 	switch d.Type {
 	case bluetooth.DeviceTypeClassic:
-		symbol = StyleDeviceTypeClassic.Render("B")
+		symbol = StyleDeviceTypeClassic.Render(d.ClassOfDevice.Glyph())
 		typeTag = StyleDeviceTypeClassic.Render("[CLS]")
 	case bluetooth.DeviceTypeWiFi:
 		symbol = StyleDeviceTypeWiFi.Render("W")
@@ -234,7 +279,7 @@ func renderNormalEntry(d *bluetooth.Device, raw1, raw2, raw3 string, maxW int, i
 		iso = StyleIsolateMarker.Render("!")
 	}
 
-	mac := d.MAC
+	mac := addressLabel(d)
 	if len(mac) > maxW-8 {
 		mac = mac[:maxW-8]
 	}
@@ -244,11 +289,24 @@ func renderNormalEntry(d *bluetooth.Device, raw1, raw2, raw3 string, maxW int, i
 
 	line1 := fmt.Sprintf("   %s %s %s %s %s", check, symbol, StyleDeviceName.Render(name), iso, typeTag)
 	line2 := fmt.Sprintf("       %s", StyleDeviceMAC.Render(mac))
+	if v := vendorLabel(d, maxW-9-len(mac)); v != "" {
+		line2 += "  " + StyleDeviceDist.Render(v)
+	}
 	bandExtra := ""
 	if d.Type == bluetooth.DeviceTypeWiFi && d.Band() != "" {
 		bandExtra = StyleDeviceTypeWiFi.Render(fmt.Sprintf("  %s ch%d", d.Band(), d.Channel))
 	}
+	if class := d.ClassOfDevice.Label(); class != "" {
+		bandExtra = StyleDeviceTypeClassic.Render("  " + class)
+	}
 	line3 := fmt.Sprintf("       %s  %s", StyleDeviceRSSI.Render(rssiStr), StyleDeviceDist.Render(distStr)) + bandExtra
+	if tag := trackerTag(d, isFollowing); tag != "" {
+		sty := StyleIsolateMarker
+		if isFollowing {
+			sty = StyleTrackerAlert
+		}
+		line3 += "  " + sty.Render(tag[2:])
+	}
 
 	return []string{line1, line2, line3, ""}
 }
@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"ble-radar.klederson.com/internal/localization"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// RenderFoxHuntPanel renders the walk-around direction finding mode in place
+// of the radar. status is a one-line message such as an estimate error.
+func RenderFoxHuntPanel(f *localization.FoxHunt, width, height int, now time.Time, status string) string {
+	innerW := width - 4
+	if innerW < 20 {
+		innerW = 20
+	}
+
+	title := StylePanelTitle.Render("FOX HUNT")
+	escHint := StyleHelp.Render("[ESC]")
+	titleLine := title + strings.Repeat(" ", max(0, innerW-lipgloss.Width(title)-lipgloss.Width(escHint))) + escHint
+	sep := StyleRadarRing.Render(strings.Repeat("-", innerW))
+
+	labelSty := lipgloss.NewStyle().Foreground(ColorMidGreen)
+	valSty := lipgloss.NewStyle().Foreground(ColorMatrixGreen).Bold(true)
+
+	name := f.Name
+	if name == "" {
+		name = "[unnamed]"
+	}
+	live := "no signal yet"
+	if rssi, ok := f.Last(); ok {
+		live = fmt.Sprintf("%d dBm", int(rssi))
+	}
+	lines := []string{
+		titleLine, sep, "",
+		labelSty.Render("  Device    ") + valSty.Render(name),
+		labelSty.Render("  MAC       ") + valSty.Render(f.MAC),
+		labelSty.Render("  Signal    ") + valSty.Render(live),
+		"",
+		labelSty.Render("  Facing    ") + StyleIsolateMarker.Render(fmt.Sprintf("%03.0f° %s", f.Heading*180/math.Pi, angleToDir(f.Heading))),
+		StyleHelp.Render("  Hold the adapter in front of you, turn to face a direction,"),
+		StyleHelp.Render("  set it with [←/→] and press [Space] to mark. Cover the full circle."),
+		"",
+	}
+
+	// Marks with their averaged RSSI
+	readings := f.Readings(now)
+	if len(readings) > 0 {
+		barW := innerW - 34
+		if barW < 10 {
+			barW = 10
+		}
+		lines = append(lines, labelSty.Render("  Marks:"))
+		for _, r := range readings {
+			head := fmt.Sprintf("    %03.0f° %-2s ", r.Angle*180/math.Pi, angleToDir(r.Angle))
+			if r.Samples == 0 {
+				lines = append(lines, valSty.Render(head)+StyleHelp.Render(" no samples"))
+				continue
+			}
+			value := fmt.Sprintf(" %6.1f dBm (%d)", r.RSSI, r.Samples)
+			if !r.Complete {
+				value += " ..."
+			}
+			lines = append(lines, valSty.Render(head)+renderSignalBar(r.RSSI, barW)+valSty.Render(value))
+		}
+		lines = append(lines, "")
+	}
+
+	// Estimate
+	if b, err := f.Estimate(now); err == nil {
+		lines = append(lines,
+			labelSty.Render("  Bearing   ")+valSty.Render(fmt.Sprintf("%03.0f° %s  confidence %.0f%%",
+				b.Angle*180/math.Pi, angleToDir(b.Angle), b.Confidence*100)),
+			labelSty.Render("  Fit       ")+valSty.Render(fmt.Sprintf("peak ±%.1f dB, misfit %.1f dB RMS", b.Amplitude, b.Residual)),
+			"",
+			StyleHelp.Render("  [Enter] pin bearing on radar  [U] undo mark  [X] clear pin"),
+		)
+	} else if len(readings) > 0 {
+		lines = append(lines, StyleHelp.Render("  "+err.Error()))
+	}
+
+	if status != "" {
+		lines = append(lines, "", StyleIsolateMarker.Render("  "+status))
+	}
+
+	for len(lines) < height-2 {
+		lines = append(lines, "")
+	}
+	if len(lines) > height-2 {
+		lines = lines[:height-2]
+	}
+
+	content := strings.Join(lines, "\n")
+	return StylePanelActive.Width(width - 2).Height(height - 2).Render(content)
+}
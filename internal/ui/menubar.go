@@ -8,12 +8,37 @@ import (
 )
 
 // RenderMenuBar renders the top menu bar with context-aware key hints.
-func RenderMenuBar(width int, adapter string, scanning bool, detailOpen bool, filterActive bool) string {
+func RenderMenuBar(width int, adapter string, scanning bool, detailOpen bool, filterActive bool, calibrating bool, foxHunting bool, surveying bool) string {
 	title := fmt.Sprintf(" %s v%s ", config.AppName, config.AppVersion)
 
 	var keys []struct{ key, label string }
 
-	if filterActive {
+	if calibrating {
+		keys = []struct{ key, label string }{
+			{"0-9", " distance"},
+			{"Enter", " sample"},
+			{"G/D", " save"},
+			{"U", "ndo"},
+			{"Esc", " cancel"},
+		}
+	} else if foxHunting {
+		keys = []struct{ key, label string }{
+			{"←/→", " heading"},
+			{"Space", " mark"},
+			{"U", "ndo"},
+			{"Enter", " pin"},
+			{"X", " unpin"},
+			{"Esc", " cancel"},
+		}
+	} else if surveying {
+		keys = []struct{ key, label string }{
+			{"Tab", " field"},
+			{"Enter", " sample"},
+			{"^U", "ndo"},
+			{"^S", "ave"},
+			{"Esc", " close"},
+		}
+	} else if filterActive {
 		keys = []struct{ key, label string }{
 			{"Type", " to search"},
 			{"Esc", " done"},
@@ -22,6 +47,7 @@ func RenderMenuBar(width int, adapter string, scanning bool, detailOpen bool, fi
 		keys = []struct{ key, label string }{
 			{"Esc", " close"},
 			{"j/k", " navigate"},
+			{"A", "dvertisement"},
 			{"Q", "uit"},
 		}
 	} else {
@@ -31,6 +57,11 @@ func RenderMenuBar(width int, adapter string, scanning bool, detailOpen bool, fi
 			{"I", "solate"},
 			{"/", " search"},
 			{"1/2/3", " filter"},
+			{"+/-", " zoom"},
+			{"A", "uto range"},
+			{"C", "alibrate"},
+			{"F", "ox hunt"},
+			{"T", "rilaterate"},
 			{"Q", "uit"},
 		}
 	}
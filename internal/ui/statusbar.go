@@ -6,8 +6,9 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
-// RenderStatusBar renders the bottom status bar.
-func RenderStatusBar(width int, scanning bool, total, ble, classic, wifi int, sweepDeg float64, maxRange float64) string {
+// RenderStatusBar renders the bottom status bar. alert, if not empty, is
+// shown as a warning after the counters, e.g. a tracker following the user.
+func RenderStatusBar(width int, scanning bool, total, ble, classic, wifi int, sweepDeg float64, maxRange float64, autoRange bool, alert string) string {
 	status := ""
 	if scanning {
 		status = StyleStatusScanning.Render("[SCANNING]")
@@ -15,10 +16,17 @@ func RenderStatusBar(width int, scanning bool, total, ble, classic, wifi int, sw
 		status = StyleStatusPaused.Render("[PAUSED]")
 	}
 
-	info := fmt.Sprintf(" Devices: %d  BLE: %d  CLS: %d  WiFi: %d  Sweep: %ddeg  Range: 0-%.0fm",
-		total, ble, classic, wifi, int(sweepDeg), maxRange)
+	rangeMode := ""
+	if autoRange {
+		rangeMode = " (auto)"
+	}
+	info := fmt.Sprintf(" Devices: %d  BLE: %d  CLS: %d  WiFi: %d  Sweep: %ddeg  Range: 0-%gm%s",
+		total, ble, classic, wifi, int(sweepDeg), maxRange, rangeMode)
 
 	content := status + StyleStatusBar.Foreground(ColorGreen).Render(info)
+	if alert != "" {
+		content += "  " + StyleTrackerAlert.Render(" ! "+alert+" ")
+	}
 
 	gap := width - lipgloss.Width(content)
 	if gap < 0 {
@@ -133,4 +133,9 @@ var (
 	StyleIsolateMarker = lipgloss.NewStyle().
 				Foreground(ColorWarning).
 				Bold(true)
+
+	StyleTrackerAlert = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#000000")).
+				Background(ColorWarning).
+				Bold(true)
 )
@@ -0,0 +1,250 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"ble-radar.klederson.com/internal/survey"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxSurveyRows is how many observations the list shows before eliding.
+const maxSurveyRows = 5
+
+// RenderSurveyPanel renders the trilateration survey in place of the radar:
+// the position form, the recorded observations and a floor plan of the
+// positions with the estimate and its error ellipse. status is a one-line
+// message such as a save confirmation.
+func RenderSurveyPanel(f *survey.Form, width, height int, status string) string {
+	innerW := width - 4
+	if innerW < 20 {
+		innerW = 20
+	}
+
+	title := StylePanelTitle.Render("TRILATERATION SURVEY")
+	escHint := StyleHelp.Render("[ESC]")
+	titleLine := title + strings.Repeat(" ", max(0, innerW-lipgloss.Width(title)-lipgloss.Width(escHint))) + escHint
+	sep := StyleRadarRing.Render(strings.Repeat("-", innerW))
+
+	labelSty := lipgloss.NewStyle().Foreground(ColorMidGreen)
+	valSty := lipgloss.NewStyle().Foreground(ColorMatrixGreen).Bold(true)
+
+	s := f.Survey
+	name := s.Name
+	if name == "" {
+		name = "[unnamed]"
+	}
+	live := "no signal yet"
+	if rssi, ok := f.Last(); ok {
+		live = fmt.Sprintf("%d dBm", int(rssi))
+	}
+	lines := []string{
+		titleLine, sep,
+		labelSty.Render("  Device    ") + valSty.Render(name) + labelSty.Render("  "+s.MAC),
+		labelSty.Render("  Signal    ") + valSty.Render(live) +
+			labelSty.Render(fmt.Sprintf("  model %.0f dBm @1m, n=%.1f", s.Model.MeasuredPower, s.Model.Exponent)),
+		"",
+	}
+
+	// Current step
+	if o, ok := f.Current(); ok {
+		n, target := f.Progress()
+		lines = append(lines,
+			valSty.Render(fmt.Sprintf("  Sampling at %s (%.1f, %.1f) ... %d/%d", o.Name, o.X, o.Y, n, target)),
+			StyleHelp.Render("  Hold the adapter still. [Ctrl+U] abort this position"),
+		)
+	} else {
+		labels := [...]string{"Name", "X", "Y"}
+		form := " "
+		for i, in := range f.Inputs {
+			form += " " + labelSty.Render(labels[i]+": ")
+			if survey.Field(i) == f.Field {
+				form += StyleFilterActive.Render(in + "_")
+			} else {
+				form += valSty.Render(in)
+			}
+		}
+		lines = append(lines,
+			labelSty.Render("  Stand at a known spot, enter its name and coordinates (m), press Enter"),
+			form+labelSty.Render(" m"),
+		)
+	}
+	lines = append(lines, "")
+
+	// Recorded observations
+	if n := len(s.Observations); n > 0 {
+		lines = append(lines, labelSty.Render("  Positions:"))
+		first := 0
+		if n > maxSurveyRows {
+			first = n - maxSurveyRows
+			lines = append(lines, StyleHelp.Render(fmt.Sprintf("    ... %d earlier", first)))
+		}
+		for i := first; i < n; i++ {
+			o := s.Observations[i]
+			lines = append(lines, valSty.Render(fmt.Sprintf("    %s %-10.10s (%5.1f,%5.1f)  %6.1f dBm  %5.1fm  (%d)",
+				surveyGlyph(i), o.Name, o.X, o.Y, o.Mean(), survey.Range(o, s.Model), len(o.Samples))))
+		}
+		lines = append(lines, "")
+	}
+
+	// Estimate
+	var footer []string
+	fix, err := s.Solve()
+	if err == nil {
+		footer = append(footer,
+			labelSty.Render("  Position  ")+valSty.Render(fmt.Sprintf("(%.1f, %.1f) m", fix.Position.X, fix.Position.Y)),
+			labelSty.Render("  95% error ")+valSty.Render(fmt.Sprintf("±%.1fm x ±%.1fm, long axis %03.0f°  misfit %.1fm RMS",
+				fix.Ellipse.Major, fix.Ellipse.Minor, fix.Ellipse.Bearing()*180/math.Pi, fix.RMS)),
+		)
+	} else if len(s.Observations) > 0 {
+		footer = append(footer, StyleHelp.Render("  "+err.Error()))
+	}
+	footer = append(footer, StyleHelp.Render("  [Tab] next field  [Ctrl+U] undo  [Ctrl+S] save survey"))
+	if status != "" {
+		footer = append(footer, StyleIsolateMarker.Render("  "+status))
+	}
+
+	// Floor plan in whatever height is left
+	planH := height - 2 - len(lines) - len(footer) - 1
+	if len(s.Observations) > 0 && planH >= 5 {
+		var fp *survey.Fix
+		if err == nil {
+			fp = &fix
+		}
+		lines = append(lines, renderFloorPlan(s, fp, innerW-2, planH)...)
+		lines = append(lines, "")
+	}
+	lines = append(lines, footer...)
+
+	for len(lines) < height-2 {
+		lines = append(lines, "")
+	}
+	if len(lines) > height-2 {
+		lines = lines[:height-2]
+	}
+
+	content := strings.Join(lines, "\n")
+	return StylePanelActive.Width(width - 2).Height(height - 2).Render(content)
+}
+
+// surveyGlyph labels the i-th observation on the floor plan.
+func surveyGlyph(i int) string {
+	const glyphs = "123456789abcdefghijklmnopqrstuvwxyz"
+	if i < len(glyphs) {
+		return glyphs[i : i+1]
+	}
+	return "+"
+}
+
+// planCell is one character of the floor plan.
+type planCell struct {
+	ch  string
+	sty lipgloss.Style
+}
+
+// renderFloorPlan draws the survey positions, the estimate (X) and its
+// error ellipse on a w x h-1 grid, north up, with terminal cells treated as
+// twice as tall as wide. The last line is the grid legend.
+func renderFloorPlan(s *survey.Survey, fix *survey.Fix, w, h int) []string {
+	h--
+	// Bounds of everything worth showing
+	minX, maxX := math.Inf(1), math.Inf(-1)
+	minY, maxY := math.Inf(1), math.Inf(-1)
+	extend := func(x, y float64) {
+		minX, maxX = math.Min(minX, x), math.Max(maxX, x)
+		minY, maxY = math.Min(minY, y), math.Max(maxY, y)
+	}
+	for _, o := range s.Observations {
+		extend(o.X, o.Y)
+	}
+	if fix != nil {
+		// Keep a huge ellipse from shrinking the positions to a dot.
+		r := math.Min(fix.Ellipse.Major, math.Max(maxX-minX, maxY-minY)+5)
+		extend(fix.Position.X-r, fix.Position.Y-r)
+		extend(fix.Position.X+r, fix.Position.Y+r)
+	}
+	spanX, spanY := math.Max(maxX-minX, 1), math.Max(maxY-minY, 1)
+	cx, cy := (minX+maxX)/2, (minY+maxY)/2
+
+	// Meters per column; a row is two columns tall.
+	scale := math.Max(spanX/float64(w-2), spanY/float64(2*(h-2)))
+	toCell := func(x, y float64) (int, int, bool) {
+		col := w/2 + int(math.Round((x-cx)/scale))
+		row := h/2 - int(math.Round((y-cy)/(2*scale)))
+		return col, row, col >= 0 && col < w && row >= 0 && row < h
+	}
+
+	grid := make([][]planCell, h)
+	for r := range grid {
+		grid[r] = make([]planCell, w)
+		for c := range grid[r] {
+			grid[r][c] = planCell{" ", StyleRadarDot}
+		}
+	}
+	put := func(x, y float64, ch string, sty lipgloss.Style) {
+		if c, r, ok := toCell(x, y); ok {
+			grid[r][c] = planCell{ch, sty}
+		}
+	}
+
+	// Grid dots at a round step
+	step := niceStep(scale * 8)
+	for gx := math.Floor((cx-scale*float64(w)/2)/step) * step; gx <= cx+scale*float64(w)/2; gx += step {
+		for gy := math.Floor((cy-scale*float64(h))/step) * step; gy <= cy+scale*float64(h); gy += step {
+			put(gx, gy, "·", StyleRadarDot)
+		}
+	}
+
+	if fix != nil {
+		e := fix.Ellipse
+		ellSty := lipgloss.NewStyle().Foreground(ColorWarning)
+		for i := 0; i < 360; i++ {
+			t := float64(i) * math.Pi / 180
+			u, v := e.Major*math.Cos(t), e.Minor*math.Sin(t)
+			x := fix.Position.X + u*math.Cos(e.Angle) - v*math.Sin(e.Angle)
+			y := fix.Position.Y + u*math.Sin(e.Angle) + v*math.Cos(e.Angle)
+			put(x, y, ".", ellSty)
+		}
+	}
+	for i, o := range s.Observations {
+		put(o.X, o.Y, surveyGlyph(i), StyleRadarBLEDevice)
+	}
+	if fix != nil {
+		put(fix.Position.X, fix.Position.Y, "X", StyleIsolateMarker)
+	}
+
+	lines := make([]string, 0, h+1)
+	for _, row := range grid {
+		var b strings.Builder
+		b.WriteString("  ")
+		for _, c := range row {
+			b.WriteString(c.sty.Render(c.ch))
+		}
+		lines = append(lines, b.String())
+	}
+	lines = append(lines, StyleHelp.Render(fmt.Sprintf("  · grid %s, north up  X estimate  . 95%% ellipse", formatStep(step))))
+	return lines
+}
+
+// niceStep rounds v up to 1, 2 or 5 times a power of ten.
+func niceStep(v float64) float64 {
+	if v <= 0 {
+		return 1
+	}
+	p := math.Pow(10, math.Floor(math.Log10(v)))
+	for _, m := range []float64{1, 2, 5, 10} {
+		if m*p >= v {
+			return m * p
+		}
+	}
+	return 10 * p
+}
+
+// formatStep formats a grid step in meters.
+func formatStep(m float64) string {
+	if m < 1 {
+		return fmt.Sprintf("%.0fcm", m*100)
+	}
+	return fmt.Sprintf("%.0fm", m)
+}
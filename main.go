@@ -1,18 +1,38 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strings"
+	"sync"
 
 	"ble-radar.klederson.com/internal/app"
+	"ble-radar.klederson.com/internal/bluetooth"
+	"ble-radar.klederson.com/internal/calibration"
+	"ble-radar.klederson.com/internal/config"
+	"ble-radar.klederson.com/internal/survey"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 )
 
 var (
-	flagDemo    bool
-	flagAdapter string
-	flagRange   float64
+	flagConfig      string
+	flagDemo        bool
+	flagAdapter     string
+	flagAdapters    []string
+	flagRange       float64
+	flagScanners    []string
+	flagRecord      string
+	flagReplay      string
+	flagReplaySpeed float64
+	flagReplayStep  bool
+	flagSmoothing   string
+	flagSmoothParms bluetooth.SmoothingParams
+	flagCalibration string
+
+	// cfg is the merged configuration: defaults, config files, then flags.
+	cfg *config.Config
 )
 
 func main() {
@@ -24,41 +44,258 @@ displaying them on a circular ASCII radar with a Matrix-inspired aesthetic.
 
 Requires sudo or CAP_NET_ADMIN capability for real Bluetooth scanning.
 Use --demo flag for demonstration mode without Bluetooth hardware.`,
-		RunE: run,
+		PersistentPreRunE: loadConfig,
+		RunE:              run,
 	}
 
-	rootCmd.Flags().BoolVar(&flagDemo, "demo", false, "Run in demo mode with fake devices (no Bluetooth required)")
-	rootCmd.Flags().StringVar(&flagAdapter, "adapter", "hci0", "Bluetooth adapter to use")
-	rootCmd.Flags().Float64Var(&flagRange, "range", 30.0, "Maximum radar range in meters")
+	def := config.Default()
+	rootCmd.PersistentFlags().StringVar(&flagConfig, "config", "",
+		"Config file (default: ble-radar/"+config.FileName+" in the XDG config dirs)")
+	rootCmd.PersistentFlags().BoolVar(&flagDemo, "demo", false, "Run in demo mode with fake devices (no Bluetooth required)")
+	rootCmd.PersistentFlags().StringVar(&flagAdapter, "adapter", def.Scanner.Adapter, "Bluetooth adapter to use")
+	rootCmd.PersistentFlags().StringSliceVar(&flagAdapters, "adapters", nil, "Scan BLE on several adapters at once (e.g. hci0,hci1)")
+	rootCmd.Flags().Float64Var(&flagRange, "range", def.Radar.MaxRange, "Maximum radar range in meters")
+	rootCmd.PersistentFlags().StringSliceVar(&flagScanners, "scanners", nil,
+		"Scanners to run (e.g. ble,wifi or -wifi to disable one); available: "+strings.Join(bluetooth.ScannerNames(), ", "))
+	rootCmd.PersistentFlags().StringVar(&flagRecord, "record", "", "Record every discovery to this session file")
+	rootCmd.PersistentFlags().StringVar(&flagReplay, "replay", "", "Replay a recorded session file instead of scanning")
+	rootCmd.PersistentFlags().Float64Var(&flagReplaySpeed, "replay-speed", 1.0, "Replay speed multiplier (0 = as fast as possible)")
+	rootCmd.PersistentFlags().BoolVar(&flagReplayStep, "replay-step", false, "Step through the replay one discovery at a time (press n)")
+
+	rootCmd.PersistentFlags().StringVar(&flagSmoothing, "smoothing", def.Smoothing.Method,
+		"RSSI smoothing method: "+strings.Join(bluetooth.SmoothingMethods, ", "))
+	rootCmd.PersistentFlags().Float64Var(&flagSmoothParms.Alpha, "ema-alpha", def.Smoothing.Alpha, "EMA weight of each new sample")
+	rootCmd.PersistentFlags().Float64Var(&flagSmoothParms.KalmanQ, "kalman-q", def.Smoothing.KalmanQ, "Kalman process noise (higher follows movement faster)")
+	rootCmd.PersistentFlags().Float64Var(&flagSmoothParms.KalmanR, "kalman-r", def.Smoothing.KalmanR, "Kalman measurement noise (higher smooths more)")
+	rootCmd.PersistentFlags().IntVar(&flagSmoothParms.MedianN, "median-window", def.Smoothing.MedianWindow, "Median filter window in samples")
+	rootCmd.PersistentFlags().StringVar(&flagCalibration, "calibration", calibration.DefaultPath(),
+		"Distance calibration profile file (written by the C calibration wizard)")
+
+	rootCmd.AddCommand(newScanCmd())
+	rootCmd.AddCommand(newAdaptersCmd())
+	rootCmd.AddCommand(newSurveyCmd())
+	rootCmd.AddCommand(newOUICmd())
 
 	if err := rootCmd.Execute(); err != nil {
 		os.Exit(1)
 	}
 }
 
+// loadConfig reads the config files and overlays the flags the user set
+// explicitly, so the command line always wins over the files.
+func loadConfig(cmd *cobra.Command, args []string) error {
+	c, _, err := config.Load(flagConfig)
+	if err != nil {
+		return err
+	}
+
+	flags := cmd.Flags()
+	if flags.Changed("adapter") {
+		c.Scanner.Adapter = flagAdapter
+	}
+	if flags.Changed("adapters") {
+		c.Scanner.Adapters = flagAdapters
+	}
+	if flags.Changed("range") {
+		c.Radar.MaxRange = flagRange
+	}
+	if flags.Changed("scanners") {
+		c.Scanner.Scanners = flagScanners
+	}
+	if flags.Changed("smoothing") {
+		c.Smoothing.Method = flagSmoothing
+	}
+	if flags.Changed("ema-alpha") {
+		c.Smoothing.Alpha = flagSmoothParms.Alpha
+	}
+	if flags.Changed("kalman-q") {
+		c.Smoothing.KalmanQ = flagSmoothParms.KalmanQ
+	}
+	if flags.Changed("kalman-r") {
+		c.Smoothing.KalmanR = flagSmoothParms.KalmanR
+	}
+	if flags.Changed("median-window") {
+		c.Smoothing.MedianWindow = flagSmoothParms.MedianN
+	}
+	if flags.Changed("calibration") || c.Calibration.Profile == "" {
+		c.Calibration.Profile = flagCalibration
+	}
+	if c.Survey.Dir == "" {
+		c.Survey.Dir = survey.DefaultDir()
+	}
+
+	if err := c.Validate(); err != nil {
+		return fmt.Errorf("invalid flags: %w", err)
+	}
+	cfg = c
+	return nil
+}
+
+// scannerSelection returns the registry names to run for the current flags.
+func scannerSelection() []string {
+	switch {
+	case flagReplay != "":
+		return []string{"replay"}
+	case flagDemo:
+		return []string{"mock"}
+	}
+	return cfg.Scanner.Scanners
+}
+
+func scannerOptions() bluetooth.ScannerOptions {
+	return bluetooth.ScannerOptions{
+		Adapter:          cfg.Scanner.Adapter,
+		Adapters:         cfg.Scanner.Adapters,
+		AdapterPositions: adapterPositions(),
+		ClassicInterval:  cfg.Scanner.ClassicInterval,
+		WiFiInterval:     cfg.Scanner.WiFiInterval,
+		ReplayPath:       flagReplay,
+		ReplaySpeed:      flagReplaySpeed,
+		ReplayStep:       flagReplayStep,
+	}
+}
+
+// adapterPositions returns the configured localization anchors.
+func adapterPositions() map[string]bluetooth.Point {
+	if len(cfg.Localization.Anchors) == 0 {
+		return nil
+	}
+	pos := make(map[string]bluetooth.Point, len(cfg.Localization.Anchors))
+	for id, p := range cfg.Localization.Anchors {
+		pos[id] = bluetooth.Point{X: p.X, Y: p.Y}
+	}
+	return pos
+}
+
+// newSmoothing builds the RSSI smoother factory from the configuration.
+func newSmoothing() (bluetooth.SmootherFactory, error) {
+	return bluetooth.NewSmootherFactory(cfg.Smoothing.Method, bluetooth.SmoothingParams{
+		Alpha:   cfg.Smoothing.Alpha,
+		KalmanQ: cfg.Smoothing.KalmanQ,
+		KalmanR: cfg.Smoothing.KalmanR,
+		MedianN: cfg.Smoothing.MedianWindow,
+	})
+}
+
+// newIdentities builds the IRK resolver for the configured known devices,
+// or returns nil when there are none.
+func newIdentities() (*bluetooth.IdentityResolver, error) {
+	if len(cfg.Identities) == 0 {
+		return nil, nil
+	}
+	keys := make([]bluetooth.IdentityKey, len(cfg.Identities))
+	for i, id := range cfg.Identities {
+		irk, err := id.Key()
+		if err != nil {
+			return nil, fmt.Errorf("identity %s: %w", id.Name, err)
+		}
+		keys[i] = bluetooth.IdentityKey{Name: id.Name, IRK: irk}
+	}
+	return bluetooth.NewIdentityResolver(keys)
+}
+
+// openRecorder creates the --record session file. The returned close
+// function flushes the recorder and closes the file; calls after the first
+// return the first result.
+func openRecorder() (*bluetooth.Recorder, func() error, error) {
+	if flagRecord == "" {
+		return nil, func() error { return nil }, nil
+	}
+	f, err := os.Create(flagRecord)
+	if err != nil {
+		return nil, nil, err
+	}
+	rec, err := bluetooth.NewRecorder(f)
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	var once sync.Once
+	var closeErr error
+	return rec, func() error {
+		once.Do(func() {
+			closeErr = rec.Close()
+			if cerr := f.Close(); closeErr == nil {
+				closeErr = cerr
+			}
+		})
+		return closeErr
+	}, nil
+}
+
 func run(cmd *cobra.Command, args []string) error {
-	model := app.New(flagDemo, flagAdapter)
+	smoothing, err := newSmoothing()
+	if err != nil {
+		return err
+	}
+	identities, err := newIdentities()
+	if err != nil {
+		return err
+	}
+	vendors, err := newVendors()
+	if err != nil {
+		return err
+	}
+
+	profiles, err := calibration.Load(cfg.Calibration.Profile)
+	if err != nil {
+		return fmt.Errorf("loading calibration: %w", err)
+	}
+
+	rec, closeRec, err := openRecorder()
+	if err != nil {
+		return err
+	}
+	defer closeRec()
+
+	model := app.New(app.Options{
+		Config:          cfg,
+		Demo:            flagDemo || flagReplay != "",
+		Scanners:        scannerSelection(),
+		ScannerOptions:  scannerOptions(),
+		Recorder:        rec,
+		Smoothing:       smoothing,
+		Identities:      identities,
+		Vendors:         vendors,
+		Calibration:     profiles,
+		CalibrationPath: cfg.Calibration.Profile,
+	})
 
 	p := tea.NewProgram(
 		model,
 		tea.WithAltScreen(),
 		tea.WithMouseCellMotion(),
-		tea.WithFPS(30),
+		tea.WithFPS(cfg.Radar.TargetFPS),
 	)
 
 	// Start scanners with reference to the tea program
 	if err := model.StartScanners(p); err != nil {
-		if !flagDemo {
-			fmt.Fprintf(os.Stderr, "\nError: %v\n\n", err)
-			fmt.Fprintln(os.Stderr, "Bluetooth scanning requires elevated permissions.")
-			fmt.Fprintln(os.Stderr, "Try one of:")
-			fmt.Fprintln(os.Stderr, "  sudo ./ble-radar")
-			fmt.Fprintln(os.Stderr, "  sudo setcap cap_net_admin+ep ./ble-radar")
-			fmt.Fprintln(os.Stderr, "  ./ble-radar --demo    (demo mode, no hardware needed)")
-			return err
-		}
+		printPermissionHelp(err)
+		return err
 	}
 
-	_, err := p.Run()
-	return err
+	if _, err := p.Run(); err != nil {
+		return err
+	}
+	return closeRec()
+}
+
+// printPermissionHelp reports a scanning error with hints on adapter
+// selection and permissions. Demo and replayed sessions need neither, so
+// their errors are printed alone.
+func printPermissionHelp(err error) {
+	fmt.Fprintf(os.Stderr, "\nError: %v\n\n", err)
+	if flagReplay != "" || flagDemo {
+		return
+	}
+	if errors.Is(err, bluetooth.ErrAdapterNotFound) {
+		fmt.Fprintln(os.Stderr, "Run 'ble-radar adapters' to list the available controllers,")
+		fmt.Fprintln(os.Stderr, "then pick one with --adapter or scanner.adapter in the config file.")
+		return
+	}
+	fmt.Fprintln(os.Stderr, "Bluetooth scanning requires elevated permissions.")
+	fmt.Fprintln(os.Stderr, "Try one of:")
+	fmt.Fprintln(os.Stderr, "  sudo ./ble-radar")
+	fmt.Fprintln(os.Stderr, "  sudo setcap cap_net_admin+ep ./ble-radar")
+	fmt.Fprintln(os.Stderr, "  ./ble-radar --demo    (demo mode, no hardware needed)")
 }
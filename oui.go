@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"ble-radar.klederson.com/internal/oui"
+	"github.com/spf13/cobra"
+)
+
+var flagOUIOutput string
+
+func newOUICmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "oui",
+		Short: "Manage the IEEE OUI vendor database",
+	}
+
+	update := &cobra.Command{
+		Use:   "update <file>...",
+		Short: "Rebuild the vendor database from IEEE registry CSVs",
+		Long: `Reads the IEEE registry CSVs (oui.csv, mam.csv, oui36.csv and iab.csv from
+https://standards-oui.ieee.org/, gzip-compressed or not) and writes the
+compressed database ble-radar looks vendors up in. The default output
+replaces the database built into the binary; pass
+--output internal/oui/oui.csv.gz to refresh the embedded one.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: runOUIUpdate,
+	}
+	update.Flags().StringVarP(&flagOUIOutput, "output", "o", oui.DefaultPath(), "Database file to write")
+
+	cmd.AddCommand(update)
+	return cmd
+}
+
+func runOUIUpdate(cmd *cobra.Command, args []string) error {
+	db := oui.New()
+	for _, path := range args {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		err = db.Read(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	if err := db.Save(flagOUIOutput); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %d assignments to %s\n", db.Len(), flagOUIOutput)
+	return nil
+}
+
+// newVendors loads the OUI database, preferring one written by
+// `ble-radar oui update` over the embedded one.
+func newVendors() (*oui.DB, error) {
+	db, err := oui.Load(oui.DefaultPath())
+	if err != nil {
+		return nil, fmt.Errorf("loading OUI database: %w", err)
+	}
+	return db, nil
+}
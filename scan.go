@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"ble-radar.klederson.com/internal/bluetooth"
+	"ble-radar.klederson.com/internal/calibration"
+	"ble-radar.klederson.com/internal/headless"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagScanDuration time.Duration
+	flagScanTypes    []string
+	flagScanOutput   string
+)
+
+func newScanCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scan",
+		Short: "Scan without the UI and stream discoveries as JSON Lines",
+		Long: `Runs the scanners headless and writes one JSON object per discovery,
+including the smoothed RSSI and estimated distance from the device store.
+
+Example:
+  ble-radar scan --demo --duration 10s --type ble | jq .name`,
+		RunE: runScan,
+	}
+
+	cmd.Flags().DurationVar(&flagScanDuration, "duration", 0, "Stop after this long (0 = until interrupted)")
+	cmd.Flags().StringSliceVar(&flagScanTypes, "type", nil, "Only emit these device types (ble, classic, wifi)")
+	cmd.Flags().StringVarP(&flagScanOutput, "output", "o", "-", "Output file (- for stdout)")
+
+	return cmd
+}
+
+func runScan(cmd *cobra.Command, args []string) error {
+	types := make(map[bluetooth.DeviceType]bool, len(flagScanTypes))
+	for _, t := range flagScanTypes {
+		dt, err := bluetooth.ParseDeviceType(t)
+		if err != nil {
+			return err
+		}
+		types[dt] = true
+	}
+
+	smoothing, err := newSmoothing()
+	if err != nil {
+		return err
+	}
+	identities, err := newIdentities()
+	if err != nil {
+		return err
+	}
+	vendors, err := newVendors()
+	if err != nil {
+		return err
+	}
+
+	profiles, err := calibration.Load(cfg.Calibration.Profile)
+	if err != nil {
+		return fmt.Errorf("loading calibration: %w", err)
+	}
+
+	scanners, err := bluetooth.NewScanners(scannerSelection(), scannerOptions())
+	if err != nil {
+		return err
+	}
+
+	rec, closeRec, err := openRecorder()
+	if err != nil {
+		return err
+	}
+	defer closeRec()
+
+	var w io.Writer = os.Stdout
+	var out *os.File
+	if flagScanOutput != "" && flagScanOutput != "-" {
+		f, err := os.Create(flagScanOutput)
+		if err != nil {
+			return err
+		}
+		defer f.Close() // Error paths only; a clean run closes it below
+		w, out = f, f
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err = headless.Run(ctx, headless.Options{
+		Config:      cfg,
+		Scanners:    scanners,
+		Duration:    flagScanDuration,
+		Types:       types,
+		Recorder:    rec,
+		Smoothing:   smoothing,
+		Identities:  identities,
+		Vendors:     vendors,
+		Calibration: profiles,
+	}, w)
+	if err != nil {
+		printPermissionHelp(err)
+		return err
+	}
+	if err := closeRec(); err != nil {
+		return err
+	}
+	if out != nil {
+		return out.Close()
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"ble-radar.klederson.com/internal/survey"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagSurveyPower float64
+	flagSurveyExp   float64
+)
+
+func newSurveyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "survey <file|mac>",
+		Short: "Re-run trilateration on a saved survey",
+		Long: `Loads a survey recorded with the T key (a file, or a MAC address looked up
+in survey.dir) and prints the observations and the estimated position with
+its 95% error ellipse. --measured-power and --path-loss-exp redo the
+analysis with a different path loss model than the one saved.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runSurvey,
+	}
+
+	cmd.Flags().Float64Var(&flagSurveyPower, "measured-power", 0, "Override the RSSI at 1 m (dBm)")
+	cmd.Flags().Float64Var(&flagSurveyExp, "path-loss-exp", 0, "Override the path loss exponent")
+
+	return cmd
+}
+
+func runSurvey(cmd *cobra.Command, args []string) error {
+	path := args[0]
+	if _, err := os.Stat(path); err != nil && !strings.ContainsAny(path, "/.") {
+		path = survey.PathFor(cfg.Survey.Dir, path)
+	}
+	s, err := survey.Load(path)
+	if err != nil {
+		return fmt.Errorf("loading survey: %w", err)
+	}
+	if cmd.Flags().Changed("measured-power") {
+		s.Model.MeasuredPower = flagSurveyPower
+	}
+	if cmd.Flags().Changed("path-loss-exp") {
+		if flagSurveyExp <= 0 {
+			return fmt.Errorf("--path-loss-exp must be positive, got %g", flagSurveyExp)
+		}
+		s.Model.Exponent = flagSurveyExp
+	}
+
+	fmt.Printf("%s %s  model %.1f dBm @1m, n=%.2f\n\n", s.MAC, s.Name, s.Model.MeasuredPower, s.Model.Exponent)
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "POSITION\tX\tY\tRSSI\tRANGE\tSAMPLES")
+	for _, o := range s.Observations {
+		fmt.Fprintf(tw, "%s\t%.2f\t%.2f\t%.1f\t%.2f\t%d\n",
+			o.Name, o.X, o.Y, o.Mean(), survey.Range(o, s.Model), len(o.Samples))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fix, err := s.Solve()
+	if err != nil {
+		return err
+	}
+	e := fix.Ellipse
+	fmt.Printf("\nEstimate   (%.2f, %.2f) m\n", fix.Position.X, fix.Position.Y)
+	fmt.Printf("95%% error  ±%.2f m x ±%.2f m, long axis %03.0f°\n", e.Major, e.Minor, e.Bearing()*180/math.Pi)
+	fmt.Printf("Misfit     %.2f m RMS over %d positions\n", fix.RMS, fix.N)
+	return nil
+}